@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var agingBuckets = []struct {
+	Label string
+	Upper time.Duration
+}{
+	{"0~1일", 24 * time.Hour},
+	{"1~3일", 3 * 24 * time.Hour},
+	{"3~7일", 7 * 24 * time.Hour},
+	{"7일 초과", 0},
+}
+
+func agingBucket(age time.Duration) int {
+	for n, b := range agingBuckets {
+		if b.Upper > 0 && age < b.Upper {
+			return n
+		}
+	}
+	return len(agingBuckets) - 1
+}
+
+func openTicketAgingEmbed(now time.Time) (*discordgo.MessageEmbed, error) {
+	cursor, err := app().Tickets.Find(context.TODO(), bson.M{"status": ticketStatusOpen}, options.Find().SetProjection(bson.M{"category": 1, "created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		return nil, err
+	}
+	embed := &discordgo.MessageEmbed{Title: "열린 티켓 경과 기간", Color: colorBlue}
+	if len(tickets) == 0 {
+		embed.Description = "현재 열린 티켓이 없습니다."
+		return embed, nil
+	}
+	counts := make(map[string][]int)
+	totals := make([]int, len(agingBuckets))
+	for _, t := range tickets {
+		if counts[t.Category] == nil {
+			counts[t.Category] = make([]int, len(agingBuckets))
+		}
+		n := agingBucket(now.Sub(t.CreatedAt))
+		counts[t.Category][n]++
+		totals[n]++
+	}
+	categories := make([]string, 0, len(counts))
+	for category := range counts {
+		categories = append(categories, category)
+	}
+	stale := len(agingBuckets) - 1
+	sort.Slice(categories, func(a, b int) bool {
+		if counts[categories[a]][stale] != counts[categories[b]][stale] {
+			return counts[categories[a]][stale] > counts[categories[b]][stale]
+		}
+		return categories[a] < categories[b]
+	})
+	line := func(name string, row []int) string {
+		cells := make([]string, len(row))
+		for n, count := range row {
+			cells[n] = fmt.Sprintf("%s %d", agingBuckets[n].Label, count)
+			if n == stale && count > 0 {
+				cells[n] = fmt.Sprintf("\u001b[1;31m%s\u001b[0m", cells[n])
+			}
+		}
+		return fmt.Sprintf("%s\n  %s", name, strings.Join(cells, " │ "))
+	}
+	lines := make([]string, 0, len(categories)+1)
+	for _, category := range categories {
+		lines = append(lines, line(category, counts[category]))
+	}
+	lines = append(lines, line(fmt.Sprintf("전체 %d건", len(tickets)), totals))
+	embed.Description = "```ansi\n" + strings.Join(lines, "\n") + "\n```"
+	if totals[stale] > 0 {
+		embed.Color = colorRed
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("7일 넘게 열려 있는 티켓이 %d건 있습니다. /지연티켓으로 확인하세요.", totals[stale])}
+	}
+	return embed, nil
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	externalAlertTimeout  = 5 * time.Second
+	externalAlertGuildKey = "guild"
+	externalSinkTelegram  = "telegram"
+	externalSinkWebhook   = "webhook"
+	externalSinkOff       = "off"
+	alertEventSLABreach   = "sla_breach"
+	alertEventUrgent      = "urgent_ticket"
+	alertEventTest        = "test"
+	urgentPriority        = "긴급"
+)
+
+type externalSink struct {
+	Kind   string `bson:"kind"`
+	Target string `bson:"target"`
+}
+
+type externalAlert struct {
+	Event   string    `json:"event"`
+	Title   string    `json:"title"`
+	Text    string    `json:"text"`
+	Ticket  string    `json:"ticket,omitempty"`
+	URL     string    `json:"url,omitempty"`
+	Time    time.Time `json:"time"`
+	Sandbox bool      `json:"-"`
+}
+
+var (
+	externalAlertHTTP = &http.Client{Timeout: externalAlertTimeout, Transport: &http.Transport{
+		DialContext: (&net.Dialer{Timeout: externalAlertTimeout, Control: rejectInternalDial}).DialContext,
+	}}
+	carrierGradeNAT   = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+	telegramChatID    = regexp.MustCompile(`^(-?\d+|@[A-Za-z0-9_]{5,})$`)
+	externalSinkKinds = []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "텔레그램", Value: externalSinkTelegram},
+		{Name: "웹훅 (카카오톡 브리지 등)", Value: externalSinkWebhook},
+		{Name: "해제", Value: externalSinkOff},
+	}
+	externalSinkLabels = map[string]string{externalSinkTelegram: "텔레그램", externalSinkWebhook: "웹훅"}
+)
+
+func alertsCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "알림설정",
+		Description: "SLA 초과와 긴급 티켓 알림을 받을 방법을 설정합니다.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "외부", Description: "텔레그램이나 웹훅으로 알림을 받습니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "kind", Description: "알림 방식", Required: true, Choices: externalSinkKinds},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "target", Description: "텔레그램 채팅 ID(숫자 또는 @채널) 또는 https:// 웹훅 주소", Required: false},
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "server", Description: "내 알림 대신 서버 전체 알림으로 설정 (관리자 전용)", Required: false},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "확인", Description: "현재 외부 알림 설정을 확인하고 시험 알림을 보냅니다."},
+		},
+	}
+}
+
+func validateExternalSink(kind, target string) bool {
+	switch kind {
+	case externalSinkTelegram:
+		return telegramChatID.MatchString(target)
+	case externalSinkWebhook:
+		u, err := url.Parse(target)
+		return err == nil && u.Scheme == "https" && u.Host != "" && resolvesPublic(u.Hostname())
+	}
+	return false
+}
+
+func isPublicAddress(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || carrierGradeNAT.Contains(ip))
+}
+
+func resolvesPublic(host string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), externalAlertTimeout)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return false
+	}
+	for _, addr := range addrs {
+		if !isPublicAddress(addr.IP) {
+			return false
+		}
+	}
+	return true
+}
+
+func rejectInternalDial(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !isPublicAddress(ip) {
+		return fmt.Errorf("refusing to connect to internal address %s", host)
+	}
+	return nil
+}
+
+func describeExternalSink(sink externalSink) string {
+	if sink.Kind == externalSinkWebhook {
+		if u, err := url.Parse(sink.Target); err == nil {
+			return fmt.Sprintf("%s · %s", externalSinkLabels[sink.Kind], u.Host)
+		}
+	}
+	return fmt.Sprintf("%s · %s", externalSinkLabels[sink.Kind], sink.Target)
+}
+
+func handleAlertSettings(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	userID := interactionUser(i).ID
+	if sub.Name == "확인" {
+		sinks := getConfig().ExternalAlerts
+		lines := []string{"서버: 설정 안 됨", "내 알림: 설정 안 됨"}
+		if sink, ok := sinks[externalAlertGuildKey]; ok {
+			lines[0] = "서버: " + describeExternalSink(sink)
+		}
+		if sink, ok := sinks[userID]; ok {
+			lines[1] = "내 알림: " + describeExternalSink(sink)
+			sendExternalAlert(externalAlert{Event: alertEventTest, Title: "시험 알림", Text: "외부 알림이 정상적으로 연결되었습니다.", Time: time.Now()}, userID)
+			lines = append(lines, "", "내 알림으로 시험 알림을 보냈습니다.")
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "외부 알림 설정", Description: strings.Join(lines, "\n"), Color: colorBlue}}}})
+		return
+	}
+	var kind, target string
+	server := false
+	for _, opt := range sub.Options {
+		switch opt.Name {
+		case "kind":
+			kind = opt.StringValue()
+		case "target":
+			target = strings.TrimSpace(opt.StringValue())
+		case "server":
+			server = opt.BoolValue()
+		}
+	}
+	key, scope := userID, "내 알림"
+	if server {
+		if !isAdministrator(i) {
+			respondError(s, i, errAdminOnly, nil)
+			return
+		}
+		key, scope = externalAlertGuildKey, "서버 알림"
+	}
+	if kind != externalSinkOff && !validateExternalSink(kind, target) {
+		respondError(s, i, errInvalidAlertTarget, nil, target)
+		return
+	}
+	if kind == externalSinkTelegram && os.Getenv("TELEGRAM_BOT_TOKEN") == "" {
+		respondError(s, i, errTelegramTokenUnset, nil)
+		return
+	}
+	err := updateConfig(func(cfg *guildConfig) {
+		if kind == externalSinkOff {
+			delete(cfg.ExternalAlerts, key)
+		} else {
+			cfg.ExternalAlerts[key] = externalSink{Kind: kind, Target: target}
+		}
+	})
+	if err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	summary := fmt.Sprintf("%s을(를) 해제했습니다.", scope)
+	if kind != externalSinkOff {
+		summary = fmt.Sprintf("%s을(를) %s(으)로 보냅니다. SLA 초과와 긴급 티켓이 발생하면 알림이 전송됩니다.", scope, describeExternalSink(externalSink{Kind: kind, Target: target}))
+	}
+	log.Printf("%s updated external alert sink '%s' (%s).", userID, key, kind)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "외부 알림 변경", Description: summary, Color: colorGreen}}}})
+}
+
+func ticketAlert(event, title, text string, t *ticket) externalAlert {
+	return externalAlert{
+		Event:   event,
+		Title:   title,
+		Text:    text,
+		Ticket:  t.Code,
+		URL:     fmt.Sprintf("https://discord.com/channels/%s/%s", t.GuildID, t.ChannelID),
+		Time:    time.Now(),
+		Sandbox: t.Sandbox,
+	}
+}
+
+func alertUrgentTicket(t *ticket, text string) {
+	sendExternalAlert(ticketAlert(alertEventUrgent, "긴급 티켓", text, t), t.AssigneeID)
+}
+
+func sendExternalAlert(alert externalAlert, userIDs ...string) {
+	sinks := getConfig().ExternalAlerts
+	keys := []string{externalAlertGuildKey}
+	if alert.Event == alertEventTest {
+		keys = nil
+	}
+	seen := make(map[externalSink]bool)
+	for _, key := range append(keys, userIDs...) {
+		sink, ok := sinks[key]
+		if key == "" || !ok || seen[sink] {
+			continue
+		}
+		seen[sink] = true
+		go deliverExternalAlert(sink, alert)
+	}
+}
+
+func deliverExternalAlert(sink externalSink, alert externalAlert) {
+	if alert.Sandbox {
+		log.Printf("Sandbox mode: mocked %s alert via %s: %s", alert.Event, sink.Kind, alert.Text)
+		incCounter("potatobot_external_alerts_total", metricLabel("sink", sink.Kind)+","+metricLabel("result", "mocked"))
+		return
+	}
+	err := postExternalAlert(sink, alert)
+	result := "ok"
+	if err != nil {
+		result = "error"
+		log.Printf("Could not deliver %s alert via %s: %v", alert.Event, sink.Kind, err)
+	}
+	incCounter("potatobot_external_alerts_total", metricLabel("sink", sink.Kind)+","+metricLabel("result", result))
+}
+
+func postExternalAlert(sink externalSink, alert externalAlert) error {
+	endpoint := sink.Target
+	var payload interface{} = alert
+	if sink.Kind == externalSinkTelegram {
+		token := os.Getenv("TELEGRAM_BOT_TOKEN")
+		if token == "" {
+			return fmt.Errorf("TELEGRAM_BOT_TOKEN is not set")
+		}
+		text := fmt.Sprintf("[%s] %s", alert.Title, alert.Text)
+		if alert.URL != "" {
+			text += "\n" + alert.URL
+		}
+		endpoint = fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", token)
+		payload = map[string]interface{}{"chat_id": sink.Target, "text": text, "disable_web_page_preview": true}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := externalAlertHTTP.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		if sink.Kind == externalSinkTelegram {
+			return fmt.Errorf("telegram request failed")
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	appealButtonID       = "ticket_appeal"
+	appealModalID        = "ticket_appeal_submit"
+	appealDecisionPrefix = "appeal_decision:"
+	appealAccept         = "accept"
+	appealReject         = "reject"
+)
+
+func handleAppealButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	b, err := activeTicketBlock(i.Member.User.ID)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	if b == nil {
+		respondError(s, i, errNotBlocked, nil, i.Member.User.ID)
+		return
+	}
+	if !b.AppealedAt.IsZero() {
+		respondError(s, i, errAppealExists, nil)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseModal, Data: &discordgo.InteractionResponseData{
+		CustomID: appealModalID,
+		Title:    "접수 제한 이의신청",
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.TextInput{CustomID: "statement", Label: "이의신청 사유", Style: discordgo.TextInputParagraph, Placeholder: "제한이 부당하다고 생각하는 이유를 적어주세요. 이의신청은 한 번만 할 수 있습니다.", Required: true, MinLength: 10, MaxLength: 1000}}},
+		},
+	}})
+}
+
+func handleAppealSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID := i.Member.User.ID
+	b, err := activeTicketBlock(userID)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	if b == nil {
+		respondError(s, i, errNotBlocked, nil, userID)
+		return
+	}
+	statement := ""
+	for _, row := range i.ModalSubmitData().Components {
+		for _, comp := range row.(*discordgo.ActionsRow).Components {
+			if input, ok := comp.(*discordgo.TextInput); ok && input.CustomID == "statement" {
+				statement = strings.TrimSpace(input.Value)
+			}
+		}
+	}
+	now := time.Now()
+	result, err := app().Blocks.UpdateOne(context.TODO(), bson.M{"_id": userID, "appealed_at": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"appealed_at": now}})
+	if err != nil {
+		respondError(s, i, errBlockSaveFailed, err)
+		return
+	}
+	if result.ModifiedCount == 0 {
+		respondError(s, i, errAppealExists, nil)
+		return
+	}
+	ch, err := withRetry("channel create", func() (*discordgo.Channel, error) {
+		return s.GuildChannelCreateComplex(i.GuildID, discordgo.GuildChannelCreateData{
+			Name:     "이의신청-" + i.Member.User.Username,
+			Type:     discordgo.ChannelTypeGuildText,
+			Topic:    fmt.Sprintf("User ID: %s | 접수 제한 이의신청", userID),
+			ParentID: getConfig().OpenCategoryID,
+			PermissionOverwrites: []*discordgo.PermissionOverwrite{
+				{ID: i.GuildID, Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionViewChannel},
+				{ID: userID, Type: discordgo.PermissionOverwriteTypeMember, Allow: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages},
+			},
+		})
+	})
+	if err != nil {
+		app().Blocks.UpdateOne(context.TODO(), bson.M{"_id": userID}, bson.M{"$unset": bson.M{"appealed_at": ""}})
+		respondError(s, i, errChannelCreateFailed, err)
+		return
+	}
+	if _, err := app().Blocks.UpdateOne(context.TODO(), bson.M{"_id": userID}, bson.M{"$set": bson.M{"appeal_channel_id": ch.ID}}); err != nil {
+		log.Printf("Could not record appeal channel for %s: %v", userID, err)
+	}
+	log.Printf("User %s filed a block appeal in %s.", userID, ch.ID)
+	_, err = s.ChannelMessageSendComplex(ch.ID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       "접수 제한 이의신청",
+			Description: fmt.Sprintf("<@%s> 님의 이의신청입니다. 이 채널은 민원인과 관리자만 볼 수 있으며, 관리자의 결정은 한 번만 내려집니다.", userID),
+			Color:       colorYellow,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "차단 사유", Value: b.Reason, Inline: false},
+				{Name: "처리자", Value: fmt.Sprintf("<@%s>", b.ModeratorID), Inline: true},
+				{Name: "기간", Value: b.expiryLabel(), Inline: true},
+				{Name: "이의신청 사유", Value: statement, Inline: false},
+			},
+			Timestamp: now.In(kstLocation).Format(time.RFC3339),
+		}},
+		Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "차단 해제", Style: discordgo.SuccessButton, CustomID: appealDecisionPrefix + appealAccept},
+			discordgo.Button{Label: "기각", Style: discordgo.DangerButton, CustomID: appealDecisionPrefix + appealReject},
+		}}},
+	})
+	if err != nil {
+		log.Printf("Could not post appeal details in %s: %v", ch.ID, err)
+	}
+	s.ChannelMessageSendEmbed(getConfig().LogChannelID, &discordgo.MessageEmbed{Title: "이의신청 접수", Description: fmt.Sprintf("<@%s> 님이 접수 제한에 이의신청했습니다: <#%s>", userID, ch.ID), Color: colorYellow})
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "이의신청 접수", Description: fmt.Sprintf("<#%s> 채널에서 관리자가 검토합니다.", ch.ID), Color: colorGreen}}}})
+}
+
+func handleAppealDecision(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var b ticketBlock
+	if err := app().Blocks.FindOne(context.TODO(), bson.M{"appeal_channel_id": i.ChannelID}).Decode(&b); err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("Could not look up appeal for channel %s: %v", i.ChannelID, err)
+		}
+		respondError(s, i, errAppealNotFound, nil)
+		return
+	}
+	decision := strings.TrimPrefix(i.MessageComponentData().CustomID, appealDecisionPrefix)
+	var err error
+	var embed *discordgo.MessageEmbed
+	if decision == appealAccept {
+		_, err = app().Blocks.DeleteOne(context.TODO(), bson.M{"_id": b.UserID})
+		embed = &discordgo.MessageEmbed{Title: "이의신청 인용", Description: fmt.Sprintf("<@%s> 님이 이의신청을 받아들여 접수 제한을 해제했습니다. <@%s> 님은 다시 민원을 접수할 수 있습니다.", i.Member.User.ID, b.UserID), Color: colorGreen}
+	} else {
+		_, err = app().Blocks.UpdateOne(context.TODO(), bson.M{"_id": b.UserID}, bson.M{"$unset": bson.M{"appeal_channel_id": ""}})
+		embed = &discordgo.MessageEmbed{Title: "이의신청 기각", Description: fmt.Sprintf("<@%s> 님이 이의신청을 기각했습니다. 접수 제한은 %s 유지됩니다.", i.Member.User.ID, b.expiryLabel()), Color: colorRed}
+	}
+	if err != nil {
+		respondError(s, i, errBlockSaveFailed, err)
+		return
+	}
+	log.Printf("Block appeal of %s decided as '%s' by %s.", b.UserID, decision, i.Member.User.ID)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{Embeds: i.Message.Embeds, Components: []discordgo.MessageComponent{}}})
+	s.ChannelMessageSendEmbed(i.ChannelID, embed)
+	if err := retryDiscord("permission set", func() error {
+		return s.ChannelPermissionSet(i.ChannelID, b.UserID, discordgo.PermissionOverwriteTypeMember, discordgo.PermissionViewChannel, discordgo.PermissionSendMessages)
+	}); err != nil {
+		log.Printf("Could not lock appeal channel %s: %v", i.ChannelID, err)
+	}
+	if categoryID := getConfig().ClosedCategoryID; categoryID != "" {
+		if _, err := withRetry("channel edit", func() (*discordgo.Channel, error) {
+			return s.ChannelEdit(i.ChannelID, &discordgo.ChannelEdit{ParentID: categoryID})
+		}); err != nil {
+			log.Printf("Could not move appeal channel %s to the closed category: %v", i.ChannelID, err)
+		}
+	}
+	s.ChannelMessageSendEmbed(getConfig().LogChannelID, &discordgo.MessageEmbed{Title: embed.Title, Description: fmt.Sprintf("<@%s> 님의 이의신청: <#%s>\n%s", b.UserID, i.ChannelID, embed.Description), Color: embed.Color})
+}
@@ -0,0 +1,419 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type messageRevision struct {
+	Raw      string    `bson:"raw"`
+	EditedAt time.Time `bson:"edited_at"`
+}
+
+type archivedReaction struct {
+	Name     string `bson:"name"`
+	ID       string `bson:"id,omitempty"`
+	Animated bool   `bson:"animated,omitempty"`
+	Count    int    `bson:"count"`
+}
+
+type archivedMessage struct {
+	MessageID string                      `bson:"_id"`
+	ChannelID string                      `bson:"channel_id"`
+	Raw       string                      `bson:"raw"`
+	SentAt    time.Time                   `bson:"sent_at"`
+	EditedAt  time.Time                   `bson:"edited_at,omitempty"`
+	DeletedAt time.Time                   `bson:"deleted_at,omitempty"`
+	Revisions []messageRevision           `bson:"revisions,omitempty"`
+	Reactions map[string]archivedReaction `bson:"reactions,omitempty"`
+}
+
+func ensureMessageArchiveIndexes(ctx context.Context) error {
+	err := createIndexes(ctx, app().Messages, mongo.IndexModel{Keys: bson.D{{Key: "channel_id", Value: 1}, {Key: "sent_at", Value: 1}}})
+	if err != nil {
+		return fmt.Errorf("could not create message archive indexes: %w", err)
+	}
+	return nil
+}
+
+func archivedTicketChannel(s *discordgo.Session, channelID string) (*discordgo.Channel, bool) {
+	if app().Messages == nil {
+		return nil, false
+	}
+	ch, err := s.State.Channel(channelID)
+	if err != nil || !isTicketTopic(ticketCategory(ch)) {
+		return nil, false
+	}
+	return ch, true
+}
+
+func encodeArchivedMessage(ch *discordgo.Channel, m *discordgo.Message) (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	if featuresFor(ticketCategory(ch)).Encrypted {
+		return seal(data)
+	}
+	return string(data), nil
+}
+
+func decodeArchivedMessage(raw string) (*discordgo.Message, error) {
+	data := []byte(raw)
+	if strings.HasPrefix(raw, sealedPrefix) {
+		var err error
+		if data, err = unseal(raw); err != nil {
+			return nil, err
+		}
+	}
+	var m discordgo.Message
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func archiveMessage(ch *discordgo.Channel, m *discordgo.Message) error {
+	raw, err := encodeArchivedMessage(ch, m)
+	if err != nil {
+		return err
+	}
+	_, err = app().Messages.UpdateOne(context.TODO(), bson.M{"_id": m.ID}, bson.M{
+		"$setOnInsert": bson.M{"channel_id": ch.ID, "raw": raw, "sent_at": m.Timestamp},
+	}, options.Update().SetUpsert(true))
+	return err
+}
+
+func archiveMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	ch, ok := archivedTicketChannel(s, m.ChannelID)
+	if !ok {
+		return
+	}
+	if err := archiveMessage(ch, m.Message); err != nil {
+		log.Printf("Could not archive message %s in '%s': %v", m.ID, ch.Name, err)
+	}
+}
+
+func archiveMessageUpdate(s *discordgo.Session, m *discordgo.MessageUpdate) {
+	ch, ok := archivedTicketChannel(s, m.ChannelID)
+	if !ok || m.Author == nil {
+		return
+	}
+	var previous archivedMessage
+	err := app().Messages.FindOne(context.TODO(), bson.M{"_id": m.ID}).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		if err := archiveMessage(ch, m.Message); err != nil {
+			log.Printf("Could not archive edited message %s in '%s': %v", m.ID, ch.Name, err)
+		}
+		return
+	} else if err != nil {
+		log.Printf("Could not load archived message %s: %v", m.ID, err)
+		return
+	}
+	raw, err := encodeArchivedMessage(ch, m.Message)
+	if err != nil {
+		log.Printf("Could not encode edited message %s in '%s': %v", m.ID, ch.Name, err)
+		return
+	}
+	update := bson.M{"$set": bson.M{"raw": raw}}
+	if m.EditedTimestamp != nil {
+		update["$set"].(bson.M)["edited_at"] = *m.EditedTimestamp
+		if old, err := decodeArchivedMessage(previous.Raw); err == nil && old.Content != m.Content {
+			update["$push"] = bson.M{"revisions": messageRevision{Raw: previous.Raw, EditedAt: *m.EditedTimestamp}}
+		}
+	}
+	if _, err := app().Messages.UpdateOne(context.TODO(), bson.M{"_id": m.ID}, update); err != nil {
+		log.Printf("Could not archive edit of message %s in '%s': %v", m.ID, ch.Name, err)
+	}
+}
+
+func markMessagesDeleted(s *discordgo.Session, channelID string, ids []string) {
+	ch, ok := archivedTicketChannel(s, channelID)
+	if !ok {
+		return
+	}
+	filter := bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$exists": false}}
+	if _, err := app().Messages.UpdateMany(context.TODO(), filter, bson.M{"$set": bson.M{"deleted_at": time.Now()}}); err != nil {
+		log.Printf("Could not archive deletion of %d messages in '%s': %v", len(ids), ch.Name, err)
+	}
+}
+
+func archiveMessageDelete(s *discordgo.Session, m *discordgo.MessageDelete) {
+	markMessagesDeleted(s, m.ChannelID, []string{m.ID})
+}
+
+func archiveMessageDeleteBulk(s *discordgo.Session, m *discordgo.MessageDeleteBulk) {
+	markMessagesDeleted(s, m.ChannelID, m.Messages)
+}
+
+func archiveReaction(s *discordgo.Session, channelID, messageID string, emoji discordgo.Emoji, delta int) {
+	ch, ok := archivedTicketChannel(s, channelID)
+	if !ok {
+		return
+	}
+	key := "reactions." + emoji.Name
+	if emoji.ID != "" {
+		key += ":" + emoji.ID
+	}
+	update := bson.M{
+		"$set": bson.M{key + ".name": emoji.Name, key + ".id": emoji.ID, key + ".animated": emoji.Animated},
+		"$inc": bson.M{key + ".count": delta},
+	}
+	if _, err := app().Messages.UpdateOne(context.TODO(), bson.M{"_id": messageID}, update); err != nil {
+		log.Printf("Could not archive reaction on message %s in '%s': %v", messageID, ch.Name, err)
+	}
+}
+
+func archiveReactionAdd(s *discordgo.Session, r *discordgo.MessageReactionAdd) {
+	archiveReaction(s, r.ChannelID, r.MessageID, r.Emoji, 1)
+}
+
+func archiveReactionRemove(s *discordgo.Session, r *discordgo.MessageReactionRemove) {
+	archiveReaction(s, r.ChannelID, r.MessageID, r.Emoji, -1)
+}
+
+func archiveReactionRemoveAll(s *discordgo.Session, r *discordgo.MessageReactionRemoveAll) {
+	ch, ok := archivedTicketChannel(s, r.ChannelID)
+	if !ok {
+		return
+	}
+	if _, err := app().Messages.UpdateOne(context.TODO(), bson.M{"_id": r.MessageID}, bson.M{"$unset": bson.M{"reactions": ""}}); err != nil {
+		log.Printf("Could not clear archived reactions on message %s in '%s': %v", r.MessageID, ch.Name, err)
+	}
+}
+
+func decodeArchivedEntry(a archivedMessage) (*discordgo.Message, error) {
+	m, err := decodeArchivedMessage(a.Raw)
+	if err != nil || len(a.Reactions) == 0 {
+		return m, err
+	}
+	m.Reactions = nil
+	for _, r := range a.Reactions {
+		if r.Count > 0 {
+			m.Reactions = append(m.Reactions, &discordgo.MessageReactions{Count: r.Count, Emoji: &discordgo.Emoji{Name: r.Name, ID: r.ID, Animated: r.Animated}})
+		}
+	}
+	sort.Slice(m.Reactions, func(i, j int) bool {
+		if m.Reactions[i].Count != m.Reactions[j].Count {
+			return m.Reactions[i].Count > m.Reactions[j].Count
+		}
+		return m.Reactions[i].Emoji.Name < m.Reactions[j].Emoji.Name
+	})
+	return m, nil
+}
+
+func reactionsHTML(reactions []*discordgo.MessageReactions) string {
+	var sb strings.Builder
+	sb.WriteString(`<div class="reactions">`)
+	for _, r := range reactions {
+		if r.Emoji == nil || r.Count == 0 {
+			continue
+		}
+		emoji := html.EscapeString(r.Emoji.Name)
+		if r.Emoji.ID != "" {
+			ext := "png"
+			if r.Emoji.Animated {
+				ext = "gif"
+			}
+			emoji = fmt.Sprintf(`<img %s alt=":%s:">`, inlineImage(fmt.Sprintf("https://cdn.discordapp.com/emojis/%s.%s", r.Emoji.ID, ext)), html.EscapeString(r.Emoji.Name))
+		}
+		sb.WriteString(fmt.Sprintf(`<span class="reaction">%s %d</span>`, emoji, r.Count))
+	}
+	sb.WriteString(`</div>`)
+	return sb.String()
+}
+
+func loadArchivedMessages(channelID string) ([]archivedMessage, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "sent_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := app().Messages.Find(context.TODO(), bson.M{"channel_id": channelID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var archived []archivedMessage
+	if err := cursor.All(context.TODO(), &archived); err != nil {
+		return nil, err
+	}
+	return archived, nil
+}
+
+func ticketMessages(s *discordgo.Session, channel *discordgo.Channel) ([]*discordgo.Message, error) {
+	if app().Messages == nil {
+		return fetchAllMessages(s, channel.ID)
+	}
+	if t := ticketForChannel(channel); t == nil || !t.LiveArchive {
+		return fetchAllMessages(s, channel.ID)
+	}
+	messages, err := archivedLiveMessages(channel.ID)
+	if err != nil || len(messages) == 0 {
+		log.Printf("Message archive for '%s' is unavailable, fetching from Discord instead: %v", channel.Name, err)
+		return fetchAllMessages(s, channel.ID)
+	}
+	return messages, nil
+}
+
+func archivedLiveMessages(channelID string) ([]*discordgo.Message, error) {
+	archived, err := loadArchivedMessages(channelID)
+	if err != nil {
+		return nil, err
+	}
+	var messages []*discordgo.Message
+	for _, a := range archived {
+		if !a.DeletedAt.IsZero() {
+			continue
+		}
+		m, err := decodeArchivedEntry(a)
+		if err != nil {
+			log.Printf("Could not decode archived message %s in %s: %v", a.MessageID, channelID, err)
+			continue
+		}
+		messages = append(messages, m)
+	}
+	return messages, nil
+}
+
+func backfillMessageArchive(s *discordgo.Session) {
+	if app().Messages == nil || app().Tickets == nil {
+		return
+	}
+	cursor, err := app().Tickets.Find(context.TODO(), bson.M{"status": ticketStatusOpen, "live_archive": true})
+	if err != nil {
+		log.Printf("Could not list tickets for message archive backfill: %v", err)
+		return
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		log.Printf("Could not list tickets for message archive backfill: %v", err)
+		return
+	}
+	backfilled := 0
+	for _, t := range tickets {
+		ch, err := s.Channel(t.ChannelID)
+		if err != nil {
+			continue
+		}
+		var first, last archivedMessage
+		before, after := "", ""
+		if err := app().Messages.FindOne(context.TODO(), bson.M{"channel_id": t.ChannelID}, options.FindOne().SetSort(bson.D{{Key: "sent_at", Value: 1}})).Decode(&first); err == nil {
+			before = first.MessageID
+		}
+		if err := app().Messages.FindOne(context.TODO(), bson.M{"channel_id": t.ChannelID}, options.FindOne().SetSort(bson.D{{Key: "sent_at", Value: -1}})).Decode(&last); err == nil {
+			after = last.MessageID
+		}
+		for {
+			page, err := s.ChannelMessages(t.ChannelID, 100, before, "", "")
+			if err != nil {
+				log.Printf("Could not backfill message archive for '%s': %v", t.Name(), err)
+				break
+			}
+			for _, m := range page {
+				if err := archiveMessage(ch, m); err == nil {
+					backfilled++
+				}
+			}
+			if len(page) < 100 {
+				break
+			}
+			before = page[len(page)-1].ID
+		}
+		if after == "" {
+			continue
+		}
+		for {
+			page, err := s.ChannelMessages(t.ChannelID, 100, "", after, "")
+			if err != nil {
+				log.Printf("Could not backfill message archive for '%s': %v", t.Name(), err)
+				break
+			}
+			if len(page) == 0 {
+				break
+			}
+			for _, m := range page {
+				if err := archiveMessage(ch, m); err == nil {
+					backfilled++
+				}
+			}
+			after = page[0].ID
+		}
+	}
+	if backfilled > 0 {
+		log.Printf("Backfilled %d messages into the message archive.", backfilled)
+	}
+}
+
+type messageRevisionView struct {
+	Content    string
+	ReplacedAt time.Time
+}
+
+type messageHistory struct {
+	Revisions []messageRevisionView
+	DeletedAt time.Time
+}
+
+func withMessageHistory(channel *discordgo.Channel, messages []*discordgo.Message) ([]*discordgo.Message, map[string]messageHistory) {
+	if app().Messages == nil {
+		return messages, nil
+	}
+	archived, err := loadArchivedMessages(channel.ID)
+	if err != nil {
+		log.Printf("Could not load message history for '%s': %v", channel.Name, err)
+		return messages, nil
+	}
+	present := make(map[string]bool, len(messages))
+	for _, m := range messages {
+		present[m.ID] = true
+	}
+	history := make(map[string]messageHistory)
+	merged := messages
+	for _, a := range archived {
+		if len(a.Revisions) == 0 && a.DeletedAt.IsZero() {
+			continue
+		}
+		h := messageHistory{DeletedAt: a.DeletedAt}
+		for _, rev := range a.Revisions {
+			if old, err := decodeArchivedMessage(rev.Raw); err == nil {
+				h.Revisions = append(h.Revisions, messageRevisionView{Content: old.Content, ReplacedAt: rev.EditedAt})
+			}
+		}
+		history[a.MessageID] = h
+		if !a.DeletedAt.IsZero() && !present[a.MessageID] {
+			if m, err := decodeArchivedEntry(a); err == nil {
+				if len(merged) == len(messages) {
+					merged = append([]*discordgo.Message(nil), messages...)
+				}
+				merged = append(merged, m)
+			}
+		}
+	}
+	if len(merged) != len(messages) {
+		sort.SliceStable(merged, func(i, j int) bool { return merged[i].Timestamp.Before(merged[j].Timestamp) })
+	}
+	return merged, history
+}
+
+func messageHistoryHTML(body string, msg *discordgo.Message, h messageHistory, anonymousID string) string {
+	if len(h.Revisions) > 0 {
+		var revisions strings.Builder
+		for _, rev := range h.Revisions {
+			revisions.WriteString(fmt.Sprintf(`<div class="revision"><span class="timestamp">~ %s</span> %s</div>`, rev.ReplacedAt.In(kstLocation).Format("2006-01-02 15:04:05"), mentionsHTML(rev.Content, anonymousID)))
+		}
+		body += fmt.Sprintf(`<details class="revisions"><summary>수정됨 (이전 내용 %d개)</summary>%s</details>`, len(h.Revisions), revisions.String())
+	} else if msg.EditedTimestamp != nil {
+		body += fmt.Sprintf(`<div class="message-note">수정됨 · %s</div>`, msg.EditedTimestamp.In(kstLocation).Format("2006-01-02 15:04:05"))
+	}
+	if !h.DeletedAt.IsZero() {
+		body = fmt.Sprintf(`<div class="deleted-message">%s</div><div class="message-note">🗑️ %s 삭제됨</div>`, body, h.DeletedAt.In(kstLocation).Format("2006-01-02 15:04:05"))
+	}
+	return body
+}
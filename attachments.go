@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultAttachmentPrompt    = "증빙서류를 첨부해주세요."
+	defaultAttachmentMaxSizeMB = 8
+)
+
+type attachmentRequirement struct {
+	Prompt    string   `bson:"prompt"`
+	MinFiles  int      `bson:"min_files"`
+	Types     []string `bson:"types,omitempty"`
+	MaxSizeMB int      `bson:"max_size_mb"`
+}
+
+type ticketAttachment struct {
+	Filename   string    `bson:"filename"`
+	URL        string    `bson:"url"`
+	Size       int       `bson:"size"`
+	UploadedAt time.Time `bson:"uploaded_at"`
+}
+
+func parseAttachmentTypes(raw string) []string {
+	var types []string
+	for _, part := range strings.FieldsFunc(strings.ToLower(raw), func(r rune) bool { return r == ',' || r == ' ' }) {
+		if part = strings.TrimPrefix(part, "."); part != "" {
+			types = append(types, part)
+		}
+	}
+	return types
+}
+
+func (r attachmentRequirement) describe() string {
+	types := "모든 형식"
+	if len(r.Types) > 0 {
+		types = strings.Join(r.Types, ", ")
+	}
+	return fmt.Sprintf("%d개 이상 · %s · 파일당 %dMB 이하", r.MinFiles, types, r.MaxSizeMB)
+}
+
+func attachmentSummary(cfg guildConfig) string {
+	var lines []string
+	for _, option := range ticketOptions() {
+		r, ok := cfg.CategoryAttachments[option.Value]
+		if !ok {
+			lines = append(lines, fmt.Sprintf("%s: 사용 안 함", option.Value))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s", option.Value, r.describe()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (r attachmentRequirement) validate(a *discordgo.MessageAttachment) string {
+	if len(r.Types) > 0 {
+		ext := strings.TrimPrefix(strings.ToLower(path.Ext(a.Filename)), ".")
+		if !containsSkill(r.Types, ext) {
+			return fmt.Sprintf("`%s`: 허용되지 않는 형식입니다.", a.Filename)
+		}
+	}
+	if a.Size > r.MaxSizeMB<<20 {
+		return fmt.Sprintf("`%s`: %.1fMB로 최대 크기(%dMB)를 넘습니다.", a.Filename, float64(a.Size)/(1<<20), r.MaxSizeMB)
+	}
+	return ""
+}
+
+func postAttachmentPrompt(s *discordgo.Session, t *ticket, r attachmentRequirement) {
+	_, err := s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("<@%s>", t.OwnerID),
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       "📎 서류 첨부",
+			Description: r.Prompt + "\n이 채널에 파일을 올려주시면 확인 후 담당자를 호출합니다.",
+			Color:       colorYellow,
+			Fields:      []*discordgo.MessageEmbedField{{Name: "제출 조건", Value: r.describe(), Inline: false}},
+		}},
+	})
+	if err != nil {
+		log.Printf("Could not post attachment prompt in '%s': %v", t.Name(), err)
+	}
+}
+
+func handleIntakeAttachments(s *discordgo.Session, m *discordgo.MessageCreate, t *ticket) {
+	if !t.AttachmentsPending || m.Author.ID != t.OwnerID || len(m.Attachments) == 0 {
+		return
+	}
+	r, ok := getConfig().CategoryAttachments[t.Category]
+	if !ok {
+		r = attachmentRequirement{MaxSizeMB: defaultAttachmentMaxSizeMB}
+	}
+	var accepted []ticketAttachment
+	var rejected []string
+	for _, a := range m.Attachments {
+		if problem := r.validate(a); problem != "" {
+			rejected = append(rejected, problem)
+			continue
+		}
+		accepted = append(accepted, ticketAttachment{Filename: a.Filename, URL: a.URL, Size: a.Size, UploadedAt: m.Timestamp})
+	}
+	t.Attachments = append(t.Attachments, accepted...)
+	complete := len(t.Attachments) >= r.MinFiles
+	update := bson.M{}
+	if len(accepted) > 0 {
+		update["$push"] = bson.M{"attachments": bson.M{"$each": accepted}}
+	}
+	if complete {
+		t.AttachmentsPending = false
+		t.IntakeCompletedAt = time.Now()
+		update["$set"] = bson.M{"attachments_pending": false, "intake_completed_at": t.IntakeCompletedAt}
+	}
+	if len(update) > 0 {
+		if _, err := app().Tickets.UpdateOne(context.TODO(), bson.M{"channel_id": t.ChannelID}, update); err != nil {
+			log.Printf("Could not record attachments for '%s': %v", t.Name(), err)
+		}
+	}
+	if len(rejected) > 0 {
+		s.ChannelMessageSendEmbedReply(m.ChannelID, &discordgo.MessageEmbed{Title: "첨부 파일 확인", Description: strings.Join(rejected, "\n") + "\n\n제출 조건: " + r.describe(), Color: colorRed}, m.Reference())
+	}
+	if !complete {
+		if len(accepted) > 0 {
+			s.ChannelMessageSendEmbed(m.ChannelID, &discordgo.MessageEmbed{Title: "첨부 파일 확인", Description: fmt.Sprintf("%d개 중 %d개를 받았습니다. 나머지 파일도 올려주세요.", r.MinFiles, len(t.Attachments)), Color: colorYellow})
+		}
+		return
+	}
+	content := fmt.Sprintf("<@%s> 님, 민원인이 서류를 제출했습니다.", t.AssigneeID)
+	if t.AssigneeID == "" {
+		supportRoleID, ok := getConfig().CategorySupportRoles[t.Category]
+		if !ok {
+			supportRoleID = getConfig().DefaultSupportRoleID
+		}
+		content = supportPingContent(s, t.Category, supportRoleID)
+	}
+	s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Content: content,
+		Embeds:  []*discordgo.MessageEmbed{{Title: "접수 완료", Description: fmt.Sprintf("서류 %d개가 제출되어 접수가 완료되었습니다. 곧 담당자가 확인할 예정입니다.", len(t.Attachments)), Color: colorGreen}},
+	})
+	log.Printf("Intake attachments for '%s' completed with %d files.", t.Name(), len(t.Attachments))
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	inactivityCheckInterval = 10 * time.Minute
+	inactivityCloseCode     = "무응답"
+)
+
+type inactivityPolicy struct {
+	WarnAfter  time.Duration `bson:"warn_after"`
+	CloseAfter time.Duration `bson:"close_after"`
+}
+
+func (p inactivityPolicy) enabled() bool {
+	return p.WarnAfter > 0 && p.CloseAfter > 0
+}
+
+func inactivityPolicyFor(category string) inactivityPolicy {
+	return getConfig().CategoryInactivity[category]
+}
+
+func inactivitySummary(cfg guildConfig) string {
+	var lines []string
+	for _, option := range ticketOptions() {
+		p := cfg.CategoryInactivity[option.Value]
+		if !p.enabled() {
+			lines = append(lines, fmt.Sprintf("%s: 사용 안 함", option.Value))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s 무응답 시 경고, 이후 %s 뒤 종료", option.Value, formatSLADuration(p.WarnAfter), formatSLADuration(p.CloseAfter)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func checkInactiveTickets(s *discordgo.Session) error {
+	cursor, err := app().Tickets.Find(context.TODO(), bson.M{"status": ticketStatusOpen})
+	if err != nil {
+		return err
+	}
+	var open []ticket
+	if err := cursor.All(context.TODO(), &open); err != nil {
+		return err
+	}
+	for n := range open {
+		t := &open[n]
+		policy := inactivityPolicyFor(t.Category)
+		if !policy.enabled() {
+			continue
+		}
+		ch, err := s.Channel(t.ChannelID)
+		if err != nil {
+			continue
+		}
+		if err := checkInactiveTicket(s, t, ch, policy); err != nil {
+			log.Printf("Could not process inactivity for ticket '%s': %v", t.Name(), err)
+		}
+	}
+	return nil
+}
+
+func lastActivity(t *ticket, ch *discordgo.Channel) time.Time {
+	if ch.LastMessageID == "" {
+		return t.CreatedAt
+	}
+	last, err := discordgo.SnowflakeTimestamp(ch.LastMessageID)
+	if err != nil || last.Before(t.CreatedAt) {
+		return t.CreatedAt
+	}
+	return last
+}
+
+func checkInactiveTicket(s *discordgo.Session, t *ticket, ch *discordgo.Channel, policy inactivityPolicy) error {
+	if t.InactivityWarningID != "" {
+		if ch.LastMessageID != t.InactivityWarningID {
+			t.InactivityWarningID = ""
+			return updateTicket(t.ChannelID, bson.M{"$unset": bson.M{"inactivity_warning_id": "", "inactivity_warned_at": ""}})
+		}
+		if time.Since(t.InactivityWarnedAt) < policy.CloseAfter {
+			return nil
+		}
+		return autoCloseTicket(s, t, policy)
+	}
+	if time.Since(lastActivity(t, ch)) < policy.WarnAfter {
+		return nil
+	}
+	mentions := []string{fmt.Sprintf("<@%s>", t.OwnerID)}
+	if t.AssigneeID != "" {
+		mentions = append(mentions, fmt.Sprintf("<@%s>", t.AssigneeID))
+	}
+	for _, id := range t.Participants {
+		mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+	}
+	msg, err := s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+		Content: strings.Join(mentions, " "),
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       "무응답 안내",
+			Description: fmt.Sprintf("이 티켓에 %s 동안 새 메시지가 없었습니다.\n%s 안에 메시지가 없으면 티켓이 자동으로 닫힙니다.", formatSLADuration(policy.WarnAfter), formatSLADuration(policy.CloseAfter)),
+			Color:       colorYellow,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return updateTicket(t.ChannelID, bson.M{"$set": bson.M{"inactivity_warning_id": msg.ID, "inactivity_warned_at": time.Now()}})
+}
+
+func autoCloseTicket(s *discordgo.Session, t *ticket, policy inactivityPolicy) error {
+	t.CloseCode = inactivityCloseCode
+	t.CloseReason = fmt.Sprintf("%s 무응답으로 자동 종료", formatSLADuration(policy.WarnAfter+policy.CloseAfter))
+	err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"close_code": t.CloseCode, "close_reason": t.CloseReason}, "$unset": bson.M{"inactivity_warning_id": "", "inactivity_warned_at": ""}})
+	if err != nil {
+		return err
+	}
+	log.Printf("Auto-closing inactive ticket '%s'.", t.Name())
+	closeTicketChannel(s, t, s.State.User.ID, false)
+	return nil
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ticketBlock struct {
+	UserID          string    `bson:"_id"`
+	Reason          string    `bson:"reason"`
+	ModeratorID     string    `bson:"moderator_id"`
+	CreatedAt       time.Time `bson:"created_at"`
+	ExpiresAt       time.Time `bson:"expires_at,omitempty"`
+	AppealedAt      time.Time `bson:"appealed_at,omitempty"`
+	AppealChannelID string    `bson:"appeal_channel_id,omitempty"`
+}
+
+func (b ticketBlock) expiryLabel() string {
+	if b.ExpiresAt.IsZero() {
+		return "무기한"
+	}
+	return fmt.Sprintf("<t:%d:f> (<t:%d:R>)", b.ExpiresAt.Unix(), b.ExpiresAt.Unix())
+}
+
+func activeTicketBlock(userID string) (*ticketBlock, error) {
+	filter := bson.M{"_id": userID, "$or": []bson.M{{"expires_at": bson.M{"$exists": false}}, {"expires_at": bson.M{"$gt": time.Now()}}}}
+	var b ticketBlock
+	if err := app().Blocks.FindOne(context.TODO(), filter).Decode(&b); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &b, nil
+}
+
+func checkTicketBlock(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	b, err := activeTicketBlock(i.Member.User.ID)
+	if err != nil {
+		log.Printf("Could not check ticket blacklist for %s: %v", i.Member.User.ID, err)
+		return true
+	}
+	if b == nil {
+		return true
+	}
+	logError(i, errTicketBlocked, nil, b.Reason, b.expiryLabel())
+	data := &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{errTicketBlocked.embed(b.Reason, b.expiryLabel())}}
+	if b.AppealedAt.IsZero() {
+		data.Components = []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "이의신청", Style: discordgo.SecondaryButton, CustomID: appealButtonID},
+		}}}
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: data})
+	return false
+}
+
+func blockCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	oneValue := 1.0
+	return &discordgo.ApplicationCommand{
+		Name:                     "차단",
+		Description:              "특정 사용자가 민원 티켓을 열지 못하도록 차단합니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "차단할 사용자", Required: true},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "reason", Description: "차단 사유 (민원인에게 표시됩니다)", Required: true, MaxLength: 200},
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "days", Description: "차단 기간(일), 비우면 무기한", Required: false, MinValue: &oneValue},
+		},
+	}
+}
+
+func unblockCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "차단해제",
+		Description:              "사용자의 민원 티켓 차단을 해제합니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "차단을 해제할 사용자", Required: true},
+		},
+	}
+}
+
+func handleBlockUser(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	opts := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		opts[opt.Name] = opt
+	}
+	user := opts["user"].UserValue(nil)
+	b := ticketBlock{UserID: user.ID, Reason: opts["reason"].StringValue(), ModeratorID: i.Member.User.ID, CreatedAt: time.Now()}
+	if opt, ok := opts["days"]; ok {
+		b.ExpiresAt = b.CreatedAt.Add(time.Duration(opt.IntValue()) * 24 * time.Hour)
+	}
+	if _, err := app().Blocks.ReplaceOne(context.TODO(), bson.M{"_id": user.ID}, b, options.Replace().SetUpsert(true)); err != nil {
+		respondError(s, i, errBlockSaveFailed, err)
+		return
+	}
+	log.Printf("User %s blocked from opening tickets by %s until %s: %s", user.ID, b.ModeratorID, b.expiryLabel(), b.Reason)
+	fields := []*discordgo.MessageEmbedField{
+		{Name: "사유", Value: b.Reason, Inline: false},
+		{Name: "처리자", Value: fmt.Sprintf("<@%s>", b.ModeratorID), Inline: true},
+		{Name: "기간", Value: b.expiryLabel(), Inline: true},
+	}
+	s.ChannelMessageSendEmbed(getConfig().LogChannelID, &discordgo.MessageEmbed{Title: "민원 접수 차단", Description: fmt.Sprintf("<@%s> 님의 티켓 생성을 차단했습니다.", user.ID), Color: colorRed, Fields: fields})
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{
+		Title:       "차단 완료",
+		Description: fmt.Sprintf("<@%s> 님은 이제 새 티켓을 열 수 없습니다. 이미 열린 티켓은 그대로 유지됩니다.", user.ID),
+		Color:       colorGreen,
+		Fields:      fields,
+	}}}})
+}
+
+func handleUnblockUser(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	user := i.ApplicationCommandData().Options[0].UserValue(nil)
+	result, err := app().Blocks.DeleteOne(context.TODO(), bson.M{"_id": user.ID})
+	if err != nil {
+		respondError(s, i, errBlockSaveFailed, err)
+		return
+	}
+	if result.DeletedCount == 0 {
+		respondError(s, i, errNotBlocked, nil, user.ID)
+		return
+	}
+	log.Printf("User %s unblocked by %s.", user.ID, i.Member.User.ID)
+	s.ChannelMessageSendEmbed(getConfig().LogChannelID, &discordgo.MessageEmbed{Title: "민원 접수 차단 해제", Description: fmt.Sprintf("<@%s> 님이 <@%s> 님의 티켓 생성 차단을 해제했습니다.", i.Member.User.ID, user.ID), Color: colorGreen})
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "차단 해제", Description: fmt.Sprintf("<@%s> 님이 다시 티켓을 열 수 있습니다.", user.ID), Color: colorGreen}}}})
+}
@@ -0,0 +1,1053 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const boltDuplicateKeyCode = 11000
+
+type boltStore struct {
+	db *bbolt.DB
+}
+
+type boltCollection struct {
+	db     *bbolt.DB
+	bucket []byte
+}
+
+type boltMatch struct {
+	key   []byte
+	doc   bson.D
+	score float64
+}
+
+func openBoltStore(path string) (*boltStore, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &boltStore{db: db}, nil
+}
+
+func (b *boltStore) Name() string { return "embedded database" }
+
+func (b *boltStore) Collection(name string) collection {
+	return &boltCollection{db: b.db, bucket: []byte(name)}
+}
+
+func (b *boltStore) Ping(ctx context.Context) error {
+	return b.db.View(func(*bbolt.Tx) error { return nil })
+}
+
+func (b *boltStore) Disconnect(ctx context.Context) error { return b.db.Close() }
+
+func (c *boltCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error) {
+	o := options.MergeFindOptions(opts...)
+	var docs []interface{}
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		matches, err := c.query(tx, filter, o.Sort, o.Skip, o.Limit)
+		if err != nil {
+			return err
+		}
+		docs, err = project(matches, o.Projection)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mongo.NewCursorFromDocuments(docs, nil, nil)
+}
+
+func (c *boltCollection) FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult {
+	o := options.MergeFindOneOptions(opts...)
+	var docs []interface{}
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		matches, err := c.query(tx, filter, o.Sort, o.Skip, int64Ptr(1))
+		if err != nil {
+			return err
+		}
+		docs, err = project(matches, o.Projection)
+		return err
+	})
+	return singleResult(docs, err)
+}
+
+func (c *boltCollection) FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult {
+	o := options.MergeFindOneAndUpdateOptions(opts...)
+	returnAfter := o.ReturnDocument != nil && *o.ReturnDocument == options.After
+	var docs []interface{}
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		changes, err := toDocument(update)
+		if err != nil {
+			return err
+		}
+		matches, err := c.query(tx, filter, o.Sort, nil, int64Ptr(1))
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			if o.Upsert == nil || !*o.Upsert {
+				return nil
+			}
+			doc, err := c.upsert(tx, filter, changes, false)
+			if err == nil && returnAfter {
+				docs = []interface{}{doc}
+			}
+			return err
+		}
+		before := matches[0].doc
+		after, err := applyUpdate(cloneDocument(before), changes, false)
+		if err != nil {
+			return err
+		}
+		if err := c.put(tx, matches[0].key, after); err != nil {
+			return err
+		}
+		docs = []interface{}{before}
+		if returnAfter {
+			docs = []interface{}{after}
+		}
+		return nil
+	})
+	return singleResult(docs, err)
+}
+
+func (c *boltCollection) InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error) {
+	doc, err := toDocument(document)
+	if err != nil {
+		return nil, err
+	}
+	doc = withID(doc)
+	err = c.db.Update(func(tx *bbolt.Tx) error { return c.insert(tx, doc) })
+	if err != nil {
+		return nil, err
+	}
+	return &mongo.InsertOneResult{InsertedID: documentID(doc)}, nil
+}
+
+func (c *boltCollection) InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error) {
+	result := &mongo.InsertManyResult{}
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		for _, document := range documents {
+			doc, err := toDocument(document)
+			if err != nil {
+				return err
+			}
+			doc = withID(doc)
+			if err := c.insert(tx, doc); err != nil {
+				return err
+			}
+			result.InsertedIDs = append(result.InsertedIDs, documentID(doc))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *boltCollection) UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return c.update(filter, update, false, false, options.MergeUpdateOptions(opts...).Upsert)
+}
+
+func (c *boltCollection) UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error) {
+	return c.update(filter, update, true, false, options.MergeUpdateOptions(opts...).Upsert)
+}
+
+func (c *boltCollection) ReplaceOne(ctx context.Context, filter interface{}, replacement interface{}, opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error) {
+	return c.update(filter, replacement, false, true, options.MergeReplaceOptions(opts...).Upsert)
+}
+
+func (c *boltCollection) DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return c.delete(filter, int64Ptr(1))
+}
+
+func (c *boltCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error) {
+	return c.delete(filter, nil)
+}
+
+func (c *boltCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	var count int64
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		matches, err := c.query(tx, filter, nil, nil, nil)
+		count = int64(len(matches))
+		return err
+	})
+	return count, err
+}
+
+func (c *boltCollection) Distinct(ctx context.Context, fieldName string, filter interface{}, opts ...*options.DistinctOptions) ([]interface{}, error) {
+	var values []interface{}
+	err := c.db.View(func(tx *bbolt.Tx) error {
+		matches, err := c.query(tx, filter, nil, nil, nil)
+		if err != nil {
+			return err
+		}
+		for _, m := range matches {
+			for _, v := range lookupPath(m.doc, fieldName) {
+				if arr, ok := v.(bson.A); ok {
+					for _, elem := range arr {
+						values = appendDistinct(values, elem)
+					}
+					continue
+				}
+				values = appendDistinct(values, v)
+			}
+		}
+		return nil
+	})
+	return values, err
+}
+
+func (c *boltCollection) update(filter interface{}, update interface{}, many, replace bool, upsert *bool) (*mongo.UpdateResult, error) {
+	result := &mongo.UpdateResult{}
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		changes, err := toDocument(update)
+		if err != nil {
+			return err
+		}
+		if replace && isOperatorDocument(changes) {
+			return errors.New("replacement document must not contain update operators")
+		}
+		var limit *int64
+		if !many {
+			limit = int64Ptr(1)
+		}
+		matches, err := c.query(tx, filter, nil, nil, limit)
+		if err != nil {
+			return err
+		}
+		for _, m := range matches {
+			result.MatchedCount++
+			next, err := applyUpdate(cloneDocument(m.doc), changes, false)
+			if err != nil {
+				return err
+			}
+			raw, err := bson.Marshal(next)
+			if err != nil {
+				return err
+			}
+			if bytes.Equal(raw, c.bucketFor(tx).Get(m.key)) {
+				continue
+			}
+			if err := c.put(tx, m.key, next); err != nil {
+				return err
+			}
+			result.ModifiedCount++
+		}
+		if result.MatchedCount > 0 || upsert == nil || !*upsert {
+			return nil
+		}
+		doc, err := c.upsert(tx, filter, changes, replace)
+		if err != nil {
+			return err
+		}
+		result.UpsertedCount, result.UpsertedID = 1, documentID(doc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *boltCollection) upsert(tx *bbolt.Tx, filter interface{}, changes bson.D, replace bool) (bson.D, error) {
+	query, err := toDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+	seed := bson.D{}
+	for _, e := range query {
+		if strings.HasPrefix(e.Key, "$") {
+			continue
+		}
+		if cond, ok := e.Value.(bson.D); ok && isOperatorDocument(cond) {
+			if eq, ok := lookupKey(cond, "$eq"); ok {
+				seed = setPath(seed, e.Key, eq)
+			}
+			continue
+		}
+		seed = setPath(seed, e.Key, e.Value)
+	}
+	doc := seed
+	if replace {
+		doc = cloneDocument(changes)
+		if id, ok := lookupKey(seed, "_id"); ok {
+			doc = setPath(doc, "_id", id)
+		}
+	} else if doc, err = applyUpdate(seed, changes, true); err != nil {
+		return nil, err
+	}
+	doc = withID(doc)
+	return doc, c.insert(tx, doc)
+}
+
+func (c *boltCollection) delete(filter interface{}, limit *int64) (*mongo.DeleteResult, error) {
+	result := &mongo.DeleteResult{}
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		matches, err := c.query(tx, filter, nil, nil, limit)
+		if err != nil {
+			return err
+		}
+		for _, m := range matches {
+			if err := c.bucketFor(tx).Delete(m.key); err != nil {
+				return err
+			}
+			result.DeletedCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *boltCollection) bucketFor(tx *bbolt.Tx) *bbolt.Bucket {
+	return tx.Bucket(c.bucket)
+}
+
+func (c *boltCollection) insert(tx *bbolt.Tx, doc bson.D) error {
+	key := documentKey(documentID(doc))
+	if bucket := c.bucketFor(tx); bucket != nil && bucket.Get(key) != nil {
+		return mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: boltDuplicateKeyCode, Message: fmt.Sprintf("E11000 duplicate key error collection: %s index: _id_ dup key: { _id: %v }", c.bucket, documentID(doc))}}}
+	}
+	return c.put(tx, key, doc)
+}
+
+func (c *boltCollection) put(tx *bbolt.Tx, key []byte, doc bson.D) error {
+	if !bytes.Equal(key, documentKey(documentID(doc))) {
+		return errors.New("the _id field cannot be changed")
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	bucket, err := tx.CreateBucketIfNotExists(c.bucket)
+	if err != nil {
+		return err
+	}
+	return bucket.Put(key, raw)
+}
+
+func (c *boltCollection) query(tx *bbolt.Tx, filter interface{}, sortSpec interface{}, skip, limit *int64) ([]boltMatch, error) {
+	query, err := toDocument(filter)
+	if err != nil {
+		return nil, err
+	}
+	bucket := c.bucketFor(tx)
+	if bucket == nil {
+		return nil, nil
+	}
+	var terms []string
+	if text, ok := lookupKey(query, "$text"); ok {
+		if search, ok := text.(bson.D); ok {
+			if value, ok := lookupKey(search, "$search"); ok {
+				terms = strings.Fields(strings.ToLower(fmt.Sprint(value)))
+			}
+		}
+	}
+	var matches []boltMatch
+	err = bucket.ForEach(func(k, v []byte) error {
+		var doc bson.D
+		if err := bson.Unmarshal(append([]byte(nil), v...), &doc); err != nil {
+			return fmt.Errorf("could not decode document %q in %s: %w", k, c.bucket, err)
+		}
+		if !matchDocument(doc, query) {
+			return nil
+		}
+		m := boltMatch{key: append([]byte(nil), k...), doc: doc}
+		if terms != nil {
+			if m.score = textScore(doc, terms); m.score == 0 {
+				return nil
+			}
+		}
+		matches = append(matches, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if sortSpec != nil {
+		spec, err := toDocument(sortSpec)
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(matches, func(a, b int) bool { return lessBySpec(matches[a], matches[b], spec) })
+	}
+	if skip != nil && *skip > 0 {
+		if *skip >= int64(len(matches)) {
+			return nil, nil
+		}
+		matches = matches[*skip:]
+	}
+	if limit != nil && *limit > 0 && *limit < int64(len(matches)) {
+		matches = matches[:*limit]
+	}
+	return matches, nil
+}
+
+func project(matches []boltMatch, projection interface{}) ([]interface{}, error) {
+	var spec bson.D
+	if projection != nil {
+		var err error
+		if spec, err = toDocument(projection); err != nil {
+			return nil, err
+		}
+	}
+	docs := make([]interface{}, 0, len(matches))
+	for _, m := range matches {
+		doc := m.doc
+		for _, e := range spec {
+			if isMetaScore(e.Value) {
+				doc = setPath(doc, e.Key, m.score)
+			} else if included, ok := numberValue(e.Value); ok && included == 0 {
+				doc = unsetPath(doc, e.Key)
+			}
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func singleResult(docs []interface{}, err error) *mongo.SingleResult {
+	if err != nil {
+		return mongo.NewSingleResultFromDocument(bson.D{}, err, nil)
+	}
+	if len(docs) == 0 {
+		return mongo.NewSingleResultFromDocument(bson.D{}, mongo.ErrNoDocuments, nil)
+	}
+	return mongo.NewSingleResultFromDocument(docs[0], nil, nil)
+}
+
+func toDocument(v interface{}) (bson.D, error) {
+	if v == nil {
+		return bson.D{}, nil
+	}
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func cloneDocument(doc bson.D) bson.D {
+	clone, err := toDocument(doc)
+	if err != nil {
+		return append(bson.D(nil), doc...)
+	}
+	return clone
+}
+
+func withID(doc bson.D) bson.D {
+	if _, ok := lookupKey(doc, "_id"); ok {
+		return doc
+	}
+	return append(bson.D{{Key: "_id", Value: primitive.NewObjectID()}}, doc...)
+}
+
+func documentID(doc bson.D) interface{} {
+	id, _ := lookupKey(doc, "_id")
+	return id
+}
+
+func documentKey(id interface{}) []byte {
+	if n, ok := numberValue(id); ok && n == math.Trunc(n) && math.Abs(n) < 1<<53 {
+		return []byte("n:" + strconv.FormatInt(int64(n), 10))
+	}
+	switch v := id.(type) {
+	case int64:
+		return []byte("n:" + strconv.FormatInt(v, 10))
+	case string:
+		return []byte("s:" + v)
+	case primitive.ObjectID:
+		return []byte("o:" + v.Hex())
+	}
+	raw, _ := bson.Marshal(bson.D{{Key: "_id", Value: id}})
+	return []byte("b:" + hex.EncodeToString(raw))
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func lookupKey(doc bson.D, key string) (interface{}, bool) {
+	for _, e := range doc {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+	return nil, false
+}
+
+func isOperatorDocument(doc bson.D) bool {
+	return len(doc) > 0 && strings.HasPrefix(doc[0].Key, "$")
+}
+
+func isMetaScore(v interface{}) bool {
+	doc, ok := v.(bson.D)
+	if !ok {
+		return false
+	}
+	meta, ok := lookupKey(doc, "$meta")
+	return ok && meta == "textScore"
+}
+
+func lookupPath(doc bson.D, path string) []interface{} {
+	return lookupParts(doc, strings.Split(path, "."))
+}
+
+func lookupParts(value interface{}, parts []string) []interface{} {
+	if len(parts) == 0 {
+		return []interface{}{value}
+	}
+	switch v := value.(type) {
+	case bson.D:
+		child, ok := lookupKey(v, parts[0])
+		if !ok {
+			return nil
+		}
+		return lookupParts(child, parts[1:])
+	case bson.A:
+		if idx, err := strconv.Atoi(parts[0]); err == nil {
+			if idx < 0 || idx >= len(v) {
+				return nil
+			}
+			return lookupParts(v[idx], parts[1:])
+		}
+		var values []interface{}
+		for _, elem := range v {
+			if _, ok := elem.(bson.D); ok {
+				values = append(values, lookupParts(elem, parts)...)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func candidates(values []interface{}) []interface{} {
+	var all []interface{}
+	for _, v := range values {
+		all = append(all, v)
+		if arr, ok := v.(bson.A); ok {
+			all = append(all, arr...)
+		}
+	}
+	return all
+}
+
+func matchDocument(doc bson.D, query bson.D) bool {
+	for _, e := range query {
+		switch e.Key {
+		case "$text", "$comment":
+			continue
+		case "$and", "$or", "$nor":
+			clauses, _ := e.Value.(bson.A)
+			matched := 0
+			for _, clause := range clauses {
+				if sub, ok := clause.(bson.D); ok && matchDocument(doc, sub) {
+					matched++
+				}
+			}
+			if e.Key == "$and" && matched != len(clauses) || e.Key == "$or" && matched == 0 || e.Key == "$nor" && matched > 0 {
+				return false
+			}
+		default:
+			if !matchField(lookupPath(doc, e.Key), e.Value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func matchField(values []interface{}, cond interface{}) bool {
+	ops, ok := cond.(bson.D)
+	if !ok || !isOperatorDocument(ops) {
+		return matchEqual(values, cond)
+	}
+	for _, op := range ops {
+		if !matchOperator(values, op.Key, op.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchEqual(values []interface{}, target interface{}) bool {
+	if isNull(target) && len(values) == 0 {
+		return true
+	}
+	for _, v := range candidates(values) {
+		if equalValues(v, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchOperator(values []interface{}, op string, arg interface{}) bool {
+	switch op {
+	case "$eq":
+		return matchEqual(values, arg)
+	case "$ne":
+		return !matchEqual(values, arg)
+	case "$in", "$nin":
+		list, _ := arg.(bson.A)
+		found := false
+		for _, target := range list {
+			if matchEqual(values, target) {
+				found = true
+				break
+			}
+		}
+		return found == (op == "$in")
+	case "$exists":
+		want := true
+		if b, ok := arg.(bool); ok {
+			want = b
+		} else if n, ok := numberValue(arg); ok {
+			want = n != 0
+		}
+		return (len(values) > 0) == want
+	case "$type":
+		for _, v := range candidates(values) {
+			if bsonTypeName(v) == arg {
+				return true
+			}
+		}
+		return false
+	case "$gt", "$gte", "$lt", "$lte":
+		for _, v := range candidates(values) {
+			cmp, ok := compareValues(v, arg)
+			if !ok {
+				continue
+			}
+			if op == "$gt" && cmp > 0 || op == "$gte" && cmp >= 0 || op == "$lt" && cmp < 0 || op == "$lte" && cmp <= 0 {
+				return true
+			}
+		}
+		return false
+	case "$not":
+		return !matchField(values, arg)
+	}
+	return false
+}
+
+func textScore(doc bson.D, terms []string) float64 {
+	var text strings.Builder
+	collectText(doc, &text)
+	haystack := strings.ToLower(text.String())
+	score := 0.0
+	for _, term := range terms {
+		score += float64(strings.Count(haystack, term))
+	}
+	return score
+}
+
+func collectText(value interface{}, out *strings.Builder) {
+	switch v := value.(type) {
+	case string:
+		out.WriteString(v)
+		out.WriteByte('\n')
+	case bson.D:
+		for _, e := range v {
+			if e.Key != "_id" {
+				collectText(e.Value, out)
+			}
+		}
+	case bson.A:
+		for _, elem := range v {
+			collectText(elem, out)
+		}
+	}
+}
+
+func lessBySpec(a, b boltMatch, spec bson.D) bool {
+	for _, e := range spec {
+		var cmp int
+		if isMetaScore(e.Value) {
+			cmp = -compareNumbers(a.score, b.score)
+		} else {
+			cmp = orderValues(firstValue(a.doc, e.Key), firstValue(b.doc, e.Key))
+			if dir, ok := numberValue(e.Value); ok && dir < 0 {
+				cmp = -cmp
+			}
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return false
+}
+
+func firstValue(doc bson.D, path string) interface{} {
+	values := lookupPath(doc, path)
+	if len(values) == 0 {
+		return nil
+	}
+	return values[0]
+}
+
+func typeRank(v interface{}) int {
+	if _, ok := numberValue(v); ok {
+		return 2
+	}
+	switch v.(type) {
+	case nil, primitive.Null, primitive.Undefined:
+		return 1
+	case string, primitive.Symbol:
+		return 3
+	case bson.D:
+		return 4
+	case bson.A:
+		return 5
+	case primitive.Binary:
+		return 6
+	case primitive.ObjectID:
+		return 7
+	case bool:
+		return 8
+	case primitive.DateTime:
+		return 9
+	case primitive.Timestamp:
+		return 10
+	}
+	return 11
+}
+
+func orderValues(a, b interface{}) int {
+	if ra, rb := typeRank(a), typeRank(b); ra != rb {
+		return ra - rb
+	}
+	cmp, _ := compareValues(a, b)
+	return cmp
+}
+
+func compareValues(a, b interface{}) (int, bool) {
+	if typeRank(a) != typeRank(b) {
+		return 0, false
+	}
+	if x, ok := numberValue(a); ok {
+		y, _ := numberValue(b)
+		return compareNumbers(x, y), true
+	}
+	switch x := a.(type) {
+	case nil, primitive.Null, primitive.Undefined:
+		return 0, true
+	case string:
+		y, ok := b.(string)
+		return strings.Compare(x, y), ok
+	case primitive.DateTime:
+		y := b.(primitive.DateTime)
+		return compareNumbers(float64(x), float64(y)), true
+	case bool:
+		y := b.(bool)
+		if x == y {
+			return 0, true
+		}
+		if !x {
+			return -1, true
+		}
+		return 1, true
+	case primitive.ObjectID:
+		y := b.(primitive.ObjectID)
+		return bytes.Compare(x[:], y[:]), true
+	case bson.A:
+		y := b.(bson.A)
+		for idx := 0; idx < len(x) && idx < len(y); idx++ {
+			if cmp := orderValues(x[idx], y[idx]); cmp != 0 {
+				return cmp, true
+			}
+		}
+		return len(x) - len(y), true
+	}
+	ra, errA := bson.Marshal(bson.D{{Key: "v", Value: a}})
+	rb, errB := bson.Marshal(bson.D{{Key: "v", Value: b}})
+	if errA != nil || errB != nil {
+		return 0, false
+	}
+	return bytes.Compare(ra, rb), true
+}
+
+func compareNumbers(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func equalValues(a, b interface{}) bool {
+	cmp, ok := compareValues(a, b)
+	return ok && cmp == 0
+}
+
+func isNull(v interface{}) bool {
+	return typeRank(v) == 1
+}
+
+func numberValue(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func bsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case int32:
+		return "int"
+	case int64:
+		return "long"
+	case float64:
+		return "double"
+	case bool:
+		return "bool"
+	case primitive.DateTime:
+		return "date"
+	case primitive.ObjectID:
+		return "objectId"
+	case bson.D:
+		return "object"
+	case bson.A:
+		return "array"
+	case nil, primitive.Null:
+		return "null"
+	}
+	return ""
+}
+
+func appendDistinct(values []interface{}, v interface{}) []interface{} {
+	for _, existing := range values {
+		if equalValues(existing, v) {
+			return values
+		}
+	}
+	return append(values, v)
+}
+
+func applyUpdate(doc bson.D, update bson.D, inserting bool) (bson.D, error) {
+	if !isOperatorDocument(update) {
+		replacement := cloneDocument(update)
+		if id, ok := lookupKey(doc, "_id"); ok {
+			replacement = append(bson.D{{Key: "_id", Value: id}}, unsetPath(replacement, "_id")...)
+		}
+		return replacement, nil
+	}
+	for _, op := range update {
+		fields, ok := op.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("%s expects a document", op.Key)
+		}
+		for _, f := range fields {
+			var err error
+			switch op.Key {
+			case "$set":
+				doc = setPath(doc, f.Key, f.Value)
+			case "$setOnInsert":
+				if inserting {
+					doc = setPath(doc, f.Key, f.Value)
+				}
+			case "$unset":
+				doc = unsetPath(doc, f.Key)
+			case "$inc":
+				doc, err = incPath(doc, f.Key, f.Value)
+			case "$push", "$addToSet":
+				doc, err = appendPath(doc, f.Key, f.Value, op.Key == "$addToSet")
+			case "$pull":
+				doc, err = pullPath(doc, f.Key, f.Value)
+			default:
+				err = fmt.Errorf("unsupported update operator %s", op.Key)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return doc, nil
+}
+
+func setPath(doc bson.D, path string, value interface{}) bson.D {
+	key, rest, nested := strings.Cut(path, ".")
+	for idx, e := range doc {
+		if e.Key != key {
+			continue
+		}
+		if !nested {
+			doc[idx].Value = value
+			return doc
+		}
+		if arr, ok := e.Value.(bson.A); ok {
+			doc[idx].Value = setArrayPath(arr, rest, value)
+			return doc
+		}
+		child, _ := e.Value.(bson.D)
+		doc[idx].Value = setPath(child, rest, value)
+		return doc
+	}
+	if !nested {
+		return append(doc, bson.E{Key: key, Value: value})
+	}
+	return append(doc, bson.E{Key: key, Value: setPath(bson.D{}, rest, value)})
+}
+
+func setArrayPath(arr bson.A, path string, value interface{}) bson.A {
+	key, rest, nested := strings.Cut(path, ".")
+	idx, err := strconv.Atoi(key)
+	if err != nil || idx < 0 {
+		return arr
+	}
+	for len(arr) <= idx {
+		arr = append(arr, nil)
+	}
+	if !nested {
+		arr[idx] = value
+		return arr
+	}
+	child, _ := arr[idx].(bson.D)
+	arr[idx] = setPath(child, rest, value)
+	return arr
+}
+
+func unsetPath(doc bson.D, path string) bson.D {
+	key, rest, nested := strings.Cut(path, ".")
+	for idx, e := range doc {
+		if e.Key != key {
+			continue
+		}
+		if !nested {
+			return append(doc[:idx:idx], doc[idx+1:]...)
+		}
+		if child, ok := e.Value.(bson.D); ok {
+			doc[idx].Value = unsetPath(child, rest)
+		}
+		return doc
+	}
+	return doc
+}
+
+func incPath(doc bson.D, path string, delta interface{}) (bson.D, error) {
+	current := firstValue(doc, path)
+	if current == nil {
+		current = int32(0)
+	}
+	sum, err := addNumbers(current, delta)
+	if err != nil {
+		return nil, fmt.Errorf("cannot $inc %s: %w", path, err)
+	}
+	return setPath(doc, path, sum), nil
+}
+
+func addNumbers(a, b interface{}) (interface{}, error) {
+	if _, ok := numberValue(a); !ok {
+		return nil, fmt.Errorf("%v is not a number", a)
+	}
+	if _, ok := numberValue(b); !ok {
+		return nil, fmt.Errorf("%v is not a number", b)
+	}
+	_, aFloat := a.(float64)
+	_, bFloat := b.(float64)
+	if aFloat || bFloat {
+		x, _ := numberValue(a)
+		y, _ := numberValue(b)
+		return x + y, nil
+	}
+	x, y := integerValue(a), integerValue(b)
+	sum := x + y
+	_, aLong := a.(int64)
+	_, bLong := b.(int64)
+	if !aLong && !bLong && sum >= math.MinInt32 && sum <= math.MaxInt32 {
+		return int32(sum), nil
+	}
+	return sum, nil
+}
+
+func integerValue(v interface{}) int64 {
+	switch n := v.(type) {
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	}
+	return 0
+}
+
+func appendPath(doc bson.D, path string, value interface{}, unique bool) (bson.D, error) {
+	items := bson.A{value}
+	if spec, ok := value.(bson.D); ok && isOperatorDocument(spec) {
+		each, ok := lookupKey(spec, "$each")
+		if !ok {
+			return nil, fmt.Errorf("unsupported modifier on %s", path)
+		}
+		items, _ = each.(bson.A)
+	}
+	current := firstValue(doc, path)
+	arr, ok := current.(bson.A)
+	if !ok && current != nil {
+		return nil, fmt.Errorf("%s is not an array", path)
+	}
+	for _, item := range items {
+		if unique && matchEqual([]interface{}{arr}, item) {
+			continue
+		}
+		arr = append(arr, item)
+	}
+	if arr == nil {
+		arr = bson.A{}
+	}
+	return setPath(doc, path, arr), nil
+}
+
+func pullPath(doc bson.D, path string, cond interface{}) (bson.D, error) {
+	arr, ok := firstValue(doc, path).(bson.A)
+	if !ok {
+		return doc, nil
+	}
+	kept := bson.A{}
+	for _, item := range arr {
+		var remove bool
+		if sub, ok := cond.(bson.D); ok && !isOperatorDocument(sub) {
+			itemDoc, isDoc := item.(bson.D)
+			remove = isDoc && matchDocument(itemDoc, sub)
+		} else {
+			remove = matchField([]interface{}{item}, cond)
+		}
+		if !remove {
+			kept = append(kept, item)
+		}
+	}
+	return setPath(doc, path, kept), nil
+}
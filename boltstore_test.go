@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func useTestStore(t *testing.T) {
+	t.Helper()
+	st, err := openBoltStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("openBoltStore: %v", err)
+	}
+	previous := app()
+	updateBot(func(b *bot) { attachStore(b, st, defaultCounterBucket) })
+	t.Cleanup(func() {
+		st.Disconnect(context.Background())
+		currentBot.Store(previous)
+	})
+}
+
+func TestBoltSequenceIncrements(t *testing.T) {
+	useTestStore(t)
+	for want := uint64(1); want <= 3; want++ {
+		got, err := getNextSequenceValue("일반민원")
+		if err != nil {
+			t.Fatalf("getNextSequenceValue: %v", err)
+		}
+		if got != want {
+			t.Fatalf("sequence = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestBoltUpsertReportsDuplicateKey(t *testing.T) {
+	useTestStore(t)
+	if _, ok := claimTicketCooldown("user", time.Hour); !ok {
+		t.Fatal("first claim was refused")
+	}
+	remaining, ok := claimTicketCooldown("user", time.Hour)
+	if ok {
+		t.Fatal("second claim inside the cooldown was accepted")
+	}
+	if remaining <= 0 || remaining > time.Hour {
+		t.Errorf("remaining cooldown = %s", remaining)
+	}
+
+	job := &scheduledJob{Name: "weekly_leaderboard", Interval: time.Hour}
+	now := time.Now()
+	if claimed, err := claimJob(job, now); err != nil || !claimed {
+		t.Fatalf("first claimJob = %v, %v", claimed, err)
+	}
+	if claimed, err := claimJob(job, now); err != nil || claimed {
+		t.Fatalf("second claimJob = %v, %v; want false, nil", claimed, err)
+	}
+}
+
+func TestBoltTicketQueries(t *testing.T) {
+	useTestStore(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, kstLocation)
+	tickets := []interface{}{
+		ticket{ID: 1, ChannelID: "c1", OwnerID: "u1", Category: "일반민원", Status: ticketStatusOpen, Subject: "환불 문의", CreatedAt: base},
+		ticket{ID: 2, ChannelID: "c2", OwnerID: "u1", Category: "신고", Status: ticketStatusClosed, Subject: "욕설 신고", CreatedAt: base.Add(time.Hour)},
+		ticket{ID: 3, ChannelID: "c3", OwnerID: "u2", Category: "일반민원", Status: ticketStatusOpen, Subject: "환불 환불 요청", CreatedAt: base.Add(2 * time.Hour)},
+	}
+	if _, err := app().Tickets.InsertMany(ctx, tickets); err != nil {
+		t.Fatalf("InsertMany: %v", err)
+	}
+
+	filter := bson.M{"status": bson.M{"$ne": ticketStatusDeleted}, "created_at": bson.M{"$gte": base.Add(30 * time.Minute)}}
+	cursor, err := app().Tickets.Find(ctx, filter, options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(1))
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	var found []ticket
+	if err := cursor.All(ctx, &found); err != nil {
+		t.Fatalf("cursor.All: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != 3 {
+		t.Fatalf("newest ticket after cutoff = %+v, want ticket 3", found)
+	}
+
+	if n, err := app().Tickets.CountDocuments(ctx, bson.M{"$or": []bson.M{{"owner_id": "u2"}, {"category": "신고"}}}); err != nil || n != 2 {
+		t.Errorf("CountDocuments($or) = %d, %v; want 2", n, err)
+	}
+	owners, err := app().Tickets.Distinct(ctx, "owner_id", bson.M{"category": bson.M{"$in": []string{"일반민원"}}})
+	if err != nil || len(owners) != 2 {
+		t.Errorf("Distinct(owner_id) = %v, %v; want 2 owners", owners, err)
+	}
+
+	if err := updateTicket("c1", bson.M{"$addToSet": bson.M{"participants": bson.M{"$each": []string{"a", "b", "a"}}}, "$inc": bson.M{"reopen_count": 1}}); err != nil {
+		t.Fatalf("updateTicket: %v", err)
+	}
+	if err := updateTicket("c1", bson.M{"$pull": bson.M{"participants": "a"}, "$unset": bson.M{"subject": ""}}); err != nil {
+		t.Fatalf("updateTicket: %v", err)
+	}
+	got, err := findTicket("c1")
+	if err != nil {
+		t.Fatalf("findTicket: %v", err)
+	}
+	if len(got.Participants) != 1 || got.Participants[0] != "b" || got.ReopenCount != 1 || got.Subject != "" {
+		t.Errorf("updated ticket = participants %v, reopen %d, subject %q", got.Participants, got.ReopenCount, got.Subject)
+	}
+	if _, err := findTicket("missing"); err != mongo.ErrNoDocuments {
+		t.Errorf("findTicket(missing) error = %v, want ErrNoDocuments", err)
+	}
+
+	search := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "created_at", Value: -1}})
+	cursor, err = app().Tickets.Find(ctx, bson.M{"$text": bson.M{"$search": "환불"}}, search)
+	if err != nil {
+		t.Fatalf("Find($text): %v", err)
+	}
+	found = nil
+	if err := cursor.All(ctx, &found); err != nil {
+		t.Fatalf("cursor.All: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != 3 {
+		t.Errorf("text search = %+v, want only ticket 3", found)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const changeStreamRetryDelay = 30 * time.Second
+
+var changeStreamOnce sync.Once
+
+type ticketChange struct {
+	OperationType     string `bson:"operationType"`
+	FullDocument      ticket `bson:"fullDocument"`
+	UpdateDescription struct {
+		UpdatedFields bson.M `bson:"updatedFields"`
+	} `bson:"updateDescription"`
+}
+
+func watchTicketChanges(s *discordgo.Session) {
+	if _, ok := app().Tickets.(*mongo.Collection); !ok {
+		log.Println("Ticket change streams need MongoDB; external edits to tickets will not be synced.")
+		return
+	}
+	for !draining.Load() {
+		err := watchTicketChangesOnce(s)
+		if draining.Load() {
+			return
+		}
+		log.Printf("Ticket change stream stopped: %v. Retrying in %s.", err, changeStreamRetryDelay)
+		time.Sleep(changeStreamRetryDelay)
+	}
+}
+
+func watchTicketChangesOnce(s *discordgo.Session) error {
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{"operationType": bson.M{"$in": []string{"update", "replace"}}}}}}
+	stream, err := app().Tickets.(*mongo.Collection).Watch(context.TODO(), pipeline, options.ChangeStream().SetFullDocument(options.UpdateLookup))
+	if err != nil {
+		return err
+	}
+	defer stream.Close(context.TODO())
+	log.Println("Watching the tickets collection for external changes.")
+	for stream.Next(context.TODO()) {
+		var change ticketChange
+		if err := stream.Decode(&change); err != nil {
+			log.Printf("Could not decode ticket change: %v", err)
+			continue
+		}
+		if change.FullDocument.ChannelID == "" {
+			continue
+		}
+		if err := syncTicketToDiscord(s, &change.FullDocument, change.UpdateDescription.UpdatedFields, change.OperationType == "replace"); err != nil {
+			log.Printf("Could not apply external change to ticket '%s': %v", change.FullDocument.Name(), err)
+		}
+	}
+	return stream.Err()
+}
+
+func changedField(fields bson.M, all bool, name string) bool {
+	if all {
+		return true
+	}
+	for key := range fields {
+		if key == name || len(key) > len(name) && key[:len(name)+1] == name+"." {
+			return true
+		}
+	}
+	return false
+}
+
+func syncTicketToDiscord(s *discordgo.Session, t *ticket, fields bson.M, replaced bool) error {
+	if t.Status == ticketStatusDeleted {
+		return nil
+	}
+	if changedField(fields, replaced, "status") {
+		if err := syncTicketStatus(s, t); err != nil {
+			return fmt.Errorf("status: %w", err)
+		}
+	}
+	if changedField(fields, replaced, "assignee_id") && t.AssigneeID != "" {
+		if err := syncTicketAssignee(s, t); err != nil {
+			return fmt.Errorf("assignee: %w", err)
+		}
+	}
+	if !t.Forum && (changedField(fields, replaced, "participants") || changedField(fields, replaced, "participant_roles")) {
+		if err := syncTicketParticipants(s, t); err != nil {
+			return fmt.Errorf("participants: %w", err)
+		}
+	}
+	return nil
+}
+
+func syncTicketStatus(s *discordgo.Session, t *ticket) error {
+	closed := t.Status == ticketStatusClosed
+	ch, err := s.Channel(t.ChannelID)
+	if err != nil {
+		return err
+	}
+	if t.Forum {
+		if ch.ThreadMetadata != nil && ch.ThreadMetadata.Locked == closed {
+			return nil
+		}
+		return setForumTicketClosed(s, t, closed)
+	}
+	cfg := getConfig()
+	if closed && ch.ParentID != cfg.ClosedCategoryID {
+		s.ChannelPermissionSet(t.ChannelID, t.OwnerID, discordgo.PermissionOverwriteTypeMember, 0, discordgo.PermissionViewChannel)
+		_, err = s.ChannelEditComplex(t.ChannelID, &discordgo.ChannelEdit{ParentID: cfg.ClosedCategoryID})
+		s.ChannelMessageSendEmbed(t.ChannelID, &discordgo.MessageEmbed{Title: "티켓 종료", Description: "외부 시스템에서 티켓이 닫힘 처리되었습니다.", Color: colorGray})
+		return err
+	}
+	if !closed && ch.ParentID == cfg.ClosedCategoryID {
+		_, err = s.ChannelEditComplex(t.ChannelID, &discordgo.ChannelEdit{ParentID: cfg.OpenCategoryID})
+		s.ChannelPermissionSet(t.ChannelID, t.OwnerID, discordgo.PermissionOverwriteTypeMember, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0)
+		s.ChannelMessageSendEmbed(t.ChannelID, &discordgo.MessageEmbed{Title: "티켓 재오픈", Description: "외부 시스템에서 티켓이 다시 열렸습니다.", Color: colorGreen})
+		return err
+	}
+	return nil
+}
+
+func syncTicketAssignee(s *discordgo.Session, t *ticket) error {
+	msg, err := findTicketMessage(s, t.ChannelID)
+	if err != nil || msg == nil {
+		return err
+	}
+	mention := fmt.Sprintf("<@%s>", t.AssigneeID)
+	for _, field := range msg.Embeds[0].Fields {
+		if field.Name == "담당자" && field.Value == mention {
+			return nil
+		}
+	}
+	return setTicketMessageAssignee(s, msg, mention)
+}
+
+func syncTicketParticipants(s *discordgo.Session, t *ticket) error {
+	ch, err := s.Channel(t.ChannelID)
+	if err != nil {
+		return err
+	}
+	allowed := make(map[string]bool)
+	for _, o := range ch.PermissionOverwrites {
+		if o.Allow&discordgo.PermissionViewChannel != 0 {
+			allowed[o.ID] = true
+		}
+	}
+	for _, id := range t.Participants {
+		if !allowed[id] {
+			if err := s.ChannelPermissionSet(t.ChannelID, id, discordgo.PermissionOverwriteTypeMember, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0); err != nil {
+				return err
+			}
+		}
+	}
+	for _, id := range t.ParticipantRoles {
+		if !allowed[id] {
+			if err := s.ChannelPermissionSet(t.ChannelID, id, discordgo.PermissionOverwriteTypeRole, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultClosedRetentionDays = 30
+	closedCleanupInterval      = 7 * 24 * time.Hour
+	closedCleanupPrefix        = "cleanup_closed:"
+	closedCleanupAll           = "all"
+	maxCleanupButtons          = 20
+)
+
+type staleChannel struct {
+	Channel  *discordgo.Channel
+	ClosedAt time.Time
+}
+
+func closedRetention() time.Duration {
+	if days := getConfig().ClosedRetentionDays; days > 0 {
+		return time.Duration(days) * 24 * time.Hour
+	}
+	v := os.Getenv("CLOSED_TICKET_RETENTION_DAYS")
+	if v == "" {
+		return defaultClosedRetentionDays * 24 * time.Hour
+	}
+	days, err := strconv.Atoi(v)
+	if err != nil || days < 1 {
+		log.Printf("Invalid CLOSED_TICKET_RETENTION_DAYS '%s': %v", v, err)
+		return defaultClosedRetentionDays * 24 * time.Hour
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func findStaleClosedChannels(s *discordgo.Session) ([]staleChannel, error) {
+	channels, err := s.GuildChannels(app().GuildID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list guild channels: %w", err)
+	}
+	cutoff := time.Now().Add(-closedRetention())
+	closedCategoryID := getConfig().ClosedCategoryID
+	var stale []staleChannel
+	for _, ch := range channels {
+		if ch.ParentID != closedCategoryID || ch.Type != discordgo.ChannelTypeGuildText {
+			continue
+		}
+		closedAt, err := discordgo.SnowflakeTimestamp(ch.ID)
+		if err != nil {
+			continue
+		}
+		if t, err := findTicket(ch.ID); err == nil {
+			if t.Status == ticketStatusOpen {
+				continue
+			}
+			if !t.ClosedAt.IsZero() {
+				closedAt = t.ClosedAt
+			}
+		}
+		if closedAt.Before(cutoff) {
+			stale = append(stale, staleChannel{Channel: ch, ClosedAt: closedAt})
+		}
+	}
+	sort.Slice(stale, func(a, b int) bool { return stale[a].ClosedAt.Before(stale[b].ClosedAt) })
+	return stale, nil
+}
+
+func postClosedCleanupReport(s *discordgo.Session) error {
+	stale, err := findStaleClosedChannels(s)
+	if err != nil {
+		return err
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+	var lines []string
+	var buttons []discordgo.MessageComponent
+	for n, sc := range stale {
+		if n < maxCleanupButtons {
+			buttons = append(buttons, discordgo.Button{Label: sc.Channel.Name, Style: discordgo.SecondaryButton, CustomID: closedCleanupPrefix + sc.Channel.ID})
+		}
+		if n < 30 {
+			lines = append(lines, fmt.Sprintf("<#%s> · 종료 <t:%d:R>", sc.Channel.ID, sc.ClosedAt.Unix()))
+		}
+	}
+	if len(stale) > 30 {
+		lines = append(lines, fmt.Sprintf("…외 %d개", len(stale)-30))
+	}
+	var rows []discordgo.MessageComponent
+	for start := 0; start < len(buttons); start += 5 {
+		end := start + 5
+		if end > len(buttons) {
+			end = len(buttons)
+		}
+		rows = append(rows, discordgo.ActionsRow{Components: buttons[start:end]})
+	}
+	rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: fmt.Sprintf("지금 정리 (%d개 모두)", len(stale)), Style: discordgo.DangerButton, CustomID: closedCleanupPrefix + closedCleanupAll},
+	}})
+	_, err = s.ChannelMessageSendComplex(getConfig().LogChannelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       "닫힌 티켓 정리 필요",
+			Description: fmt.Sprintf("보관 기간(%d일)이 지났지만 삭제되지 않은 닫힌 티켓 채널입니다. 버튼을 누르면 대화록을 보낸 뒤 채널을 삭제합니다.\n\n%s", int(closedRetention().Hours()/24), strings.Join(lines, "\n")),
+			Color:       colorYellow,
+			Timestamp:   time.Now().In(kstLocation).Format(time.RFC3339),
+		}},
+		Components: rows,
+	})
+	if err != nil {
+		return fmt.Errorf("could not post cleanup report: %w", err)
+	}
+	log.Printf("Posted cleanup report for %d stale closed tickets.", len(stale))
+	return nil
+}
+
+func purgeClosedTicket(s *discordgo.Session, ch *discordgo.Channel) error {
+	if !allowDestructive(ch) {
+		return fmt.Errorf("sandbox mode only deletes test tickets, skipped %s", ch.Name)
+	}
+	createAndSendLog(s, ch)
+	if _, err := s.ChannelDelete(ch.ID); err != nil {
+		return fmt.Errorf("could not delete channel %s: %w", ch.Name, err)
+	}
+	if err := updateTicket(ch.ID, bson.M{"$set": bson.M{"status": ticketStatusDeleted}}); err != nil {
+		log.Printf("Error recording ticket deletion: %v", err)
+	}
+	if t := ticketForChannel(ch); t != nil {
+		publishTicketEvent(t, ticketEventDeleted)
+	}
+	return nil
+}
+
+func handleClosedCleanup(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	target := strings.TrimPrefix(i.MessageComponentData().CustomID, closedCleanupPrefix)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}})
+	stale, err := findStaleClosedChannels(s)
+	if err != nil {
+		log.Printf("Could not rescan closed tickets for cleanup: %v", err)
+	}
+	deleted, failed := 0, 0
+	for _, sc := range stale {
+		if target != closedCleanupAll && sc.Channel.ID != target {
+			continue
+		}
+		if err := purgeClosedTicket(s, sc.Channel); err != nil {
+			log.Printf("Cleanup failed: %v", err)
+			failed++
+			continue
+		}
+		deleted++
+	}
+	description := fmt.Sprintf("닫힌 티켓 채널 %d개를 정리했습니다.", deleted)
+	if failed > 0 {
+		description += fmt.Sprintf("\n%d개는 삭제하지 못했습니다. 로그를 확인해주세요.", failed)
+	}
+	if deleted == 0 && failed == 0 {
+		description = "정리할 채널이 없습니다. 이미 삭제되었거나 다시 열린 티켓입니다."
+	}
+	embeds := []*discordgo.MessageEmbed{{Title: "닫힌 티켓 정리", Description: description, Color: colorGreen}}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+	log.Printf("%s cleaned up %d stale closed tickets (%d failed).", i.Member.User.Username, deleted, failed)
+}
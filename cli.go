@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func runTranscriptCLI(args []string) {
+	fs := flag.NewFlagSet("transcript", flag.ExitOnError)
+	channelID := fs.String("channel", "", "ID of the ticket channel to render")
+	out := fs.String("out", "", "output HTML file (default transcript-<channel name>.html)")
+	theme := fs.String("theme", "", "transcript theme: dark, light or print (default from guild settings)")
+	compact := fs.Bool("compact", false, "group consecutive messages from the same author")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: potatobot transcript --channel <id> [--out file.html] [--theme dark|light|print] [--compact]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if *channelID == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if os.Getenv("MONGO_URI") != "" || storageBackend() != storageMongo {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := connectStore(ctx); err != nil {
+			log.Printf("Warning: %v. Rendering with default category settings.", err)
+		} else {
+			defer disconnectStore(context.Background())
+			if err := loadGuildConfig(app().GuildID); err != nil {
+				log.Printf("Warning: %v. Rendering with default category settings.", err)
+			}
+		}
+	}
+
+	s, err := discordgo.New("Bot " + os.Getenv("BOT_TOKEN"))
+	if err != nil {
+		log.Fatalf("Error creating Discord session: %v", err)
+	}
+	channel, err := s.Channel(*channelID)
+	if err != nil {
+		log.Fatalf("Could not fetch channel %s: %v", *channelID, err)
+	}
+	messages, err := fetchAllMessages(s, channel.ID)
+	if err != nil {
+		log.Fatalf("Could not fetch messages for #%s: %v", channel.Name, err)
+	}
+	fileName := *out
+	if fileName == "" {
+		fileName = fmt.Sprintf("transcript-%s.html", channel.Name)
+	}
+	style := defaultTranscriptStyle()
+	if *theme != "" {
+		name, ok := parseTranscriptTheme(*theme)
+		if !ok {
+			fs.Usage()
+			os.Exit(2)
+		}
+		style.Theme = name
+	}
+	if *compact {
+		style.Compact = true
+	}
+	if err := os.WriteFile(fileName, []byte(renderTranscript(channel, messages, style)), 0644); err != nil {
+		log.Fatalf("Could not write transcript file: %v", err)
+	}
+	log.Printf("Wrote transcript for #%s (%d messages) to %s", channel.Name, len(messages), fileName)
+}
+
+func runExportCLI(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	monthValue := fs.String("month", "", "month to export in YYYY-MM (KST)")
+	out := fs.String("out", "", "output zip file (default transcripts-<month>.zip)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: potatobot export --month 2026-01 [--out file.zip]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	month, err := parseExportMonth(*monthValue)
+	if err != nil {
+		fs.Usage()
+		os.Exit(2)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := connectStore(ctx); err != nil {
+		log.Fatalf("%v", err)
+	}
+	defer disconnectStore(context.Background())
+	fileName := *out
+	if fileName == "" {
+		fileName = fmt.Sprintf("transcripts-%s.zip", month.Format("2006-01"))
+	}
+	f, err := os.Create(fileName)
+	if err != nil {
+		log.Fatalf("Could not create export file: %v", err)
+	}
+	defer f.Close()
+	count, err := exportTranscripts(month, f, func(done, total int) {
+		log.Printf("Exported %d/%d transcripts", done, total)
+	})
+	if err != nil {
+		log.Fatalf("Transcript export failed: %v", err)
+	}
+	log.Printf("Wrote %d transcripts for %s to %s", count, month.Format("2006-01"), fileName)
+}
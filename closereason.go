@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	closeCodeSelectID      = "close_code_select"
+	closeReasonModalPrefix = "close_reason_submit:"
+
+	closeCodeResolved = "해결"
+)
+
+var closeCodeOptions = []discordgo.SelectMenuOption{
+	{Label: "해결", Value: closeCodeResolved, Description: "민원이 정상적으로 처리되었습니다.", Emoji: &discordgo.ComponentEmoji{Name: "✅"}},
+	{Label: "중복", Value: "중복", Description: "이미 접수된 다른 티켓과 같은 내용입니다.", Emoji: &discordgo.ComponentEmoji{Name: "🔁"}},
+	{Label: "스팸", Value: "스팸", Description: "장난이나 광고 등 부적절한 티켓입니다.", Emoji: &discordgo.ComponentEmoji{Name: "🚫"}},
+	{Label: "무응답", Value: "무응답", Description: "민원인이 응답하지 않아 종료합니다.", Emoji: &discordgo.ComponentEmoji{Name: "⌛"}},
+	{Label: "이관", Value: "이관", Description: "다른 부서나 창구로 넘겼습니다.", Emoji: &discordgo.ComponentEmoji{Name: "📤"}},
+}
+
+func isCloseCode(code string) bool {
+	for _, option := range closeCodeOptions {
+		if option.Value == code {
+			return true
+		}
+	}
+	return false
+}
+
+func sendCloseCodeSelect(s *discordgo.Session, i *discordgo.InteractionCreate, t *ticket) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "티켓 닫기", Description: "처리 결과에 맞는 종료 코드를 선택해주세요.\n선택 후 종료 사유를 입력하면 티켓이 닫힙니다.", Color: colorYellow}}, Components: []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.SelectMenu{CustomID: ticketComponentID(closeCodeSelectID, t), Placeholder: "종료 코드 선택", Options: closeCodeOptions}}},
+		discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.Button{Label: "취소", Style: discordgo.SecondaryButton, CustomID: "cancel_close_ticket"}}},
+	}}})
+}
+
+func handleCloseCodeSelect(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	code := i.MessageComponentData().Values[0]
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseModal, Data: closeReasonModal(code)})
+}
+
+func closeReasonModal(code string) *discordgo.InteractionResponseData {
+	return &discordgo.InteractionResponseData{
+		CustomID: closeReasonModalPrefix + code,
+		Title:    "티켓 닫기 - " + code,
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.TextInput{CustomID: "reason", Label: "종료 사유", Style: discordgo.TextInputShort, Placeholder: "예: 안내 완료, 담당 부서 이관", Required: true, MaxLength: 100}}},
+			discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.TextInput{CustomID: "resolution", Label: "처리 결과 요약", Style: discordgo.TextInputParagraph, Placeholder: "민원이 어떻게 처리되었는지 간단히 적어주세요.", Required: false, MaxLength: 1000}}},
+		},
+	}
+}
+
+func handleCloseReasonSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	if t.Status != ticketStatusOpen {
+		respondError(s, i, errTicketNotOpen, nil)
+		return
+	}
+	code := strings.TrimPrefix(i.ModalSubmitData().CustomID, closeReasonModalPrefix)
+	if !isCloseCode(code) {
+		return
+	}
+	values := make(map[string]string)
+	for _, row := range i.ModalSubmitData().Components {
+		for _, comp := range row.(*discordgo.ActionsRow).Components {
+			if input, ok := comp.(*discordgo.TextInput); ok {
+				values[input.CustomID] = strings.TrimSpace(input.Value)
+			}
+		}
+	}
+	t.CloseCode = code
+	t.CloseReason = values["reason"]
+	t.Resolution = values["resolution"]
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"close_code": t.CloseCode, "close_reason": t.CloseReason, "resolution": t.Resolution}}); err != nil {
+		respondError(s, i, errTicketSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "처리 중...", Description: "티켓을 닫고 보관 처리하고 있습니다.", Color: colorGray}}}})
+	closeTicketChannel(s, t, i.Member.User.ID, false)
+}
+
+func closeReasonFields(t *ticket) []*discordgo.MessageEmbedField {
+	if t == nil || (t.CloseCode == "" && t.CloseReason == "") {
+		return nil
+	}
+	var fields []*discordgo.MessageEmbedField
+	if t.CloseCode != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "종료 코드", Value: t.CloseCode, Inline: true})
+	}
+	if t.CloseReason != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "종료 사유", Value: t.CloseReason, Inline: false})
+	}
+	if t.Resolution != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "처리 결과", Value: t.Resolution, Inline: false})
+	}
+	return fields
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	actionCloseRequest    = "close_ticket_request"
+	actionClaim           = "claim_ticket"
+	actionConfirmSelf     = "confirm_self_close"
+	actionReopen          = "reopen_ticket"
+	actionDeletePermanent = "delete_ticket_permanent"
+)
+
+var ticketActionStatus = map[string]string{
+	actionCloseRequest:      ticketStatusOpen,
+	closeCodeSelectID:       ticketStatusOpen,
+	actionClaim:             ticketStatusOpen,
+	actionConfirmSelf:       ticketStatusOpen,
+	actionReopen:            ticketStatusClosed,
+	actionDeletePermanent:   ticketStatusClosed,
+	actionReleaseQuarantine: ticketStatusOpen,
+}
+
+func ticketComponentID(action string, t *ticket) string {
+	return action + ":" + t.Name()
+}
+
+func parseTicketComponentID(customID string) (string, string) {
+	action, ref, _ := strings.Cut(customID, ":")
+	return action, ref
+}
+
+func isTicketAction(customID string) bool {
+	action, _ := parseTicketComponentID(customID)
+	_, ok := ticketActionStatus[action]
+	return ok
+}
+
+func validateTicketComponent(s *discordgo.Session, i *discordgo.InteractionCreate, action, ref string) *ticket {
+	t := requireTicket(s, i)
+	if t == nil {
+		return nil
+	}
+	if ref != "" && ref != t.Name() {
+		respondError(s, i, errStaleComponent, nil)
+		return nil
+	}
+	if required := ticketActionStatus[action]; t.Status != required {
+		if required == ticketStatusOpen {
+			respondError(s, i, errTicketNotOpen, nil)
+		} else {
+			respondError(s, i, errTicketNotClosed, nil)
+		}
+		return nil
+	}
+	return t
+}
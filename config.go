@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type guildConfig struct {
+	GuildID               string                           `bson:"_id"`
+	OpenCategoryID        string                           `bson:"open_category_id"`
+	ClosedCategoryID      string                           `bson:"closed_category_id"`
+	LogChannelID          string                           `bson:"log_channel_id"`
+	DefaultSupportRoleID  string                           `bson:"default_support_role_id"`
+	CategorySupportRoles  map[string]string                `bson:"category_support_roles"`
+	CategoryFeatures      map[string]categoryFeatures      `bson:"category_features"`
+	PingThreshold         int                              `bson:"ping_threshold"`
+	PingAgentCount        int                              `bson:"ping_agent_count"`
+	IntakeQuestions       map[string][]intakeQuestion      `bson:"intake_questions,omitempty"`
+	Verification          intakeVerification               `bson:"verification"`
+	PostInteractionEvents bool                             `bson:"post_interaction_events"`
+	TicketMode            string                           `bson:"ticket_mode,omitempty"`
+	ForumChannelID        string                           `bson:"forum_channel_id,omitempty"`
+	StatusBoardChannelID  string                           `bson:"status_board_channel_id,omitempty"`
+	StatusBoardMessageID  string                           `bson:"status_board_message_id,omitempty"`
+	AgentSkills           map[string][]string              `bson:"agent_skills,omitempty"`
+	CategoryInactivity    map[string]inactivityPolicy      `bson:"category_inactivity,omitempty"`
+	WorkingLanguage       string                           `bson:"working_language,omitempty"`
+	CategorySLAs          map[string]categorySLA           `bson:"category_slas,omitempty"`
+	SLAEscalation         slaEscalation                    `bson:"sla_escalation"`
+	CategoryPins          map[string][]pinnedInfo          `bson:"category_pins,omitempty"`
+	CategoryAttachments   map[string]attachmentRequirement `bson:"category_attachments,omitempty"`
+	CategoryRequiredRoles map[string]string                `bson:"category_required_roles,omitempty"`
+	TranscriptStyle       transcriptStyle                  `bson:"transcript_style"`
+	ExportLocale          exportLocale                     `bson:"export_locale"`
+	RecycleTicketNumbers  bool                             `bson:"recycle_ticket_numbers"`
+	TicketCooldown        time.Duration                    `bson:"ticket_cooldown,omitempty"`
+	MaxOpenTickets        int                              `bson:"max_open_tickets,omitempty"`
+	RaidThrottle          raidThrottle                     `bson:"raid_throttle"`
+	Lockdown              intakeLockdown                   `bson:"lockdown"`
+	PanelMessages         []panelMessage                   `bson:"panel_messages,omitempty"`
+	SpamPolicy            spamPolicy                       `bson:"spam_policy"`
+	Reassignment          reassignPolicy                   `bson:"reassignment"`
+	Preset                string                           `bson:"preset,omitempty"`
+	InstitutionName       string                           `bson:"institution_name,omitempty"`
+	TicketCodePrefix      string                           `bson:"ticket_code_prefix,omitempty"`
+	Topics                []ticketTopic                    `bson:"topics,omitempty"`
+	Greeting              string                           `bson:"greeting,omitempty"`
+	ClosedRetentionDays   int                              `bson:"closed_retention_days,omitempty"`
+	RecordVoiceSessions   bool                             `bson:"record_voice_sessions"`
+	LeaderboardPostedWeek time.Time                        `bson:"leaderboard_posted_week,omitempty"`
+	ExternalAlerts        map[string]externalSink          `bson:"external_alerts,omitempty"`
+	Sandbox               sandboxMode                      `bson:"sandbox"`
+	CategoryButtons       map[string][]greetingButton      `bson:"category_buttons,omitempty"`
+}
+
+var (
+	configMu      sync.RWMutex
+	currentConfig guildConfig
+)
+
+func defaultGuildConfig(id string) guildConfig {
+	return guildConfig{
+		GuildID:              id,
+		OpenCategoryID:       "1398719413016072306",
+		ClosedCategoryID:     "1398719595384406137",
+		LogChannelID:         "1397260754482237652",
+		DefaultSupportRoleID: "1397231132579467294",
+		CategorySupportRoles: map[string]string{
+			"일반민원": "1397231132579467294",
+			"법률구조": "1397231132579467294",
+			"부패신고": "1397981755847217325",
+		},
+		CategoryFeatures: map[string]categoryFeatures{
+			"일반민원": {Transcripts: true, CSAT: true, AutoAssign: false, Anonymous: false},
+			"법률구조": {Transcripts: true, CSAT: true, AutoAssign: false, Anonymous: false},
+			"부패신고": {Transcripts: false, CSAT: false, AutoAssign: false, Anonymous: true},
+		},
+		PingThreshold:  defaultPingThreshold,
+		PingAgentCount: defaultPingAgentCount,
+	}
+}
+
+func loadGuildConfig(id string) error {
+	var cfg guildConfig
+	err := app().Configs.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&cfg)
+	if err == mongo.ErrNoDocuments {
+		log.Printf("Warning: No guild_config document for guild %s. Seeding defaults; use /설정 to adjust them.", id)
+		cfg = defaultGuildConfig(id)
+		if _, err := app().Configs.InsertOne(context.TODO(), cfg); err != nil {
+			return fmt.Errorf("could not seed guild config for '%s': %w", id, err)
+		}
+	} else if err != nil {
+		return fmt.Errorf("could not load guild config for '%s': %w", id, err)
+	}
+	if cfg.CategorySupportRoles == nil {
+		cfg.CategorySupportRoles = map[string]string{}
+	}
+	if cfg.CategoryFeatures == nil {
+		cfg.CategoryFeatures = map[string]categoryFeatures{}
+	}
+	if cfg.IntakeQuestions == nil {
+		cfg.IntakeQuestions = map[string][]intakeQuestion{}
+	}
+	if cfg.AgentSkills == nil {
+		cfg.AgentSkills = map[string][]string{}
+	}
+	if cfg.CategoryInactivity == nil {
+		cfg.CategoryInactivity = map[string]inactivityPolicy{}
+	}
+	if cfg.CategorySLAs == nil {
+		cfg.CategorySLAs = map[string]categorySLA{}
+	}
+	if cfg.CategoryPins == nil {
+		cfg.CategoryPins = map[string][]pinnedInfo{}
+	}
+	if cfg.CategoryAttachments == nil {
+		cfg.CategoryAttachments = map[string]attachmentRequirement{}
+	}
+	if cfg.CategoryRequiredRoles == nil {
+		cfg.CategoryRequiredRoles = map[string]string{}
+	}
+	if cfg.ExternalAlerts == nil {
+		cfg.ExternalAlerts = map[string]externalSink{}
+	}
+	if cfg.CategoryButtons == nil {
+		cfg.CategoryButtons = map[string][]greetingButton{}
+	}
+	configMu.Lock()
+	currentConfig = cfg
+	configMu.Unlock()
+	log.Printf("Loaded configuration for guild %s.", id)
+	return nil
+}
+
+func getConfig() guildConfig {
+	configMu.RLock()
+	defer configMu.RUnlock()
+	return currentConfig
+}
+
+var saveGuildConfig = func(cfg guildConfig) error {
+	_, err := app().Configs.ReplaceOne(context.TODO(), bson.M{"_id": cfg.GuildID}, cfg, options.Replace().SetUpsert(true))
+	return err
+}
+
+func updateConfig(apply func(cfg *guildConfig)) error {
+	configMu.Lock()
+	defer configMu.Unlock()
+	cfg := cloneGuildConfig(currentConfig)
+	apply(&cfg)
+	if err := saveGuildConfig(cfg); err != nil {
+		return fmt.Errorf("could not save guild config for '%s': %w", cfg.GuildID, err)
+	}
+	currentConfig = cfg
+	return nil
+}
+
+func cloneGuildConfig(src guildConfig) guildConfig {
+	cfg := src
+	cfg.CategorySupportRoles = make(map[string]string, len(src.CategorySupportRoles))
+	for k, v := range src.CategorySupportRoles {
+		cfg.CategorySupportRoles[k] = v
+	}
+	cfg.CategoryFeatures = make(map[string]categoryFeatures, len(src.CategoryFeatures))
+	for k, v := range src.CategoryFeatures {
+		cfg.CategoryFeatures[k] = v
+	}
+	cfg.IntakeQuestions = make(map[string][]intakeQuestion, len(src.IntakeQuestions))
+	for k, v := range src.IntakeQuestions {
+		cfg.IntakeQuestions[k] = append([]intakeQuestion(nil), v...)
+	}
+	cfg.AgentSkills = make(map[string][]string, len(src.AgentSkills))
+	for k, v := range src.AgentSkills {
+		cfg.AgentSkills[k] = append([]string(nil), v...)
+	}
+	cfg.CategoryInactivity = make(map[string]inactivityPolicy, len(src.CategoryInactivity))
+	for k, v := range src.CategoryInactivity {
+		cfg.CategoryInactivity[k] = v
+	}
+	cfg.CategorySLAs = make(map[string]categorySLA, len(src.CategorySLAs))
+	for k, v := range src.CategorySLAs {
+		cfg.CategorySLAs[k] = v
+	}
+	cfg.CategoryPins = make(map[string][]pinnedInfo, len(src.CategoryPins))
+	for k, v := range src.CategoryPins {
+		cfg.CategoryPins[k] = append([]pinnedInfo(nil), v...)
+	}
+	cfg.CategoryAttachments = make(map[string]attachmentRequirement, len(src.CategoryAttachments))
+	for k, v := range src.CategoryAttachments {
+		v.Types = append([]string(nil), v.Types...)
+		cfg.CategoryAttachments[k] = v
+	}
+	cfg.CategoryRequiredRoles = make(map[string]string, len(src.CategoryRequiredRoles))
+	for k, v := range src.CategoryRequiredRoles {
+		cfg.CategoryRequiredRoles[k] = v
+	}
+	cfg.ExternalAlerts = make(map[string]externalSink, len(src.ExternalAlerts))
+	for k, v := range src.ExternalAlerts {
+		cfg.ExternalAlerts[k] = v
+	}
+	cfg.CategoryButtons = make(map[string][]greetingButton, len(src.CategoryButtons))
+	for k, v := range src.CategoryButtons {
+		cfg.CategoryButtons[k] = append([]greetingButton(nil), v...)
+	}
+	cfg.Topics = append([]ticketTopic(nil), src.Topics...)
+	cfg.PanelMessages = append([]panelMessage(nil), src.PanelMessages...)
+	return cfg
+}
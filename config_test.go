@@ -0,0 +1,106 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func useTestConfig(t *testing.T, cfg guildConfig) {
+	t.Helper()
+	previousConfig, previousSave := getConfig(), saveGuildConfig
+	saveGuildConfig = func(guildConfig) error { return nil }
+	configMu.Lock()
+	currentConfig = cfg
+	configMu.Unlock()
+	t.Cleanup(func() {
+		saveGuildConfig = previousSave
+		configMu.Lock()
+		currentConfig = previousConfig
+		configMu.Unlock()
+	})
+}
+
+func TestUpdateConfigCopiesMaps(t *testing.T) {
+	useTestConfig(t, guildConfig{
+		GuildID:             "guild",
+		ExternalAlerts:      map[string]externalSink{externalAlertGuildKey: {Kind: externalSinkWebhook, Target: "https://example.com/hook"}},
+		CategoryAttachments: map[string]attachmentRequirement{"신고": {MinFiles: 1, Types: []string{"image"}}},
+		CategoryButtons:     map[string][]greetingButton{"신고": {{ID: "a", Label: "안내"}}},
+	})
+	before := getConfig()
+	err := updateConfig(func(cfg *guildConfig) {
+		cfg.ExternalAlerts["user"] = externalSink{Kind: externalSinkTelegram, Target: "@alerts"}
+		delete(cfg.ExternalAlerts, externalAlertGuildKey)
+		cfg.CategoryAttachments["신고"].Types[0] = "video"
+		cfg.CategoryButtons["신고"][0].Label = "변경"
+	})
+	if err != nil {
+		t.Fatalf("updateConfig: %v", err)
+	}
+	if _, ok := before.ExternalAlerts[externalAlertGuildKey]; !ok || len(before.ExternalAlerts) != 1 {
+		t.Errorf("earlier snapshot ExternalAlerts changed: %v", before.ExternalAlerts)
+	}
+	if got := before.CategoryAttachments["신고"].Types[0]; got != "image" {
+		t.Errorf("earlier snapshot CategoryAttachments types = %q, want image", got)
+	}
+	if got := before.CategoryButtons["신고"][0].Label; got != "안내" {
+		t.Errorf("earlier snapshot CategoryButtons label = %q, want 안내", got)
+	}
+	after := getConfig()
+	if _, ok := after.ExternalAlerts["user"]; !ok || len(after.ExternalAlerts) != 1 {
+		t.Errorf("updated ExternalAlerts = %v", after.ExternalAlerts)
+	}
+	if got := after.CategoryAttachments["신고"].Types[0]; got != "video" {
+		t.Errorf("updated CategoryAttachments types = %q, want video", got)
+	}
+}
+
+func TestUpdateConfigKeepsConfigOnSaveError(t *testing.T) {
+	useTestConfig(t, guildConfig{GuildID: "guild", ExternalAlerts: map[string]externalSink{}})
+	saveGuildConfig = func(guildConfig) error { return errors.New("unavailable") }
+	if err := updateConfig(func(cfg *guildConfig) { cfg.ExternalAlerts["user"] = externalSink{Kind: externalSinkWebhook} }); err == nil {
+		t.Fatal("updateConfig succeeded although saving failed")
+	}
+	if got := len(getConfig().ExternalAlerts); got != 0 {
+		t.Fatalf("ExternalAlerts has %d entries after a failed save, want 0", got)
+	}
+}
+
+func TestUpdateConfigConcurrent(t *testing.T) {
+	useTestConfig(t, guildConfig{
+		GuildID:             "guild",
+		ExternalAlerts:      map[string]externalSink{},
+		CategoryAttachments: map[string]attachmentRequirement{"신고": {Types: []string{"image"}}},
+	})
+	const writers = 20
+	var wg sync.WaitGroup
+	for n := 0; n < writers; n++ {
+		wg.Add(2)
+		go func(n int) {
+			defer wg.Done()
+			err := updateConfig(func(cfg *guildConfig) {
+				cfg.ExternalAlerts[fmt.Sprint(n)] = externalSink{Kind: externalSinkWebhook, Target: "https://example.com"}
+				req := cfg.CategoryAttachments["신고"]
+				req.Types[0] = fmt.Sprint(n)
+				cfg.CategoryAttachments["신고"] = req
+			})
+			if err != nil {
+				t.Errorf("updateConfig: %v", err)
+			}
+		}(n)
+		go func() {
+			defer wg.Done()
+			cfg := getConfig()
+			for key, sink := range cfg.ExternalAlerts {
+				_ = key + sink.Target
+			}
+			_ = cfg.CategoryAttachments["신고"].Types[0]
+		}()
+	}
+	wg.Wait()
+	if got := len(getConfig().ExternalAlerts); got != writers {
+		t.Fatalf("ExternalAlerts has %d entries after %d updates, want %d", got, writers, writers)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ticketCooldown struct {
+	UserID string    `bson:"_id"`
+	Until  time.Time `bson:"until"`
+}
+
+func ticketCooldownRemaining(userID string) time.Duration {
+	var c ticketCooldown
+	if err := app().Cooldowns.FindOne(context.TODO(), bson.M{"_id": userID}).Decode(&c); err != nil {
+		if err != mongo.ErrNoDocuments {
+			log.Printf("Could not read ticket cooldown for %s: %v", userID, err)
+		}
+		return 0
+	}
+	return time.Until(c.Until)
+}
+
+func claimTicketCooldown(userID string, cooldown time.Duration) (time.Duration, bool) {
+	now := time.Now()
+	filter := bson.M{"_id": userID, "until": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"until": now.Add(cooldown)}}
+	_, err := app().Cooldowns.UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return ticketCooldownRemaining(userID), false
+	}
+	if err != nil {
+		log.Printf("Could not record ticket cooldown for %s: %v", userID, err)
+	}
+	return 0, true
+}
+
+func clearTicketCooldown(userID string) {
+	if _, err := app().Cooldowns.DeleteOne(context.TODO(), bson.M{"_id": userID}); err != nil {
+		log.Printf("Could not clear ticket cooldown for %s: %v", userID, err)
+	}
+}
+
+func cooldownApplies(i *discordgo.InteractionCreate) bool {
+	return getConfig().TicketCooldown > 0 && !hasSupportRole(i.Member)
+}
+
+func checkTicketCooldown(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	if !cooldownApplies(i) {
+		return true
+	}
+	if remaining := ticketCooldownRemaining(i.Member.User.ID); remaining > 0 {
+		respondTicketCooldown(s, i, remaining)
+		return false
+	}
+	return true
+}
+
+func respondTicketCooldown(s *discordgo.Session, i *discordgo.InteractionCreate, remaining time.Duration) {
+	respondError(s, i, errTicketCooldown, nil, formatWait(remaining), time.Now().Add(remaining).Unix())
+}
+
+func cooldownLabel(d time.Duration) string {
+	if d <= 0 {
+		return "사용 안 함"
+	}
+	return formatSLADuration(d) + "에 1건"
+}
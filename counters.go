@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	reservationReserved = "reserved"
+	reservationReleased = "released"
+	maxAuditGapsShown   = 20
+	staleReservationAge = time.Hour
+)
+
+type numberReservation struct {
+	ID         string    `bson:"_id"`
+	Category   string    `bson:"category"`
+	Number     uint64    `bson:"number"`
+	Status     string    `bson:"status"`
+	ReservedAt time.Time `bson:"reserved_at"`
+}
+
+type counterAudit struct {
+	Category string
+	Issued   uint64
+	Created  int
+	Gaps     []uint64
+	Released int
+	Pending  int
+}
+
+func reservationID(category string, number uint64) string {
+	return fmt.Sprintf("%s-%04d", category, number)
+}
+
+func reserveTicketNumber(category string) (uint64, error) {
+	if getConfig().RecycleTicketNumbers {
+		if number, ok := claimReleasedNumber(category); ok {
+			return number, nil
+		}
+	}
+	number, err := getNextSequenceValue(category)
+	if err != nil {
+		return 0, err
+	}
+	doc := numberReservation{ID: reservationID(category, number), Category: category, Number: number, Status: reservationReserved, ReservedAt: time.Now()}
+	if _, err := app().Reservations.ReplaceOne(context.TODO(), bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true)); err != nil {
+		log.Printf("Could not record reservation of ticket number %s: %v", doc.ID, err)
+	}
+	return number, nil
+}
+
+func claimReleasedNumber(category string) (uint64, bool) {
+	for {
+		var doc numberReservation
+		filter := bson.M{"category": category, "status": reservationReleased}
+		update := bson.M{"$set": bson.M{"status": reservationReserved, "reserved_at": time.Now()}}
+		err := app().Reservations.FindOneAndUpdate(context.TODO(), filter, update, options.FindOneAndUpdate().SetSort(bson.M{"number": 1})).Decode(&doc)
+		if err == mongo.ErrNoDocuments {
+			return 0, false
+		}
+		if err != nil {
+			log.Printf("Could not claim a released ticket number for '%s': %v", category, err)
+			return 0, false
+		}
+		count, err := app().Tickets.CountDocuments(context.TODO(), bson.M{"category": category, "number": doc.Number})
+		if err != nil {
+			log.Printf("Could not check recycled ticket number %s: %v", doc.ID, err)
+			releaseTicketNumber(category, doc.Number)
+			return 0, false
+		}
+		if count == 0 {
+			log.Printf("Recycling ticket number %s.", doc.ID)
+			return doc.Number, true
+		}
+		app().Reservations.DeleteOne(context.TODO(), bson.M{"_id": doc.ID})
+	}
+}
+
+func issueTicketNumber(category string, number uint64) {
+	if _, err := app().Reservations.DeleteOne(context.TODO(), bson.M{"_id": reservationID(category, number)}); err != nil {
+		log.Printf("Could not clear reservation of ticket number %s: %v", reservationID(category, number), err)
+	}
+}
+
+func releaseTicketNumber(category string, number uint64) {
+	id := reservationID(category, number)
+	if _, err := app().Reservations.UpdateOne(context.TODO(), bson.M{"_id": id}, bson.M{"$set": bson.M{"status": reservationReleased}}); err != nil {
+		log.Printf("Could not release ticket number %s: %v", id, err)
+		return
+	}
+	log.Printf("Released unused ticket number %s.", id)
+}
+
+func auditTicketCounter(category string) (counterAudit, error) {
+	audit := counterAudit{Category: category}
+	var c counter
+	err := app().Counters.FindOne(context.TODO(), bson.M{"_id": category}).Decode(&c)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return audit, fmt.Errorf("could not read counter for '%s': %w", category, err)
+	}
+	audit.Issued = c.Seq
+	numbers, err := app().Tickets.Distinct(context.TODO(), "number", bson.M{"category": category})
+	if err != nil {
+		return audit, fmt.Errorf("could not list tickets for '%s': %w", category, err)
+	}
+	created := make(map[uint64]bool, len(numbers))
+	for _, n := range numbers {
+		switch v := n.(type) {
+		case int64:
+			created[uint64(v)] = true
+		case int32:
+			created[uint64(v)] = true
+		}
+	}
+	audit.Created = len(created)
+	cursor, err := app().Reservations.Find(context.TODO(), bson.M{"category": category})
+	if err != nil {
+		return audit, fmt.Errorf("could not list reservations for '%s': %w", category, err)
+	}
+	var reservations []numberReservation
+	if err := cursor.All(context.TODO(), &reservations); err != nil {
+		return audit, fmt.Errorf("could not decode reservations for '%s': %w", category, err)
+	}
+	pending := make(map[uint64]bool)
+	for _, r := range reservations {
+		if r.Status == reservationReleased {
+			pending[r.Number] = true
+			audit.Released++
+			continue
+		}
+		if time.Since(r.ReservedAt) < staleReservationAge {
+			pending[r.Number] = true
+			audit.Pending++
+		}
+	}
+	for n := uint64(1); n <= audit.Issued; n++ {
+		if !created[n] && !pending[n] {
+			audit.Gaps = append(audit.Gaps, n)
+		}
+	}
+	return audit, nil
+}
+
+func releaseCounterGaps(audit counterAudit) (int, error) {
+	released := 0
+	for _, n := range audit.Gaps {
+		doc := numberReservation{ID: reservationID(audit.Category, n), Category: audit.Category, Number: n, Status: reservationReleased, ReservedAt: time.Now()}
+		if _, err := app().Reservations.ReplaceOne(context.TODO(), bson.M{"_id": doc.ID}, doc, options.Replace().SetUpsert(true)); err != nil {
+			return released, err
+		}
+		released++
+	}
+	return released, nil
+}
+
+func counterAuditCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "번호점검",
+		Description:              "발급된 티켓 번호와 실제 생성된 티켓을 비교해 빠진 번호를 찾습니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구 (기본: 전체)", Required: false, Choices: ticketTopicChoices()},
+			{Type: discordgo.ApplicationCommandOptionBoolean, Name: "release", Description: "빠진 번호를 재사용 대기열에 넣습니다", Required: false},
+		},
+	}
+}
+
+func handleCounterAudit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	var topics []string
+	if opt, ok := options["topic"]; ok {
+		topics = append(topics, opt.StringValue())
+	} else {
+		for _, option := range ticketOptions() {
+			topics = append(topics, option.Value)
+		}
+	}
+	release := false
+	if opt, ok := options["release"]; ok {
+		release = opt.BoolValue()
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}})
+	embed := &discordgo.MessageEmbed{Title: "티켓 번호 점검", Color: colorGreen}
+	for _, topic := range topics {
+		audit, err := auditTicketCounter(topic)
+		if err != nil {
+			log.Printf("Counter audit for '%s' failed: %v", topic, err)
+			embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: topic, Value: fmt.Sprintf("점검하지 못했습니다: %v", err)})
+			embed.Color = colorRed
+			continue
+		}
+		value := fmt.Sprintf("발급 %d · 생성 %d · 진행 중 %d · 재사용 대기 %d", audit.Issued, audit.Created, audit.Pending, audit.Released)
+		if len(audit.Gaps) > 0 {
+			if embed.Color == colorGreen {
+				embed.Color = colorYellow
+			}
+			value += fmt.Sprintf("\n빠진 번호 %d개: %s", len(audit.Gaps), formatGaps(audit.Gaps))
+			if release {
+				released, err := releaseCounterGaps(audit)
+				if err != nil {
+					log.Printf("Could not release gaps for '%s': %v", topic, err)
+				}
+				value += fmt.Sprintf("\n%d개를 재사용 대기열에 넣었습니다.", released)
+			}
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: topic, Value: value})
+	}
+	if release && !getConfig().RecycleTicketNumbers {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: "번호 재사용이 꺼져 있습니다. /설정 번호재사용으로 켜야 대기열의 번호가 다시 발급됩니다."}
+	}
+	embeds := []*discordgo.MessageEmbed{embed}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+}
+
+func formatGaps(gaps []uint64) string {
+	shown := gaps
+	if len(shown) > maxAuditGapsShown {
+		shown = shown[:maxAuditGapsShown]
+	}
+	parts := make([]string, len(shown))
+	for n, gap := range shown {
+		parts[n] = fmt.Sprintf("%04d", gap)
+	}
+	text := strings.Join(parts, ", ")
+	if len(gaps) > len(shown) {
+		text += fmt.Sprintf(" 외 %d개", len(gaps)-len(shown))
+	}
+	return text
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func sendCSATPrompt(s *discordgo.Session, t *ticket) {
+	dm, err := s.UserChannelCreate(t.OwnerID)
+	if err != nil {
+		log.Printf("Could not open DM for CSAT prompt to %s: %v", t.OwnerID, err)
+		return
+	}
+	var buttons []discordgo.MessageComponent
+	for rating := 1; rating <= 5; rating++ {
+		buttons = append(buttons, discordgo.Button{
+			Label:    strings.Repeat("⭐", rating),
+			Style:    discordgo.SecondaryButton,
+			CustomID: fmt.Sprintf("csat_rate:%s:%d", t.ChannelID, rating),
+		})
+	}
+	_, err = s.ChannelMessageSendComplex(dm.ID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       "민원 처리 만족도 조사",
+			Description: fmt.Sprintf("`%s` 민원이 종료되었습니다.\n상담은 만족스러우셨나요? 아래 버튼으로 평가해주세요.", t.Name()),
+			Color:       colorBlue,
+		}},
+		Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}},
+	})
+	if err != nil {
+		log.Printf("Could not send CSAT prompt to %s: %v", t.OwnerID, err)
+		return
+	}
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"csat_sent_at": time.Now()}}); err != nil {
+		log.Printf("Could not record CSAT prompt for '%s': %v", t.Name(), err)
+	}
+}
+
+func handleCSATRating(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	channelID := parts[1]
+	rating, _ := strconv.Atoi(parts[2])
+	err := updateTicket(channelID, bson.M{"$set": bson.M{"rating": rating, "rated_at": time.Now()}})
+	if err != nil {
+		respondError(s, i, errCSATSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{
+		Embeds: []*discordgo.MessageEmbed{{Title: "평가해주셔서 감사합니다", Description: fmt.Sprintf("만족도 %s 평가가 기록되었습니다.\n추가 의견이 있으시면 아래 버튼을 눌러 남겨주세요.", strings.Repeat("⭐", rating)), Color: colorGreen}},
+		Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "의견 남기기", Style: discordgo.PrimaryButton, CustomID: "csat_comment:" + channelID},
+		}}},
+	}})
+}
+
+func handleCSATCommentButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	channelID := strings.TrimPrefix(i.MessageComponentData().CustomID, "csat_comment:")
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseModal,
+		Data: &discordgo.InteractionResponseData{
+			CustomID: "csat_comment_submit:" + channelID,
+			Title:    "추가 의견",
+			Components: []discordgo.MessageComponent{
+				discordgo.ActionsRow{
+					Components: []discordgo.MessageComponent{
+						discordgo.TextInput{
+							CustomID:    "comment",
+							Label:       "의견",
+							Style:       discordgo.TextInputParagraph,
+							Placeholder: "민원 처리 과정에 대한 의견을 자유롭게 적어주세요.",
+							Required:    true,
+							MaxLength:   1000,
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Error responding with CSAT comment modal: %v", err)
+	}
+}
+
+func handleCSATCommentSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ModalSubmitData()
+	channelID := strings.TrimPrefix(data.CustomID, "csat_comment_submit:")
+	comment := data.Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
+	err := updateTicket(channelID, bson.M{"$set": bson.M{"comment": comment}})
+	if err != nil {
+		respondError(s, i, errCSATSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{
+		Embeds:     []*discordgo.MessageEmbed{{Title: "의견이 접수되었습니다", Description: "소중한 의견 감사합니다. 더 나은 민원 서비스를 위해 참고하겠습니다.", Color: colorGreen}},
+		Components: []discordgo.MessageComponent{},
+	}})
+}
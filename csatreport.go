@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const maxRecentCSATComments = 5
+
+var reportPeriodChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "최근 7일", Value: 7},
+	{Name: "최근 30일", Value: 30},
+	{Name: "최근 90일", Value: 90},
+	{Name: "최근 1년", Value: 365},
+}
+
+type csatBucket struct {
+	Prompted int
+	Rated    int
+	Sum      int
+}
+
+func (b *csatBucket) add(t ticket) {
+	b.Prompted++
+	if t.Rating > 0 {
+		b.Rated++
+		b.Sum += t.Rating
+	}
+}
+
+func (b *csatBucket) summary() string {
+	if b.Rated == 0 {
+		return fmt.Sprintf("응답 없음 (발송 %d건)", b.Prompted)
+	}
+	return fmt.Sprintf("⭐ %.2f · 응답 %d/%d건 (%.0f%%)", float64(b.Sum)/float64(b.Rated), b.Rated, b.Prompted, float64(b.Rated)*100/float64(b.Prompted))
+}
+
+func reportPeriod(options map[string]*discordgo.ApplicationCommandInteractionDataOption) (int, time.Time) {
+	days := 30
+	if opt, ok := options["period"]; ok {
+		days = int(opt.IntValue())
+	}
+	return days, startOfDayKST(time.Now()).AddDate(0, 0, -days+1)
+}
+
+func handleCSATReport(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	days, since := reportPeriod(options)
+	filter := bson.M{"csat_sent_at": bson.M{"$gte": since}}
+	category := ""
+	if opt, ok := options["topic"]; ok {
+		category = opt.StringValue()
+		filter["category"] = category
+	}
+	cursor, err := app().Tickets.Find(context.TODO(), filter)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	total := &csatBucket{}
+	byCategory := make(map[string]*csatBucket)
+	byAssignee := make(map[string]*csatBucket)
+	var commented []ticket
+	for _, t := range tickets {
+		total.add(t)
+		if byCategory[t.Category] == nil {
+			byCategory[t.Category] = &csatBucket{}
+		}
+		byCategory[t.Category].add(t)
+		if t.AssigneeID != "" {
+			if byAssignee[t.AssigneeID] == nil {
+				byAssignee[t.AssigneeID] = &csatBucket{}
+			}
+			byAssignee[t.AssigneeID].add(t)
+		}
+		if t.Comment != "" {
+			commented = append(commented, t)
+		}
+	}
+	title := fmt.Sprintf("만족도 조사 결과 (최근 %d일)", days)
+	if category != "" {
+		title = fmt.Sprintf("%s 만족도 조사 결과 (최근 %d일)", category, days)
+	}
+	embed := &discordgo.MessageEmbed{Title: title, Description: "전체: " + total.summary(), Color: colorBlue, Timestamp: time.Now().In(kstLocation).Format(time.RFC3339)}
+	if total.Prompted == 0 {
+		embed.Description = "해당 기간에 발송된 만족도 조사가 없습니다."
+	}
+	var categoryLines []string
+	for _, option := range ticketOptions() {
+		if b, ok := byCategory[option.Value]; ok {
+			categoryLines = append(categoryLines, fmt.Sprintf("**%s**: %s", option.Value, b.summary()))
+		}
+	}
+	if len(categoryLines) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "창구별", Value: strings.Join(categoryLines, "\n"), Inline: false})
+	}
+	assignees := make([]string, 0, len(byAssignee))
+	for id := range byAssignee {
+		assignees = append(assignees, id)
+	}
+	sort.Slice(assignees, func(a, b int) bool { return byAssignee[assignees[a]].Rated > byAssignee[assignees[b]].Rated })
+	var assigneeLines []string
+	for n, id := range assignees {
+		if n >= 10 {
+			break
+		}
+		assigneeLines = append(assigneeLines, fmt.Sprintf("<@%s>: %s", id, byAssignee[id].summary()))
+	}
+	if len(assigneeLines) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "담당자별", Value: strings.Join(assigneeLines, "\n"), Inline: false})
+	}
+	sort.Slice(commented, func(a, b int) bool { return commented[a].RatedAt.After(commented[b].RatedAt) })
+	var commentLines []string
+	for n, t := range commented {
+		if n >= maxRecentCSATComments {
+			break
+		}
+		comment := t.Comment
+		if len([]rune(comment)) > 150 {
+			comment = string([]rune(comment)[:150]) + "…"
+		}
+		commentLines = append(commentLines, fmt.Sprintf("`%s` %s\n> %s", t.Name(), strings.Repeat("⭐", t.Rating), strings.ReplaceAll(comment, "\n", " ")))
+	}
+	if len(commentLines) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "최근 의견", Value: strings.Join(commentLines, "\n"), Inline: false})
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
+)
+
+const debugTokenHeader = "X-Debug-Token"
+
+func registerDebugHandlers(mux *http.ServeMux) {
+	token := os.Getenv("DEBUG_TOKEN")
+	if token == "" {
+		log.Println("DEBUG_TOKEN is not set; pprof endpoints are disabled.")
+		return
+	}
+	guard := func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get(debugTokenHeader)), []byte(token)) != 1 {
+				http.NotFound(w, r)
+				return
+			}
+			h(w, r)
+		}
+	}
+	mux.HandleFunc("/debug/pprof/", guard(pprof.Index))
+	mux.HandleFunc("/debug/pprof/cmdline", guard(pprof.Cmdline))
+	mux.HandleFunc("/debug/pprof/profile", guard(pprof.Profile))
+	mux.HandleFunc("/debug/pprof/symbol", guard(pprof.Symbol))
+	mux.HandleFunc("/debug/pprof/trace", guard(pprof.Trace))
+	log.Println("pprof endpoints are enabled under /debug/pprof/.")
+}
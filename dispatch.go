@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultPingThreshold  = 5
+	defaultPingAgentCount = 2
+)
+
+func activeClaimLoads(topic string) (map[string]int, int, error) {
+	cursor, err := app().Tickets.Find(context.TODO(), bson.M{"status": ticketStatusOpen})
+	if err != nil {
+		return nil, 0, err
+	}
+	var open []ticket
+	if err := cursor.All(context.TODO(), &open); err != nil {
+		return nil, 0, err
+	}
+	loads := make(map[string]int)
+	unclaimed := 0
+	for _, t := range open {
+		if t.AssigneeID != "" {
+			loads[t.AssigneeID]++
+		} else if t.Category == topic {
+			unclaimed++
+		}
+	}
+	return loads, unclaimed, nil
+}
+
+func supportPingContent(s *discordgo.Session, topic, supportRoleID string) string {
+	rolePing := fmt.Sprintf("<@&%s>", supportRoleID)
+	cfg := getConfig()
+	threshold, agentCount := cfg.PingThreshold, cfg.PingAgentCount
+	if threshold <= 0 {
+		threshold = defaultPingThreshold
+	}
+	if agentCount <= 0 {
+		agentCount = defaultPingAgentCount
+	}
+	loads, unclaimed, err := activeClaimLoads(topic)
+	if err != nil {
+		log.Printf("Could not load claim data for support ping: %v", err)
+		return rolePing
+	}
+	if unclaimed < threshold {
+		return rolePing
+	}
+	agents := onDutyAgents(s, supportRoleID)
+	if len(agents) == 0 {
+		return rolePing
+	}
+	sort.SliceStable(agents, func(a, b int) bool { return loads[agents[a]] < loads[agents[b]] })
+	if len(agents) > agentCount {
+		agents = agents[:agentCount]
+	}
+	var mentions []string
+	for _, id := range agents {
+		mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+	}
+	log.Printf("Category '%s' has %d unclaimed tickets; pinging %d least-loaded agents instead of the role.", topic, unclaimed, len(agents))
+	return strings.Join(mentions, " ")
+}
+
+func onDutyAgents(s *discordgo.Session, roleID string) []string {
+	var agents []string
+	after := ""
+	for {
+		members, err := s.GuildMembers(app().GuildID, after, 1000)
+		if err != nil {
+			log.Printf("Could not list guild members for support ping: %v", err)
+			return agents
+		}
+		for _, member := range members {
+			if member.User.Bot || !memberHasRole(member, roleID) {
+				continue
+			}
+			presence, err := s.State.Presence(app().GuildID, member.User.ID)
+			if err != nil || presence.Status == discordgo.StatusOffline || presence.Status == discordgo.StatusInvisible {
+				continue
+			}
+			agents = append(agents, member.User.ID)
+		}
+		if len(members) < 1000 {
+			return agents
+		}
+		after = members[len(members)-1].User.ID
+	}
+}
+
+func memberHasRole(member *discordgo.Member, roleID string) bool {
+	for _, id := range member.Roles {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	errorReportQueueSize   = 100
+	errorReportTimeout     = 5 * time.Second
+	errorReportDedupWindow = time.Minute
+)
+
+type errorReport struct {
+	Kind    string            `json:"kind"`
+	Message string            `json:"message"`
+	Stack   string            `json:"stack,omitempty"`
+	Tags    map[string]string `json:"tags"`
+	Time    time.Time         `json:"time"`
+}
+
+type sentryTarget struct {
+	StoreURL string
+	Auth     string
+}
+
+var (
+	errorReports    chan errorReport
+	errorReportOnce sync.Once
+	errorReportMu   sync.Mutex
+	errorReportSeen = make(map[string]time.Time)
+	errorReportHTTP = &http.Client{Timeout: errorReportTimeout}
+	errorWebhookURL string
+	errorSentry     *sentryTarget
+)
+
+func startErrorReporting() {
+	errorWebhookURL = os.Getenv("ERROR_WEBHOOK_URL")
+	if dsn := os.Getenv("SENTRY_DSN"); dsn != "" {
+		target, err := parseSentryDSN(dsn)
+		if err != nil {
+			log.Printf("Invalid SENTRY_DSN: %v", err)
+		} else {
+			errorSentry = target
+		}
+	}
+	if errorWebhookURL == "" && errorSentry == nil {
+		return
+	}
+	errorReportOnce.Do(func() {
+		errorReports = make(chan errorReport, errorReportQueueSize)
+		go runErrorReporter()
+	})
+	log.Println("External error reporting is enabled.")
+}
+
+func parseSentryDSN(dsn string) (*sentryTarget, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("missing public key")
+	}
+	projectID := strings.Trim(u.Path, "/")
+	if projectID == "" {
+		return nil, fmt.Errorf("missing project ID")
+	}
+	prefix := ""
+	if idx := strings.LastIndex(projectID, "/"); idx >= 0 {
+		prefix, projectID = "/"+projectID[:idx], projectID[idx+1:]
+	}
+	return &sentryTarget{
+		StoreURL: fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, projectID),
+		Auth:     fmt.Sprintf("Sentry sentry_version=7, sentry_client=potatobot/1.0, sentry_key=%s", u.User.Username()),
+	}, nil
+}
+
+func reportError(kind, message, stack string, tags map[string]string) {
+	if errorReports == nil {
+		return
+	}
+	fingerprint := kind + "|" + message
+	errorReportMu.Lock()
+	if last, ok := errorReportSeen[fingerprint]; ok && time.Since(last) < errorReportDedupWindow {
+		errorReportMu.Unlock()
+		return
+	}
+	errorReportSeen[fingerprint] = time.Now()
+	for key, at := range errorReportSeen {
+		if time.Since(at) > errorReportDedupWindow {
+			delete(errorReportSeen, key)
+		}
+	}
+	errorReportMu.Unlock()
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	select {
+	case errorReports <- errorReport{Kind: kind, Message: message, Stack: stack, Tags: tags, Time: time.Now()}:
+	default:
+		log.Printf("Error report queue is full; dropping %s report.", kind)
+	}
+}
+
+func interactionErrorTags(i *discordgo.InteractionCreate) map[string]string {
+	tags := channelErrorTags(i.ChannelID)
+	tags["guild"] = i.GuildID
+	tags["handler"] = interactionHandlerName(i)
+	if user := interactionUser(i); user != nil {
+		tags["user"] = user.ID
+	}
+	return tags
+}
+
+func channelErrorTags(channelID string) map[string]string {
+	tags := make(map[string]string)
+	if channelID == "" {
+		return tags
+	}
+	tags["channel"] = channelID
+	session := app().Session
+	if session == nil {
+		return tags
+	}
+	if ch, err := session.State.Channel(channelID); err == nil {
+		if category := ticketCategory(ch); isTicketTopic(category) {
+			tags["ticket"] = ch.Name
+			tags["category"] = category
+			if sandboxActive() && isSandboxTicket(ch) {
+				tags["sandbox"] = "true"
+			}
+		}
+	}
+	return tags
+}
+
+func runErrorReporter() {
+	for report := range errorReports {
+		if report.Tags["sandbox"] == "true" {
+			log.Printf("Sandbox mode: mocked %s error report: %s", report.Kind, report.Message)
+			continue
+		}
+		if errorSentry != nil {
+			if err := sendSentryEvent(report); err != nil {
+				log.Printf("Could not send error report to Sentry: %v", err)
+			}
+		}
+		if errorWebhookURL != "" {
+			if err := postErrorReport(errorWebhookURL, report, nil); err != nil {
+				log.Printf("Could not send error report to webhook: %v", err)
+			}
+		}
+	}
+}
+
+func sendSentryEvent(report errorReport) error {
+	id := make([]byte, 16)
+	rand.Read(id)
+	event := map[string]interface{}{
+		"event_id":  hex.EncodeToString(id),
+		"timestamp": report.Time.UTC().Format(time.RFC3339),
+		"level":     "error",
+		"platform":  "go",
+		"logger":    report.Kind,
+		"message":   map[string]string{"formatted": report.Message},
+		"tags":      report.Tags,
+	}
+	if report.Stack != "" {
+		event["extra"] = map[string]string{"stack": report.Stack}
+	}
+	return postErrorReport(errorSentry.StoreURL, event, map[string]string{"X-Sentry-Auth": errorSentry.Auth})
+}
+
+func postErrorReport(target string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := errorReportHTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func reportDiscordAPIError(req *http.Request, failure string) {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	channelID := ""
+	for n, part := range parts {
+		if n > 0 && parts[n-1] == "channels" {
+			channelID = part
+		}
+		if n > 1 && (parts[n-2] == "interactions" || parts[n-2] == "webhooks") {
+			parts[n] = ":token"
+		} else if part != "" && strings.Trim(part, "0123456789") == "" {
+			parts[n] = ":id"
+		}
+	}
+	route := req.Method + " /" + strings.Join(parts, "/")
+	tags := channelErrorTags(channelID)
+	tags["route"] = route
+	reportError("discord", fmt.Sprintf("%s: %s", route, failure), "", tags)
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type errorCode struct {
+	Code  string
+	Title string
+	Cause string
+	Hint  string
+}
+
+var (
+	errSequenceFailed       = errorCode{Code: "PB-1001", Cause: "티켓 번호를 생성하는 데 실패했습니다.", Hint: "데이터베이스 연결 상태를 확인한 뒤 관리자에게 문의하세요."}
+	errChannelCreateFailed  = errorCode{Code: "PB-1002", Cause: "채널 생성에 실패했습니다.", Hint: "봇의 채널 관리 권한과 카테고리의 채널 수(최대 50개)를 확인하세요."}
+	errTicketFetchFailed    = errorCode{Code: "PB-1003", Cause: "티켓 정보를 찾는 데 실패했습니다.", Hint: "잠시 후 다시 시도하세요. 문제가 계속되면 봇의 메시지 기록 보기 권한을 확인하세요."}
+	errTicketMessageMissing = errorCode{Code: "PB-1004", Cause: "원본 티켓 메시지를 찾을 수 없습니다.", Hint: "원본 안내 메시지가 삭제되었거나 최근 100개 메시지 밖에 있습니다."}
+	errPermissionLookup     = errorCode{Code: "PB-1005", Cause: "대상 사용자의 권한을 확인하는 데 실패했습니다.", Hint: "잠시 후 다시 시도하세요."}
+	errTicketMessageEdit    = errorCode{Code: "PB-1006", Cause: "티켓 메시지를 수정하는 데 실패했습니다.", Hint: "봇의 메시지 관리 권한을 확인하세요."}
+	errAddUserFailed        = errorCode{Code: "PB-1007", Cause: "티켓에 사용자를 추가하는 데 실패했습니다.", Hint: "봇의 권한 관리 권한을 확인하세요."}
+	errAddRoleFailed        = errorCode{Code: "PB-1008", Cause: "티켓에 역할을 추가하는 데 실패했습니다.", Hint: "봇의 권한 관리 권한과 역할 순서를 확인하세요."}
+	errRemoveUserFailed     = errorCode{Code: "PB-1009", Cause: "티켓에서 사용자를 제거하는 데 실패했습니다.", Hint: "봇의 권한 관리 권한을 확인하세요."}
+	errRemoveRoleFailed     = errorCode{Code: "PB-1010", Cause: "티켓에서 역할을 제거하는 데 실패했습니다.", Hint: "봇의 권한 관리 권한과 역할 순서를 확인하세요."}
+	errChannelLookupFailed  = errorCode{Code: "PB-1011", Cause: "채널 정보를 불러오는 데 실패했습니다.", Hint: "잠시 후 다시 시도하세요."}
+	errCSATSaveFailed       = errorCode{Code: "PB-1012", Cause: "만족도 응답을 저장하는 데 실패했습니다.", Hint: "잠시 후 다시 시도해주세요."}
+	errLinkSaveFailed       = errorCode{Code: "PB-1013", Cause: "티켓 연결 정보를 저장하는 데 실패했습니다.", Hint: "데이터베이스 연결 상태를 확인하세요."}
+	errConfigSaveFailed     = errorCode{Code: "PB-1015", Cause: "설정을 저장하는 데 실패했습니다.", Hint: "데이터베이스 연결 상태를 확인한 뒤 다시 시도하세요."}
+	errTicketSaveFailed     = errorCode{Code: "PB-1016", Cause: "티켓 정보를 저장하는 데 실패했습니다.", Hint: "데이터베이스 연결 상태를 확인한 뒤 다시 시도하세요."}
+	errRuleSaveFailed       = errorCode{Code: "PB-1017", Cause: "규칙을 불러오거나 저장하는 데 실패했습니다.", Hint: "데이터베이스 연결 상태를 확인한 뒤 다시 시도하세요."}
+	errInternalPanic        = errorCode{Code: "PB-1018", Cause: "요청을 처리하는 중 예기치 않은 오류가 발생했습니다. (사건 번호 %s)", Hint: "잠시 후 다시 시도하세요. 문제가 계속되면 사건 번호와 함께 관리자에게 알려주세요."}
+	errBlockSaveFailed      = errorCode{Code: "PB-1019", Cause: "차단 정보를 저장하는 데 실패했습니다.", Hint: "데이터베이스 연결 상태를 확인한 뒤 다시 시도하세요."}
+	errUnsealFailed         = errorCode{Code: "PB-1021", Cause: "암호화된 기록을 복호화하지 못했습니다.", Hint: "SENSITIVE_DATA_KEY가 기록을 암호화할 때 쓴 키와 같은지 확인하세요."}
+	errGreetingSendFailed   = errorCode{Code: "PB-1020", Cause: "티켓 안내 메시지를 보내지 못해 티켓 생성을 취소했습니다.", Hint: "잠시 후 다시 시도해주세요. 문제가 계속되면 봇의 메시지 보내기 권한을 확인하도록 관리자에게 알려주세요."}
+	errRelayFailed          = errorCode{Code: "PB-1014", Cause: "연결된 티켓에 메시지를 공유하지 못했습니다.", Hint: "연결된 티켓 채널이 삭제되었는지 확인하세요."}
+
+	errNotTicketChannel       = errorCode{Code: "PB-2001", Cause: "이 명령어는 티켓 채널에서만 사용할 수 있습니다.", Hint: "티켓 채널 안에서 다시 실행하세요."}
+	errOwnerCannotClaim       = errorCode{Code: "PB-2002", Cause: "티켓을 개설한 본인은 담당자가 될 수 없습니다.", Hint: "다른 지원팀 구성원에게 배정을 요청하세요."}
+	errAlreadyClaimed         = errorCode{Code: "PB-2003", Cause: "이미 담당자가 배정된 티켓입니다.", Hint: "담당자를 바꾸려면 /담당자변경 명령어를 사용하세요."}
+	errAssigneeCannotView     = errorCode{Code: "PB-2004", Cause: "%s 님은 이 채널을 볼 수 없어 담당자로 지정할 수 없습니다.", Hint: "/추가 명령어로 먼저 사용자를 티켓에 추가하세요."}
+	errSupportRoleRemoval     = errorCode{Code: "PB-2005", Title: "제거 불가", Cause: "기본 지원 역할은 티켓에서 제거할 수 없습니다.", Hint: "지원 역할을 바꾸려면 관리자에게 설정 변경을 요청하세요."}
+	errLoadTestCount          = errorCode{Code: "PB-2006", Cause: "합성 티켓 수는 1에서 %d 사이여야 합니다.", Hint: "더 큰 규모는 여러 번 나누어 실행하세요."}
+	errInvalidLinkTarget      = errorCode{Code: "PB-2008", Cause: "연결할 수 없는 채널입니다.", Hint: "서로 다른 두 티켓 채널에서만 연결할 수 있습니다."}
+	errNoLinkedTickets        = errorCode{Code: "PB-2009", Cause: "이 티켓에 연결된 티켓이 없습니다.", Hint: "/연결 명령어로 먼저 다른 티켓과 연결하세요."}
+	errTicketNotOpen          = errorCode{Code: "PB-2010", Cause: "이미 닫힌 티켓입니다.", Hint: "관리자 패널의 '티켓 재오픈' 버튼으로 다시 열 수 있습니다."}
+	errRuleTargetMissing      = errorCode{Code: "PB-2011", Cause: "규칙 동작에 필요한 대상이 지정되지 않았습니다.", Hint: "'채널에 기록'은 channel, '역할 추가'와 '역할 호출'은 role 옵션을 함께 지정하세요."}
+	errRuleNotFound           = errorCode{Code: "PB-2012", Cause: "%d번 규칙을 찾을 수 없습니다.", Hint: "/규칙 목록으로 번호를 확인하세요."}
+	errIntakeQuestionLimit    = errorCode{Code: "PB-2013", Cause: "접수 양식에는 질문을 최대 %d개까지만 넣을 수 있습니다.", Hint: "/설정 질문 삭제로 기존 질문을 먼저 정리하세요."}
+	errIntakeQuestionNotFound = errorCode{Code: "PB-2014", Cause: "%d번 질문을 찾을 수 없습니다.", Hint: "/설정 질문 보기로 번호를 확인하세요."}
+	errForumRoleUnsupported   = errorCode{Code: "PB-2015", Cause: "포럼 게시글 티켓에는 역할 단위로 권한을 줄 수 없습니다.", Hint: "/추가 명령어로 사용자를 개별 추가하거나, 포럼 채널 권한에서 역할을 관리하세요."}
+	errAccountTooNew          = errorCode{Code: "PB-2016", Cause: "디스코드 계정을 만든 지 %d일이 지나야 민원을 접수할 수 있습니다.", Hint: "기간이 지난 뒤 다시 시도하거나, 급한 경우 관리자에게 직접 문의하세요."}
+	errTicketBlocked          = errorCode{Code: "PB-2035", Cause: "민원 접수가 제한된 계정입니다. (사유: %s · 기간: %s)", Hint: "제한이 잘못되었다고 생각되면 아래 '이의신청' 버튼으로 한 번 이의를 제기할 수 있습니다."}
+	errNotBlocked             = errorCode{Code: "PB-2036", Cause: "<@%s> 님은 차단되어 있지 않습니다.", Hint: "차단 목록은 로그 채널의 '민원 접수 차단' 기록에서 확인하세요."}
+	errAppealExists           = errorCode{Code: "PB-2037", Cause: "이번 접수 제한에 대한 이의신청은 이미 제출되었습니다.", Hint: "이의신청 채널에서 관리자의 결정을 기다려주세요."}
+	errAppealNotFound         = errorCode{Code: "PB-2038", Cause: "이 채널에 연결된 이의신청을 찾을 수 없습니다.", Hint: "이미 결정되었거나 차단이 해제된 이의신청입니다."}
+	errComponentRateLimited   = errorCode{Code: "PB-2041", Title: "잠시만요", Cause: "버튼을 너무 빠르게 누르고 있습니다.", Hint: "잠시 후 다시 시도해주세요."}
+	errGreetingButtonTarget   = errorCode{Code: "PB-2045", Cause: "버튼에는 안내 내용(content)과 링크(url) 중 하나만 지정해야 합니다.", Hint: "링크는 https://로 시작하는 주소를 입력하세요."}
+	errGreetingButtonLimit    = errorCode{Code: "PB-2046", Cause: "창구별 안내 버튼은 최대 %d개까지만 등록할 수 있습니다.", Hint: "/안내버튼 삭제로 기존 버튼을 먼저 정리하세요."}
+	errGreetingButtonNotFound = errorCode{Code: "PB-2047", Cause: "%d번 안내 버튼을 찾을 수 없습니다.", Hint: "/안내버튼 보기로 번호를 확인하세요."}
+	errLogRecordMissing       = errorCode{Code: "PB-2044", Cause: "'%s' 접수번호로 닫힌 티켓 기록을 찾을 수 없습니다.", Hint: "접수번호를 확인하세요. 아직 열려 있는 티켓은 닫힐 때 로그가 전송됩니다."}
+	errSandboxProtected       = errorCode{Code: "PB-2043", Cause: "연습 모드에서는 연습용 티켓 채널만 삭제할 수 있습니다.", Hint: "실제 티켓을 정리하려면 /연습모드로 연습 모드를 먼저 끄세요."}
+	errInvalidAlertTarget     = errorCode{Code: "PB-2042", Cause: "'%s'은(는) 올바른 알림 대상이 아닙니다.", Hint: "텔레그램은 채팅 ID(숫자 또는 @채널)를, 웹훅은 공개 인터넷에서 접근할 수 있는 https:// 주소를 입력하세요. 내부망 주소는 사용할 수 없습니다."}
+	errSealedTicketMissing    = errorCode{Code: "PB-2040", Cause: "'%s' 접수번호로 암호화 보관된 기록을 찾을 수 없습니다.", Hint: "접수번호를 확인하세요. 암호화 보관을 켠 창구의 티켓만 열람할 수 있습니다."}
+	errProxyTargetInvalid     = errorCode{Code: "PB-2039", Cause: "봇이나 서버에 없는 사용자에게는 티켓을 열 수 없습니다.", Hint: "서버 구성원을 다시 선택해주세요."}
+	errMemberTooNew           = errorCode{Code: "PB-2034", Cause: "서버에 참여한 지 %d일이 지나야 민원을 접수할 수 있습니다. <t:%d:R>부터 접수할 수 있습니다.", Hint: "기간이 지난 뒤 다시 시도하거나, 급한 경우 관리자에게 직접 문의하세요."}
+	errVerificationFailed     = errorCode{Code: "PB-2017", Cause: "본인 확인에 실패했거나 확인 시간이 만료되었습니다.", Hint: "민원 창구를 다시 선택해 새로 확인을 진행하세요."}
+	errInvalidSLADuration     = errorCode{Code: "PB-2018", Cause: "'%s'은(는) 올바른 기한 형식이 아닙니다.", Hint: "30m, 4h, 2d처럼 입력하거나 '해제'를 입력하세요."}
+	errSkillNotFound          = errorCode{Code: "PB-2019", Cause: "<@%s> 님에게 '%s' 스킬이 없습니다.", Hint: "/스킬 목록으로 등록된 스킬을 확인하세요."}
+	errTicketLanguageUnknown  = errorCode{Code: "PB-2020", Cause: "이 티켓의 민원인 언어를 아직 알 수 없습니다.", Hint: "language 옵션으로 언어를 직접 지정하세요."}
+	errInvalidExportMonth     = errorCode{Code: "PB-2021", Cause: "'%s'은(는) 올바른 달 형식이 아닙니다.", Hint: "2026-01처럼 연도와 월을 입력하세요."}
+	errTicketNotClosed        = errorCode{Code: "PB-2022", Cause: "아직 열려 있는 티켓입니다.", Hint: "티켓을 먼저 닫은 뒤 다시 시도하세요."}
+	errStaleComponent         = errorCode{Code: "PB-2023", Cause: "이 버튼은 다른 티켓에 속해 있거나 더 이상 유효하지 않습니다.", Hint: "티켓 채널의 최신 메시지에 있는 버튼을 사용하세요."}
+	errPinnedInfoLimit        = errorCode{Code: "PB-2024", Cause: "창구별 고정 안내문은 최대 %d개까지만 등록할 수 있습니다.", Hint: "/설정 안내 삭제로 기존 안내문을 먼저 정리하세요."}
+	errPinnedInfoNotFound     = errorCode{Code: "PB-2025", Cause: "%d번 안내문을 찾을 수 없습니다.", Hint: "/설정 안내 보기로 번호를 확인하세요."}
+	errInvalidStatsRange      = errorCode{Code: "PB-2026", Cause: "'%s'은(는) 올바른 조회 기간이 아닙니다.", Hint: "from과 to를 2026-01-01처럼 입력하고, 시작일이 종료일보다 앞서야 합니다."}
+	errRoleImportTooLarge     = errorCode{Code: "PB-2027", Cause: "역할 구성원이 %d명으로 한 번에 추가할 수 있는 %d명을 넘습니다.", Hint: "/역할추가로 역할 자체를 추가하거나 인원이 적은 역할을 사용하세요."}
+	errTicketCooldown         = errorCode{Code: "PB-2028", Cause: "새 티켓은 %s 뒤(<t:%d:t>)에 만들 수 있습니다.", Hint: "이미 열린 티켓이 있다면 해당 채널에서 문의를 이어가주세요."}
+	errOpenTicketLimit        = errorCode{Code: "PB-2029", Cause: "열어둘 수 있는 티켓은 1인당 %d개까지입니다.", Hint: "아래의 기존 티켓에서 문의를 이어가거나, 해결된 티켓을 먼저 닫아주세요."}
+	errPresetOpenTickets      = errorCode{Code: "PB-2031", Cause: "프리셋에 없는 창구(%s)에 아직 열린 티켓이 있습니다.", Hint: "해당 티켓을 모두 닫은 뒤 다시 적용하거나, 그 창구가 포함된 프리셋을 선택하세요."}
+	errIntakeLockdown         = errorCode{Code: "PB-2032", Cause: "짧은 시간에 접수가 몰려 민원 접수가 일시 중단되었습니다. <t:%d:R>에 재개됩니다.", Hint: "잠시 후 다시 시도해주세요. 급한 경우 운영진에게 직접 문의하세요."}
+	errInvalidCodePrefix      = errorCode{Code: "PB-2033", Cause: "'%s'은(는) 사용할 수 없는 접수번호 앞자리입니다.", Hint: "GW, SEOUL처럼 영문 대문자 2~6자로 입력하세요."}
+	errNotQuarantined         = errorCode{Code: "PB-2030", Cause: "이 티켓은 스팸 검토 대기 중이 아닙니다.", Hint: "이미 검토가 끝나 일반 접수로 전환된 티켓입니다."}
+	errSelfCloseCooldown      = errorCode{Code: "PB-2007", Cause: "티켓을 연 직후에는 직접 닫을 수 없습니다. %s 후에 다시 시도해주세요.", Hint: "담당자의 답변을 기다리거나, 급한 경우 담당자에게 종료를 요청하세요."}
+
+	errNoSupportRole           = errorCode{Code: "PB-3001", Title: "권한 없음", Cause: "지원팀 역할이 없습니다.", Hint: "관리자에게 지원팀 역할 부여를 요청하세요."}
+	errNotManagerOrAssignee    = errorCode{Code: "PB-3002", Title: "권한 없음", Cause: "관리자 또는 현재 담당자만 이 명령어를 사용할 수 있습니다.", Hint: "현재 담당자에게 변경을 요청하세요."}
+	errOwnerOnly               = errorCode{Code: "PB-3003", Title: "권한 없음", Cause: "서버 소유자만 이 명령어를 사용할 수 있습니다.", Hint: "서버 소유자에게 실행을 요청하세요."}
+	errAdminOnly               = errorCode{Code: "PB-3005", Title: "권한 없음", Cause: "관리자만 이 명령어를 사용할 수 있습니다.", Hint: "서버 관리자 권한이 있는 사용자에게 요청하세요."}
+	errVerificationRoleMissing = errorCode{Code: "PB-3006", Title: "권한 없음", Cause: "민원을 접수하려면 <@&%s> 역할이 필요합니다.", Hint: "서버 인증 절차를 먼저 완료하세요."}
+	errNotSpamModerator        = errorCode{Code: "PB-3007", Title: "권한 없음", Cause: "스팸 검토 역할이 있는 사용자만 격리된 티켓을 전환할 수 있습니다.", Hint: "검토 역할이 있는 운영진에게 확인을 요청하세요."}
+	errTopicRoleMissing        = errorCode{Code: "PB-3008", Title: "권한 없음", Cause: "%s 창구는 <@&%s> 역할이 있어야 이용할 수 있습니다.", Hint: "필요한 인증 절차를 먼저 완료하거나, 다른 창구를 선택해주세요."}
+	errNotTicketOwner          = errorCode{Code: "PB-3004", Title: "권한 없음", Cause: "티켓을 개설한 민원인만 해결 처리할 수 있습니다.", Hint: "담당자는 '티켓 닫기' 버튼을 사용하세요."}
+
+	errForumChannelUnset     = errorCode{Code: "PB-4002", Cause: "포럼 게시글 방식에 사용할 포럼 채널이 지정되지 않았습니다.", Hint: "/설정 티켓방식 명령어의 forum 옵션으로 포럼 채널을 함께 지정하세요."}
+	errSpamModeratorUnset    = errorCode{Code: "PB-4003", Cause: "스팸 검토를 맡을 역할이 지정되지 않았습니다.", Hint: "/설정 스팸검사 명령어의 moderator_role 옵션으로 검토 역할을 함께 지정하세요."}
+	errSealingKeyUnset       = errorCode{Code: "PB-4004", Cause: "암호화 보관에 사용할 키(SENSITIVE_DATA_KEY)가 없거나 올바르지 않습니다.", Hint: "32바이트 키를 base64로 인코딩해 환경 변수나 SENSITIVE_DATA_KEY_FILE로 지정한 뒤 봇을 재시작하세요."}
+	errTelegramTokenUnset    = errorCode{Code: "PB-4006", Cause: "텔레그램 알림에 사용할 봇 토큰(TELEGRAM_BOT_TOKEN)이 설정되지 않았습니다.", Hint: "환경 변수에 텔레그램 봇 토큰을 지정한 뒤 봇을 재시작하거나, 웹훅 방식을 사용하세요."}
+	errSandboxCategoryUnset  = errorCode{Code: "PB-4007", Cause: "연습용 티켓을 만들 카테고리가 지정되지 않았습니다.", Hint: "/연습모드 명령어의 category 옵션으로 연습용 카테고리를 함께 지정하세요."}
+	errLoadTestCategoryUnset = errorCode{Code: "PB-4001", Cause: "부하 테스트에 사용할 연습용 카테고리가 지정되지 않았습니다.", Hint: "/연습모드 명령어의 category 옵션으로 연습용 카테고리를 먼저 지정하세요."}
+)
+
+func (e errorCode) embed(args ...interface{}) *discordgo.MessageEmbed {
+	title := e.Title
+	if title == "" {
+		title = "오류"
+	}
+	return &discordgo.MessageEmbed{
+		Title:       title,
+		Description: fmt.Sprintf("%s\n\n💡 %s", e.cause(args...), e.Hint),
+		Color:       colorRed,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "오류 코드 " + e.Code},
+	}
+}
+
+func respondError(s *discordgo.Session, i *discordgo.InteractionCreate, e errorCode, err error, args ...interface{}) {
+	logError(i, e, err, args...)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{e.embed(args...)}}})
+}
+
+func (e errorCode) cause(args ...interface{}) string {
+	if len(args) == 0 {
+		return e.Cause
+	}
+	return fmt.Sprintf(e.Cause, args...)
+}
+
+func logError(i *discordgo.InteractionCreate, e errorCode, err error, args ...interface{}) {
+	userID := ""
+	if i.Member != nil {
+		userID = i.Member.User.ID
+	} else if i.User != nil {
+		userID = i.User.ID
+	}
+	if err != nil {
+		log.Printf("%s %s in %s (guild=%s channel=%s user=%s): %v", e.Code, e.cause(args...), interactionName(i), i.GuildID, i.ChannelID, userID, err)
+		tags := interactionErrorTags(i)
+		tags["code"] = e.Code
+		reportError("interaction", fmt.Sprintf("%s %s: %v", e.Code, e.cause(args...), err), "", tags)
+		return
+	}
+	log.Printf("%s %s in %s (guild=%s channel=%s user=%s)", e.Code, e.cause(args...), interactionName(i), i.GuildID, i.ChannelID, userID)
+}
+
+func interactionName(i *discordgo.InteractionCreate) string {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		return "/" + i.ApplicationCommandData().Name
+	case discordgo.InteractionMessageComponent:
+		return i.MessageComponentData().CustomID
+	case discordgo.InteractionModalSubmit:
+		return i.ModalSubmitData().CustomID
+	}
+	return "unknown"
+}
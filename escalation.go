@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	slaEscalationCheckInterval = time.Minute
+	defaultEscalationInterval  = 30 * time.Minute
+)
+
+type slaEscalation struct {
+	RoleID   string        `bson:"role_id,omitempty"`
+	Interval time.Duration `bson:"interval"`
+}
+
+func escalationSummary(e slaEscalation) string {
+	if e.RoleID == "" {
+		return "사용 안 함"
+	}
+	if e.Interval <= 0 {
+		return fmt.Sprintf("<@&%s> 호출 (1회)", e.RoleID)
+	}
+	return fmt.Sprintf("<@&%s> 호출, 배정 전까지 %s마다 반복", e.RoleID, formatSLADuration(e.Interval))
+}
+
+func escalateBreachedTickets(s *discordgo.Session) error {
+	e := getConfig().SLAEscalation
+	if e.RoleID == "" {
+		return nil
+	}
+	now := time.Now()
+	filter := bson.M{
+		"status":             ticketStatusOpen,
+		"first_response_due": bson.M{"$lte": now},
+		"first_response_at":  bson.M{"$exists": false},
+		"assignee_id":        bson.M{"$in": bson.A{nil, ""}},
+	}
+	cursor, err := app().Tickets.Find(context.TODO(), filter)
+	if err != nil {
+		return err
+	}
+	var breached []ticket
+	if err := cursor.All(context.TODO(), &breached); err != nil {
+		return err
+	}
+	for n := range breached {
+		t := &breached[n]
+		if !t.LastEscalatedAt.IsZero() && (e.Interval <= 0 || now.Sub(t.LastEscalatedAt) < e.Interval) {
+			continue
+		}
+		if err := escalateTicket(s, t, e, now); err != nil {
+			log.Printf("Could not escalate ticket '%s': %v", t.Name(), err)
+		}
+	}
+	return nil
+}
+
+func escalateTicket(s *discordgo.Session, t *ticket, e slaEscalation, now time.Time) error {
+	t.EscalationCount++
+	overdue := formatWait(now.Sub(t.FirstResponseDue))
+	_, err := s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+		Content: fmt.Sprintf("<@&%s>", e.RoleID),
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       "첫 응답 기한 초과",
+			Description: fmt.Sprintf("이 티켓의 첫 응답 기한이 %s 지났습니다. 담당자 배정이 필요합니다.", overdue),
+			Color:       colorRed,
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	notifyChannel(s, getConfig().LogChannelID, &discordgo.MessageEmbed{
+		Title:       "SLA 초과 알림",
+		Description: fmt.Sprintf("<#%s> 티켓이 첫 응답 기한을 넘겼습니다.", t.ChannelID),
+		Color:       colorRed,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "티켓", Value: t.Name(), Inline: true},
+			{Name: "초과 시간", Value: overdue, Inline: true},
+			{Name: "알림 횟수", Value: fmt.Sprintf("%d회", t.EscalationCount), Inline: true},
+		},
+		Timestamp: now.In(kstLocation).Format(time.RFC3339),
+	})
+	sendExternalAlert(ticketAlert(alertEventSLABreach, "SLA 초과", fmt.Sprintf("%s 티켓이 첫 응답 기한을 %s 넘겼습니다. (%d회째 알림)", t.Name(), overdue, t.EscalationCount), t), t.AssigneeID)
+	log.Printf("Escalated ticket '%s' (first response overdue by %s, alert %d).", t.Name(), overdue, t.EscalationCount)
+	return updateTicket(t.ChannelID, bson.M{"$set": bson.M{"last_escalated_at": now}, "$inc": bson.M{"escalation_count": 1}})
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	eventQueueSize      = 500
+	eventPublishTimeout = 5 * time.Second
+	eventBrokerNATS     = "nats"
+	eventBrokerKafka    = "kafka"
+	ticketEventDeleted  = "deleted"
+	defaultEventSubject = "potatobot.tickets"
+)
+
+type ticketLifecycleEvent struct {
+	Event            string    `json:"event"`
+	At               time.Time `json:"at"`
+	GuildID          string    `json:"guild_id"`
+	Code             string    `json:"code"`
+	Name             string    `json:"name"`
+	Category         string    `json:"category"`
+	Status           string    `json:"status"`
+	OwnerID          string    `json:"owner_id,omitempty"`
+	AssigneeID       string    `json:"assignee_id,omitempty"`
+	Priority         string    `json:"priority,omitempty"`
+	RequesterUrgency string    `json:"requester_urgency,omitempty"`
+	CloseCode        string    `json:"close_code,omitempty"`
+	Tags             []string  `json:"tags,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+	FirstResponseAt  time.Time `json:"first_response_at,omitempty"`
+	ClosedAt         time.Time `json:"closed_at,omitempty"`
+	ReopenCount      int       `json:"reopen_count,omitempty"`
+}
+
+type eventPublisher interface {
+	publish(ev ticketLifecycleEvent, payload []byte) error
+}
+
+var (
+	ticketEvents     chan ticketLifecycleEvent
+	eventBrokerKind  string
+	eventBrokerOnce  sync.Once
+	eventBrokerTopic string
+)
+
+func startEventPublisher() {
+	eventBrokerKind = strings.ToLower(os.Getenv("EVENT_BROKER"))
+	eventBrokerTopic = os.Getenv("EVENT_TOPIC")
+	if eventBrokerTopic == "" {
+		eventBrokerTopic = defaultEventSubject
+	}
+	var publisher eventPublisher
+	switch eventBrokerKind {
+	case "":
+		return
+	case eventBrokerNATS:
+		p, err := newNATSPublisher(os.Getenv("NATS_URL"))
+		if err != nil {
+			log.Printf("Invalid NATS_URL: %v", err)
+			return
+		}
+		publisher = p
+	case eventBrokerKafka:
+		proxy := strings.TrimRight(os.Getenv("KAFKA_REST_URL"), "/")
+		if proxy == "" {
+			log.Println("EVENT_BROKER is kafka but KAFKA_REST_URL is not set; ticket events will not be published.")
+			return
+		}
+		publisher = &kafkaRESTPublisher{proxy: proxy, client: &http.Client{Timeout: eventPublishTimeout}}
+	default:
+		log.Printf("Unknown EVENT_BROKER '%s'; expected nats or kafka.", eventBrokerKind)
+		return
+	}
+	eventBrokerOnce.Do(func() {
+		ticketEvents = make(chan ticketLifecycleEvent, eventQueueSize)
+		go runEventPublisher(publisher)
+	})
+	log.Printf("Publishing ticket events to %s (%s).", eventBrokerKind, eventBrokerTopic)
+}
+
+func publishTicketEvent(t *ticket, event string) {
+	if ticketEvents == nil {
+		return
+	}
+	if t.Sandbox {
+		log.Printf("Sandbox mode: mocked %s event for '%s'.", event, t.Name())
+		incCounter("potatobot_ticket_events_total", metricLabel("broker", eventBrokerKind)+","+metricLabel("result", "mocked"))
+		return
+	}
+	ev := ticketLifecycleEvent{
+		Event:            event,
+		At:               time.Now(),
+		GuildID:          t.GuildID,
+		Code:             t.Code,
+		Name:             t.Name(),
+		Category:         t.Category,
+		Status:           t.Status,
+		AssigneeID:       t.AssigneeID,
+		Priority:         t.Priority,
+		RequesterUrgency: t.RequesterUrgency,
+		CloseCode:        t.CloseCode,
+		Tags:             t.Tags,
+		CreatedAt:        t.CreatedAt,
+		FirstResponseAt:  t.FirstResponseAt,
+		ClosedAt:         t.ClosedAt,
+		ReopenCount:      t.ReopenCount,
+	}
+	if !featuresFor(t.Category).Anonymous {
+		ev.OwnerID = t.OwnerID
+	}
+	select {
+	case ticketEvents <- ev:
+	default:
+		incCounter("potatobot_ticket_events_total", metricLabel("broker", eventBrokerKind)+","+metricLabel("result", "dropped"))
+		log.Printf("Ticket event queue is full; dropping %s event for '%s'.", event, t.Name())
+	}
+}
+
+func runEventPublisher(publisher eventPublisher) {
+	for ev := range ticketEvents {
+		result := "ok"
+		if payload, err := json.Marshal(ev); err != nil {
+			result = "error"
+			log.Printf("Could not encode %s event for '%s': %v", ev.Event, ev.Name, err)
+		} else if err := publisher.publish(ev, payload); err != nil {
+			result = "error"
+			log.Printf("Could not publish %s event for '%s' to %s: %v", ev.Event, ev.Name, eventBrokerKind, err)
+		}
+		incCounter("potatobot_ticket_events_total", metricLabel("broker", eventBrokerKind)+","+metricLabel("result", result))
+	}
+}
+
+type kafkaRESTPublisher struct {
+	proxy  string
+	client *http.Client
+}
+
+func (p *kafkaRESTPublisher) publish(ev ticketLifecycleEvent, payload []byte) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"records": []map[string]interface{}{{"key": ev.Code, "value": json.RawMessage(payload)}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, p.proxy+"/topics/"+url.PathEscape(eventBrokerTopic), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+type natsPublisher struct {
+	target *url.URL
+	mu     sync.Mutex
+	conn   net.Conn
+}
+
+func newNATSPublisher(raw string) (*natsPublisher, error) {
+	if raw == "" {
+		raw = "nats://127.0.0.1:4222"
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("unsupported scheme '%s'", u.Scheme)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	return &natsPublisher{target: u}, nil
+}
+
+func (p *natsPublisher) connect() error {
+	dialer := &net.Dialer{Timeout: eventPublishTimeout}
+	var conn net.Conn
+	var err error
+	if p.target.Scheme == "tls" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", p.target.Host, &tls.Config{ServerName: p.target.Hostname()})
+	} else {
+		conn, err = dialer.Dial("tcp", p.target.Host)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(eventPublishTimeout))
+	reader := bufio.NewReader(conn)
+	if line, err := reader.ReadString('\n'); err != nil || !strings.HasPrefix(line, "INFO") {
+		conn.Close()
+		return fmt.Errorf("unexpected greeting from server")
+	}
+	options := map[string]interface{}{"verbose": false, "pedantic": false, "name": "potatobot", "lang": "go"}
+	if user := p.target.User; user != nil {
+		if pass, ok := user.Password(); ok {
+			options["user"], options["pass"] = user.Username(), pass
+		} else {
+			options["auth_token"] = user.Username()
+		}
+	}
+	connect, _ := json.Marshal(options)
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\nPING\r\n", connect); err != nil {
+		conn.Close()
+		return err
+	}
+	if line, err := reader.ReadString('\n'); err != nil || !strings.HasPrefix(line, "PONG") {
+		conn.Close()
+		return fmt.Errorf("server rejected connection: %s", strings.TrimSpace(line))
+	}
+	conn.SetDeadline(time.Time{})
+	p.conn = conn
+	go p.keepAlive(conn, reader)
+	return nil
+}
+
+func (p *natsPublisher) keepAlive(conn net.Conn, reader *bufio.Reader) {
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			p.mu.Lock()
+			if p.conn == conn {
+				p.conn = nil
+			}
+			p.mu.Unlock()
+			conn.Close()
+			return
+		}
+		switch {
+		case strings.HasPrefix(line, "PING"):
+			p.mu.Lock()
+			conn.Write([]byte("PONG\r\n"))
+			p.mu.Unlock()
+		case strings.HasPrefix(line, "-ERR"):
+			log.Printf("NATS server error: %s", strings.TrimSpace(line))
+		}
+	}
+}
+
+func (p *natsPublisher) publish(ev ticketLifecycleEvent, payload []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.conn == nil {
+		if err := p.connect(); err != nil {
+			return err
+		}
+	}
+	p.conn.SetWriteDeadline(time.Now().Add(eventPublishTimeout))
+	if _, err := fmt.Fprintf(p.conn, "PUB %s.%s %d\r\n%s\r\n", eventBrokerTopic, ev.Event, len(payload), payload); err != nil {
+		p.conn.Close()
+		p.conn = nil
+		return err
+	}
+	return nil
+}
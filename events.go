@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	ticketEventSLA    = "sla"
+	ticketEventUnseal = "unseal"
+)
+
+type ticketEvent struct {
+	At       time.Time `bson:"at"`
+	UserID   string    `bson:"user_id"`
+	UserName string    `bson:"user_name"`
+	Kind     string    `bson:"kind,omitempty"`
+	Action   string    `bson:"action"`
+}
+
+var componentActionLabels = map[string]string{
+	actionCloseRequest:      "티켓 닫기",
+	closeCodeSelectID:       "종료 코드 선택",
+	actionClaim:             "담당자 배정",
+	actionConfirmSelf:       "네, 해결되었습니다",
+	actionReopen:            "티켓 재오픈",
+	actionDeletePermanent:   "티켓 삭제",
+	actionReleaseQuarantine: "스팸 검토 통과",
+}
+
+func memberDisplayName(m *discordgo.Member) string {
+	if m.Nick != "" {
+		return m.Nick
+	}
+	if m.User.GlobalName != "" {
+		return m.User.GlobalName
+	}
+	return m.User.Username
+}
+
+func (e ticketEvent) line() string {
+	if e.Kind == ticketEventSLA {
+		if e.Action == "해제" {
+			return fmt.Sprintf("⏱️ %s 님이 개별 처리 기한을 해제했습니다", e.UserName)
+		}
+		return fmt.Sprintf("⏱️ %s 님이 처리 기한을 %s(으)로 지정했습니다", e.UserName, e.Action)
+	}
+	if e.Kind == ticketEventUnseal {
+		return fmt.Sprintf("🔓 %s 님이 암호화 기록을 열람했습니다 (사유: %s)", e.UserName, e.Action)
+	}
+	return fmt.Sprintf("🔘 %s 님이 '%s'을(를) 눌렀습니다", e.UserName, e.Action)
+}
+
+func recordComponentInteraction(s *discordgo.Session, i *discordgo.InteractionCreate, action string) {
+	label, ok := componentActionLabels[action]
+	if !ok || i.Member == nil {
+		return
+	}
+	event := ticketEvent{At: time.Now(), UserID: i.Member.User.ID, UserName: memberDisplayName(i.Member), Action: label}
+	t, err := findTicket(i.ChannelID)
+	if err != nil {
+		return
+	}
+	if featuresFor(t.Category).Anonymous && event.UserID == t.OwnerID {
+		event.UserName = anonymousDisplayName
+	}
+	if err := updateTicket(t.ChannelID, bson.M{"$push": bson.M{"events": event}}); err != nil {
+		log.Printf("Could not record interaction event: %v", err)
+	}
+	if getConfig().PostInteractionEvents {
+		s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{Content: "-# " + event.line(), AllowedMentions: &discordgo.MessageAllowedMentions{}})
+	}
+}
+
+func eventLineHTML(e ticketEvent) string {
+	return fmt.Sprintf(`<div class="system-line">%s <span class="timestamp">%s</span></div>`, html.EscapeString(e.line()), e.At.In(kstLocation).Format("2006-01-02 15:04:05"))
+}
+
+func isEventEcho(msg *discordgo.Message) bool {
+	return msg.Author.Bot && strings.HasPrefix(msg.Content, "-# 🔘 ")
+}
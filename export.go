@@ -0,0 +1,149 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxExportUploadSize    = 10 << 20
+	exportProgressInterval = 5 * time.Second
+)
+
+func parseExportMonth(value string) (time.Time, error) {
+	return time.ParseInLocation("2006-01", strings.TrimSpace(value), kstLocation)
+}
+
+func listTranscripts(month time.Time) ([]storedTranscript, error) {
+	filter := bson.M{"created_at": bson.M{"$gte": month, "$lt": month.AddDate(0, 1, 0)}}
+	cursor, err := app().Transcripts.Find(context.TODO(), filter, options.Find().SetSort(bson.M{"created_at": 1}).SetProjection(bson.M{"html": 0}))
+	if err != nil {
+		return nil, err
+	}
+	var docs []storedTranscript
+	if err := cursor.All(context.TODO(), &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func exportTranscripts(month time.Time, w io.Writer, progress func(done, total int)) (int, error) {
+	docs, err := listTranscripts(month)
+	if err != nil {
+		return 0, err
+	}
+	archive := zip.NewWriter(w)
+	var rows strings.Builder
+	done := 0
+	for _, doc := range docs {
+		content, err := loadTranscriptHTML(doc.ChannelID)
+		if err != nil {
+			log.Printf("Skipping transcript '%s' in export: %v", doc.Name, err)
+			continue
+		}
+		fileName := fmt.Sprintf("transcripts/%s-%s.html", doc.Name, doc.ChannelID)
+		f, err := archive.Create(fileName)
+		if err != nil {
+			return done, err
+		}
+		if _, err := io.WriteString(f, content); err != nil {
+			return done, err
+		}
+		rows.WriteString(fmt.Sprintf("<tr><td><a href=\"%s\">%s</a></td><td>%s</td><td>%s</td><td>%d</td><td>%s</td></tr>\n",
+			html.EscapeString(fileName), html.EscapeString(doc.Name), html.EscapeString(doc.Category), html.EscapeString(doc.OwnerID), doc.MessageCount, doc.CreatedAt.In(kstLocation).Format("2006-01-02 15:04")))
+		done++
+		if progress != nil {
+			progress(done, len(docs))
+		}
+	}
+	index, err := archive.Create("index.html")
+	if err != nil {
+		return done, err
+	}
+	fmt.Fprintf(index, `<!DOCTYPE html>
+<html lang="ko">
+<head><meta charset="UTF-8"><title>대화록 %s</title>
+<style>body{font-family:sans-serif;margin:2em}table{border-collapse:collapse;width:100%%}th,td{border:1px solid #ccc;padding:6px 10px;text-align:left}th{background:#f2f2f2}</style>
+</head>
+<body>
+<h1>대화록 목록 (%s)</h1>
+<p>총 %d건 · 생성 시각 %s</p>
+<table>
+<tr><th>티켓</th><th>창구</th><th>민원인 ID</th><th>메시지 수</th><th>보관 시각</th></tr>
+%s</table>
+</body>
+</html>
+`, month.Format("2006-01"), month.Format("2006-01"), done, time.Now().In(kstLocation).Format("2006-01-02 15:04"), rows.String())
+	return done, archive.Close()
+}
+
+func exportCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "대화록내보내기",
+		Description:              "한 달 동안 보관된 대화록을 zip 파일로 내보냅니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "month", Description: "내보낼 달 (예: 2026-01)", Required: true},
+		},
+	}
+}
+
+func handleTranscriptExport(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	value := i.ApplicationCommandData().Options[0].StringValue()
+	month, err := parseExportMonth(value)
+	if err != nil {
+		respondError(s, i, errInvalidExportMonth, nil, value)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}})
+	go runTranscriptExport(s, i, month)
+}
+
+func runTranscriptExport(s *discordgo.Session, i *discordgo.InteractionCreate, month time.Time) {
+	label := month.Format("2006-01")
+	edit := func(description string, color int) {
+		embeds := []*discordgo.MessageEmbed{{Title: "대화록 내보내기", Description: description, Color: color}}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+	}
+	edit(fmt.Sprintf("%s 대화록을 모으고 있습니다...", label), colorGray)
+	lastUpdate := time.Now()
+	var buf bytes.Buffer
+	count, err := exportTranscripts(month, &buf, func(done, total int) {
+		if time.Since(lastUpdate) < exportProgressInterval {
+			return
+		}
+		lastUpdate = time.Now()
+		edit(fmt.Sprintf("%s 대화록을 모으고 있습니다... (%d/%d)", label, done, total), colorGray)
+	})
+	if err != nil {
+		log.Printf("Transcript export for %s failed: %v", label, err)
+		edit(fmt.Sprintf("%s 대화록을 내보내지 못했습니다: %v", label, err), colorRed)
+		return
+	}
+	if count == 0 {
+		edit(fmt.Sprintf("%s에 보관된 대화록이 없습니다.", label), colorYellow)
+		return
+	}
+	if buf.Len() > maxExportUploadSize {
+		edit(fmt.Sprintf("%s 대화록 %d건의 압축 파일이 %.1fMB로 디스코드 업로드 한도를 넘습니다.\n서버에서 `potatobot export --month %s` 명령으로 내보내주세요.", label, count, float64(buf.Len())/(1<<20), label), colorYellow)
+		return
+	}
+	embeds := []*discordgo.MessageEmbed{{Title: "대화록 내보내기", Description: fmt.Sprintf("%s 대화록 %d건을 내보냈습니다. 압축 파일의 index.html에서 목록을 확인할 수 있습니다.", label, count), Color: colorGreen}}
+	files := []*discordgo.File{{Name: fmt.Sprintf("transcripts-%s.zip", label), ContentType: "application/zip", Reader: &buf}}
+	if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds, Files: files}); err != nil {
+		log.Printf("Could not upload transcript export for %s: %v", label, err)
+	}
+	log.Printf("Exported %d transcripts for %s (%d bytes).", count, label, buf.Len())
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	exportDateISO    = "iso"
+	exportDateKorean = "ko"
+	exportFormatCSV  = "csv"
+	exportFormatXLSX = "xlsx"
+)
+
+type exportLocale struct {
+	DateStyle         string `bson:"date_style,omitempty"`
+	ThousandSeparator bool   `bson:"thousand_separator"`
+}
+
+var exportDateChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "YYYY-MM-DD", Value: exportDateISO},
+	{Name: "한국식 (2026년 1월 2일)", Value: exportDateKorean},
+}
+
+var exportFormatChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "CSV", Value: exportFormatCSV},
+	{Name: "Excel (XLSX)", Value: exportFormatXLSX},
+}
+
+type exportSheet struct {
+	Name string
+	Rows [][]string
+}
+
+func (l exportLocale) date(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	t = t.In(kstLocation)
+	if l.DateStyle == exportDateKorean {
+		return fmt.Sprintf("%d년 %d월 %d일 %s", t.Year(), t.Month(), t.Day(), t.Format("15:04:05"))
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func (l exportLocale) number(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	if !l.ThousandSeparator {
+		return digits
+	}
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var sb strings.Builder
+	for idx, r := range digits {
+		if idx > 0 && (len(digits)-idx)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(r)
+	}
+	return sign + sb.String()
+}
+
+func (l exportLocale) label() string {
+	date := "YYYY-MM-DD"
+	if l.DateStyle == exportDateKorean {
+		date = "한국식 날짜"
+	}
+	if l.ThousandSeparator {
+		return date + " · 천 단위 쉼표"
+	}
+	return date
+}
+
+func writeCSVSheet(w io.Writer, sheet exportSheet) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	cw.WriteAll(sheet.Rows)
+	return cw.Error()
+}
+
+func writeXLSX(w io.Writer, sheets []exportSheet) error {
+	archive := zip.NewWriter(w)
+	var overrides, workbookSheets, rels strings.Builder
+	for idx, sheet := range sheets {
+		n := idx + 1
+		overrides.WriteString(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n))
+		workbookSheets.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, html.EscapeString(sheet.Name), n, n))
+		rels.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n))
+	}
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` + overrides.String() + `</Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>` + workbookSheets.String() + `</sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?><Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + rels.String() + `</Relationships>`},
+	}
+	for idx, sheet := range sheets {
+		parts = append(parts, struct{ name, body string }{fmt.Sprintf("xl/worksheets/sheet%d.xml", idx+1), xlsxSheetXML(sheet)})
+	}
+	for _, part := range parts {
+		f, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
+
+func xlsxSheetXML(sheet exportSheet) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for r, row := range sheet.Rows {
+		sb.WriteString(fmt.Sprintf(`<row r="%d">`, r+1))
+		for c, value := range row {
+			sb.WriteString(fmt.Sprintf(`<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, xlsxColumn(c), r+1, html.EscapeString(value)))
+		}
+		sb.WriteString(`</row>`)
+	}
+	sb.WriteString(`</sheetData></worksheet>`)
+	return sb.String()
+}
+
+func xlsxColumn(idx int) string {
+	name := ""
+	for idx >= 0 {
+		name = string(rune('A'+idx%26)) + name
+		idx = idx/26 - 1
+	}
+	return name
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const anonymousDisplayName = "익명 민원인"
+
+type categoryFeatures struct {
+	Transcripts bool `bson:"transcripts"`
+	CSAT        bool `bson:"csat"`
+	AutoAssign  bool `bson:"auto_assign"`
+	Anonymous   bool `bson:"anonymous"`
+	Encrypted   bool `bson:"encrypted"`
+}
+
+var defaultCategoryFeatures = categoryFeatures{Transcripts: true, CSAT: true}
+
+func featuresFor(category string) categoryFeatures {
+	if features, ok := getConfig().CategoryFeatures[category]; ok {
+		return features
+	}
+	return defaultCategoryFeatures
+}
+
+func ticketCategory(channel *discordgo.Channel) string {
+	return strings.Split(channel.Name, "-")[0]
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	ticketModeChannel = "channel"
+	ticketModeForum   = "forum"
+
+	forumClosedTagName = "종료"
+)
+
+var ticketModeChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "개별 채널", Value: ticketModeChannel},
+	{Name: "포럼 게시글", Value: ticketModeForum},
+}
+
+func forumModeEnabled(cfg guildConfig) bool {
+	return cfg.TicketMode == ticketModeForum && cfg.ForumChannelID != ""
+}
+
+func forumTagID(s *discordgo.Session, forumID, name string) (string, error) {
+	forum, err := s.Channel(forumID)
+	if err != nil {
+		return "", err
+	}
+	for _, tag := range forum.AvailableTags {
+		if tag.Name == name {
+			return tag.ID, nil
+		}
+	}
+	tags := append(forum.AvailableTags, discordgo.ForumTag{Name: name})
+	forum, err = s.ChannelEditComplex(forumID, &discordgo.ChannelEdit{AvailableTags: &tags})
+	if err != nil {
+		return "", fmt.Errorf("could not create forum tag '%s': %w", name, err)
+	}
+	for _, tag := range forum.AvailableTags {
+		if tag.Name == name {
+			return tag.ID, nil
+		}
+	}
+	return "", fmt.Errorf("forum tag '%s' missing after creation", name)
+}
+
+func createForumTicket(s *discordgo.Session, forumID, name, topic string, starter *discordgo.MessageSend) (*discordgo.Channel, error) {
+	tagID, err := forumTagID(s, forumID, topic)
+	if err != nil {
+		return nil, err
+	}
+	return s.ForumThreadStartComplex(forumID, &discordgo.ThreadStart{Name: name, AppliedTags: []string{tagID}}, starter)
+}
+
+func setForumTicketClosed(s *discordgo.Session, t *ticket, closed bool) error {
+	thread, err := s.Channel(t.ChannelID)
+	if err != nil {
+		return err
+	}
+	closedTagID, err := forumTagID(s, thread.ParentID, forumClosedTagName)
+	if err != nil {
+		return err
+	}
+	var tags []string
+	for _, id := range thread.AppliedTags {
+		if id != closedTagID {
+			tags = append(tags, id)
+		}
+	}
+	if closed {
+		tags = append(tags, closedTagID)
+	}
+	_, err = s.ChannelEditComplex(t.ChannelID, &discordgo.ChannelEdit{Locked: &closed, AppliedTags: &tags})
+	return err
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	maxGreetingButtons   = 5
+	greetingButtonPrefix = "greeting_button:"
+)
+
+type greetingButton struct {
+	ID      string `bson:"id"`
+	Label   string `bson:"label"`
+	Emoji   string `bson:"emoji,omitempty"`
+	URL     string `bson:"url,omitempty"`
+	Content string `bson:"content,omitempty"`
+}
+
+func greetingButtonsCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	topicOption := &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: true, Choices: ticketTopicChoices()}
+	return &discordgo.ApplicationCommand{
+		Name:                     "안내버튼",
+		Description:              "티켓 첫 메시지에 붙는 창구별 안내 버튼과 링크 버튼을 관리합니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "보기", Description: "창구의 안내 버튼을 확인합니다.", Options: []*discordgo.ApplicationCommandOption{topicOption}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "추가", Description: "안내 버튼이나 링크 버튼을 추가합니다.", Options: []*discordgo.ApplicationCommandOption{
+				topicOption,
+				{Type: discordgo.ApplicationCommandOptionString, Name: "label", Description: "버튼 이름 (예: 필요서류 안내)", Required: true, MaxLength: 80},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "content", Description: "누르면 보여줄 안내 내용 (\\n으로 줄바꿈)", Required: false, MaxLength: 4000},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "url", Description: "누르면 열릴 링크 (예: 신청서 주소)", Required: false},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "emoji", Description: "버튼 이모지", Required: false},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "삭제", Description: "안내 버튼을 삭제합니다.", Options: []*discordgo.ApplicationCommandOption{
+				topicOption,
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "number", Description: "/안내버튼 보기에 표시된 번호", Required: true},
+			}},
+		},
+	}
+}
+
+func greetingButtonRow(topic string) discordgo.MessageComponent {
+	buttons := getConfig().CategoryButtons[topic]
+	if len(buttons) == 0 {
+		return nil
+	}
+	var components []discordgo.MessageComponent
+	for _, b := range buttons {
+		button := discordgo.Button{Label: b.Label, Style: discordgo.SecondaryButton, CustomID: greetingButtonPrefix + b.ID}
+		if b.URL != "" {
+			button = discordgo.Button{Label: b.Label, Style: discordgo.LinkButton, URL: b.URL}
+		}
+		if b.Emoji != "" {
+			button.Emoji = &discordgo.ComponentEmoji{Name: b.Emoji}
+		}
+		components = append(components, button)
+	}
+	return discordgo.ActionsRow{Components: components}
+}
+
+func greetingButtonList(topic string, buttons []greetingButton) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: fmt.Sprintf("%s 안내 버튼", topic), Color: colorBlue}
+	if len(buttons) == 0 {
+		embed.Description = "등록된 안내 버튼이 없습니다. /안내버튼 추가 명령어로 버튼을 만들 수 있습니다."
+		return embed
+	}
+	for n, b := range buttons {
+		value := b.URL
+		if value == "" {
+			value = b.Content
+			if runes := []rune(value); len(runes) > 200 {
+				value = string(runes[:200]) + "…"
+			}
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: fmt.Sprintf("%d. %s %s", n+1, b.Emoji, b.Label), Value: value, Inline: false})
+	}
+	return embed
+}
+
+func handleGreetingButtons(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range sub.Options {
+		options[opt.Name] = opt
+	}
+	topic := options["topic"].StringValue()
+	buttons := getConfig().CategoryButtons[topic]
+	var summary string
+	var updated []greetingButton
+	switch sub.Name {
+	case "보기":
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{greetingButtonList(topic, buttons)}}})
+		return
+	case "추가":
+		if len(buttons) >= maxGreetingButtons {
+			respondError(s, i, errGreetingButtonLimit, nil, maxGreetingButtons)
+			return
+		}
+		b := greetingButton{ID: strconv.FormatInt(time.Now().UnixNano(), 36), Label: strings.TrimSpace(options["label"].StringValue())}
+		if opt, ok := options["content"]; ok {
+			b.Content = strings.ReplaceAll(opt.StringValue(), `\n`, "\n")
+		}
+		if opt, ok := options["url"]; ok {
+			b.URL = strings.TrimSpace(opt.StringValue())
+		}
+		if opt, ok := options["emoji"]; ok {
+			b.Emoji = strings.TrimSpace(opt.StringValue())
+		}
+		u, err := url.Parse(b.URL)
+		validURL := err == nil && (u.Scheme == "https" || u.Scheme == "http") && u.Host != ""
+		if (b.URL == "") == (b.Content == "") || (b.URL != "" && !validURL) {
+			respondError(s, i, errGreetingButtonTarget, nil)
+			return
+		}
+		updated = append(append([]greetingButton(nil), buttons...), b)
+		summary = fmt.Sprintf("%s 창구에 '%s' 버튼을 추가했습니다. 새로 만들어지는 티켓부터 표시됩니다.", topic, b.Label)
+	case "삭제":
+		n := int(options["number"].IntValue())
+		if n < 1 || n > len(buttons) {
+			respondError(s, i, errGreetingButtonNotFound, nil, n)
+			return
+		}
+		updated = append(updated, buttons[:n-1]...)
+		updated = append(updated, buttons[n:]...)
+		summary = fmt.Sprintf("%s 창구에서 '%s' 버튼을 삭제했습니다.", topic, buttons[n-1].Label)
+	}
+	err := updateConfig(func(cfg *guildConfig) {
+		if len(updated) == 0 {
+			delete(cfg.CategoryButtons, topic)
+		} else {
+			cfg.CategoryButtons[topic] = updated
+		}
+	})
+	if err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	log.Printf("%s updated greeting buttons of %s.", i.Member.User.Username, topic)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "안내 버튼 변경", Description: summary, Color: colorGreen}}}})
+}
+
+func handleGreetingButton(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	id := strings.TrimPrefix(i.MessageComponentData().CustomID, greetingButtonPrefix)
+	for _, buttons := range getConfig().CategoryButtons {
+		for _, b := range buttons {
+			if b.ID != id {
+				continue
+			}
+			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: b.Label, Description: b.Content, Color: colorBlue}}}})
+			return
+		}
+	}
+	respondError(s, i, errStaleComponent, nil)
+}
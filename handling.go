@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const maxHandlingAgents = 10
+
+func (t *ticket) totalHandlingTime() time.Duration {
+	var total time.Duration
+	for _, d := range t.HandlingTime {
+		total += d
+	}
+	return total
+}
+
+func pauseHandling(channelID string, now time.Time) {
+	t, err := findTicket(channelID)
+	if err != nil || t.HandlingSince.IsZero() {
+		return
+	}
+	update := bson.M{"$unset": bson.M{"handling_since": ""}}
+	if t.AssigneeID != "" && now.After(t.HandlingSince) {
+		update["$inc"] = bson.M{"handling_time." + t.AssigneeID: now.Sub(t.HandlingSince)}
+	}
+	if _, err := app().Tickets.UpdateOne(context.TODO(), bson.M{"channel_id": channelID, "handling_since": t.HandlingSince}, update); err != nil {
+		log.Printf("Could not record handling time for '%s': %v", t.Name(), err)
+	}
+}
+
+func resumeHandling(channelID string, now time.Time) {
+	filter := bson.M{"channel_id": channelID, "status": ticketStatusOpen, "assignee_id": bson.M{"$nin": []interface{}{"", nil}}, "awaiting_reply_since": bson.M{"$exists": true}, "handling_since": bson.M{"$exists": false}}
+	if _, err := app().Tickets.UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{"handling_since": now}}); err != nil {
+		log.Printf("Could not start handling timer for %s: %v", channelID, err)
+	}
+}
+
+func handlingBreakdown(times map[string]time.Duration, locale exportLocale) string {
+	agents := make([]string, 0, len(times))
+	for id := range times {
+		agents = append(agents, id)
+	}
+	sort.Strings(agents)
+	var parts []string
+	for _, id := range agents {
+		parts = append(parts, fmt.Sprintf("%s=%s", id, locale.number(int64(times[id]/time.Minute))))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func handlingTimeSummary(tickets []ticket) string {
+	totals := make(map[string]time.Duration)
+	counts := make(map[string]int)
+	for _, t := range tickets {
+		for agentID, d := range t.HandlingTime {
+			totals[agentID] += d
+			counts[agentID]++
+		}
+	}
+	if len(totals) == 0 {
+		return "기록 없음"
+	}
+	agents := make([]string, 0, len(totals))
+	for id := range totals {
+		agents = append(agents, id)
+	}
+	sort.Slice(agents, func(a, b int) bool { return totals[agents[a]] > totals[agents[b]] })
+	if len(agents) > maxHandlingAgents {
+		agents = agents[:maxHandlingAgents]
+	}
+	var lines []string
+	for _, id := range agents {
+		lines = append(lines, fmt.Sprintf("<@%s> %s (%d건, 건당 %s)", id, formatSLADuration(totals[id]), counts[id], formatSLADuration(totals[id]/time.Duration(counts[id]))))
+	}
+	return strings.Join(lines, "\n")
+}
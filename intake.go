@@ -0,0 +1,106 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	ticketModalPrefix     = "ticket_modal_submit_"
+	maxIntakeQuestions    = 5
+	maxIntakeQuestionSize = 4000
+)
+
+type intakeAnswer struct {
+	ID    string `bson:"id"`
+	Label string `bson:"label"`
+	Value string `bson:"value"`
+}
+
+type intakeQuestion struct {
+	ID          string                   `bson:"id"`
+	Label       string                   `bson:"label"`
+	Placeholder string                   `bson:"placeholder,omitempty"`
+	Style       discordgo.TextInputStyle `bson:"style"`
+	Required    bool                     `bson:"required"`
+	MaxLength   int                      `bson:"max_length"`
+}
+
+func intakeQuestions(topic string) []intakeQuestion {
+	if questions, ok := getConfig().IntakeQuestions[topic]; ok && len(questions) > 0 {
+		return withUrgencyQuestion(questions)
+	}
+	return withUrgencyQuestion(defaultIntakeQuestions(topic))
+}
+
+func defaultIntakeQuestions(topic string) []intakeQuestion {
+	questions := []intakeQuestion{
+		{ID: "nickname", Label: "민원인 닉네임", Placeholder: "로블록스 닉네임", Style: discordgo.TextInputShort, Required: true, MaxLength: 100},
+		{ID: "subject", Label: "제목", Placeholder: "민원 내용을 한 줄로 요약해주세요.", Style: discordgo.TextInputShort, Required: true, MaxLength: 100},
+		{ID: "content", Label: "민원 내용", Placeholder: "문의하실 내용을 자세하게 적어주세요.", Style: discordgo.TextInputParagraph, Required: true, MaxLength: 4000},
+	}
+	if !featuresFor(topic).Anonymous {
+		questions = append(questions, intakeQuestion{ID: "contact", Label: "연락처 또는 신원 확인 정보 (선택)", Placeholder: "디스코드 외 연락 수단이나 주민 ID 등", Style: discordgo.TextInputShort, Required: false, MaxLength: 200})
+	}
+	return questions
+}
+
+func intakeModal(topic string) *discordgo.InteractionResponseData {
+	var rows []discordgo.MessageComponent
+	for _, q := range intakeQuestions(topic) {
+		rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.TextInput{
+			CustomID:    q.ID,
+			Label:       q.Label,
+			Style:       q.Style,
+			Placeholder: q.Placeholder,
+			Required:    q.Required,
+			MaxLength:   q.MaxLength,
+		}}})
+	}
+	return &discordgo.InteractionResponseData{CustomID: ticketModalPrefix + topic, Title: "민원 접수", Components: rows}
+}
+
+func parseIntakeAnswers(topic string, data discordgo.ModalSubmitInteractionData) []intakeAnswer {
+	values := make(map[string]string)
+	for _, row := range data.Components {
+		actionsRow, ok := row.(*discordgo.ActionsRow)
+		if !ok {
+			continue
+		}
+		for _, comp := range actionsRow.Components {
+			if input, ok := comp.(*discordgo.TextInput); ok {
+				values[input.CustomID] = strings.TrimSpace(input.Value)
+			}
+		}
+	}
+	var answers []intakeAnswer
+	for _, q := range intakeQuestions(topic) {
+		answers = append(answers, intakeAnswer{ID: q.ID, Label: q.Label, Value: values[q.ID]})
+	}
+	return answers
+}
+
+func intakeValue(answers []intakeAnswer, id string) string {
+	for _, a := range answers {
+		if a.ID == id {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func intakeFields(answers []intakeAnswer, anonymous bool) []*discordgo.MessageEmbedField {
+	var fields []*discordgo.MessageEmbedField
+	for _, a := range answers {
+		value := a.Value
+		if value == "" || a.ID == urgencyQuestionID {
+			continue
+		}
+		if anonymous && a.ID == "nickname" {
+			value = anonymousDisplayName
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{Name: a.Label, Value: value, Inline: false})
+	}
+	return fields
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"unicode"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultWorkingLanguage = "ko"
+	translatorTag          = "번역필요"
+	minDetectLetters       = 8
+	maxTranslateTextLength = 1000
+)
+
+type ticketLanguage struct {
+	Code  string
+	Name  string
+	Table *unicode.RangeTable
+}
+
+var ticketLanguages = []ticketLanguage{
+	{Code: "ko", Name: "한국어", Table: unicode.Hangul},
+	{Code: "ja", Name: "일본어", Table: unicode.Hiragana},
+	{Code: "zh", Name: "중국어", Table: unicode.Han},
+	{Code: "ru", Name: "러시아어", Table: unicode.Cyrillic},
+	{Code: "th", Name: "태국어", Table: unicode.Thai},
+	{Code: "ar", Name: "아랍어", Table: unicode.Arabic},
+	{Code: "en", Name: "영어", Table: unicode.Latin},
+}
+
+func languageChoices() []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, lang := range ticketLanguages {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: lang.Name, Value: lang.Code})
+	}
+	return choices
+}
+
+func languageName(code string) string {
+	for _, lang := range ticketLanguages {
+		if lang.Code == code {
+			return lang.Name
+		}
+	}
+	return code
+}
+
+func workingLanguage() string {
+	if lang := getConfig().WorkingLanguage; lang != "" {
+		return lang
+	}
+	return defaultWorkingLanguage
+}
+
+func detectLanguage(text string) string {
+	counts := make(map[string]int)
+	total := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if unicode.Is(unicode.Katakana, r) {
+			counts["ja"]++
+			total++
+			continue
+		}
+		for _, lang := range ticketLanguages {
+			if unicode.Is(lang.Table, r) {
+				counts[lang.Code]++
+				total++
+				break
+			}
+		}
+	}
+	if total < minDetectLetters {
+		return ""
+	}
+	if counts["ja"] > 0 {
+		counts["ja"] += counts["zh"]
+		counts["zh"] = 0
+	}
+	best := ""
+	for _, lang := range ticketLanguages {
+		if counts[lang.Code] > counts[best] {
+			best = lang.Code
+		}
+	}
+	return best
+}
+
+func intakeText(answers []intakeAnswer) string {
+	var parts []string
+	for _, a := range answers {
+		if a.ID != "nickname" && a.ID != "contact" {
+			parts = append(parts, a.Value)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func applyTicketLanguage(s *discordgo.Session, t *ticket, lang string) {
+	update := bson.M{"$set": bson.M{"language": lang}}
+	t.Language = lang
+	if lang != workingLanguage() {
+		t.Translation = true
+		update = bson.M{"$set": bson.M{"language": lang, "translation": true}, "$addToSet": bson.M{"tags": translatorTag}}
+		if !containsID(t.Tags, translatorTag) {
+			t.Tags = append(t.Tags, translatorTag)
+		}
+	}
+	err := updateTicket(t.ChannelID, update)
+	if err != nil {
+		log.Printf("Could not record language of ticket '%s': %v", t.Name(), err)
+		return
+	}
+	if !t.Translation {
+		return
+	}
+	var mentions []string
+	for id, skills := range getConfig().AgentSkills {
+		if containsSkill(skills, languageName(lang)) && id != t.OwnerID {
+			mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+		}
+	}
+	description := fmt.Sprintf("민원인이 %s(으)로 작성한 것으로 보입니다. '%s' 태그를 추가하고 번역 도우미를 켰습니다.\n/번역 명령어로 끌 수 있습니다.", languageName(lang), translatorTag)
+	if len(mentions) == 0 {
+		description += fmt.Sprintf("\n%s 스킬이 등록된 담당자가 없습니다.", languageName(lang))
+	}
+	s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+		Content: strings.Join(mentions, " "),
+		Embeds:  []*discordgo.MessageEmbed{{Title: "외국어 민원", Description: description, Color: colorYellow}},
+	})
+	log.Printf("Ticket '%s' detected as '%s'; translation helper enabled.", t.Name(), lang)
+	evaluateRules(s, t, ruleEventUpdated)
+}
+
+func translateURL(text, target string) string {
+	runes := []rune(text)
+	if len(runes) > maxTranslateTextLength {
+		runes = runes[:maxTranslateTextLength]
+	}
+	return fmt.Sprintf("https://translate.google.com/?sl=auto&tl=%s&op=translate&text=%s", target, url.QueryEscape(string(runes)))
+}
+
+func handleTicketLanguage(s *discordgo.Session, m *discordgo.MessageCreate, t *ticket) {
+	if strings.TrimSpace(m.Content) == "" {
+		return
+	}
+	if t.Language == "" && m.Author.ID == t.OwnerID {
+		if lang := detectLanguage(m.Content); lang != "" {
+			applyTicketLanguage(s, t, lang)
+		}
+	}
+	if !t.Translation || t.Language == "" {
+		return
+	}
+	target := t.Language
+	if m.Author.ID == t.OwnerID {
+		target = workingLanguage()
+	}
+	if detectLanguage(m.Content) == target {
+		return
+	}
+	s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Content:         fmt.Sprintf("-# 🌐 [%s 번역 보기](<%s>)", languageName(target), translateURL(m.Content, target)),
+		Reference:       m.Reference(),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+}
+
+func handleTranslationToggle(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	enabled := options["enabled"].BoolValue()
+	update := bson.M{"translation": enabled}
+	if lang, ok := options["language"]; ok {
+		t.Language = lang.StringValue()
+		update["language"] = t.Language
+	}
+	if enabled && t.Language == "" {
+		respondError(s, i, errTicketLanguageUnknown, nil)
+		return
+	}
+	if err := updateTicket(t.ChannelID, bson.M{"$set": update}); err != nil {
+		respondError(s, i, errTicketSaveFailed, err)
+		return
+	}
+	description := "이 티켓의 번역 도우미를 껐습니다."
+	if enabled {
+		description = fmt.Sprintf("이 티켓의 번역 도우미를 켰습니다. 민원인 언어: %s", languageName(t.Language))
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "번역 도우미", Description: description, Color: colorBlue}}}})
+}
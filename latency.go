@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultLatencyBudget     = 1500 * time.Millisecond
+	latencySampleWindow      = 200
+	minLatencySamples        = 20
+	latencyAlertCooldown     = 10 * time.Minute
+	interactionPendingMaxAge = time.Minute
+)
+
+var (
+	latencyMu           sync.Mutex
+	pendingInteractions = make(map[string]pendingInteraction)
+	handlerLatencies    = make(map[string]*handlerLatency)
+	latencyBudget       = loadLatencyBudget()
+)
+
+type pendingInteraction struct {
+	Handler    string
+	ReceivedAt time.Time
+}
+
+type handlerLatency struct {
+	samples   []time.Duration
+	next      int
+	count     int64
+	slow      int64
+	lastAlert time.Time
+}
+
+type latencyTransport struct {
+	base http.RoundTripper
+}
+
+func loadLatencyBudget() time.Duration {
+	v := os.Getenv("INTERACTION_LATENCY_BUDGET")
+	if v == "" {
+		return defaultLatencyBudget
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("Invalid INTERACTION_LATENCY_BUDGET '%s': %v", v, err)
+		return defaultLatencyBudget
+	}
+	return d
+}
+
+func instrumentInteractionLatency(s *discordgo.Session) {
+	base := s.Client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	s.Client.Transport = latencyTransport{base: base}
+}
+
+func interactionHandlerName(i *discordgo.InteractionCreate) string {
+	var name string
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		return "/" + i.ApplicationCommandData().Name
+	case discordgo.InteractionMessageComponent:
+		name = i.MessageComponentData().CustomID
+	case discordgo.InteractionModalSubmit:
+		name = i.ModalSubmitData().CustomID
+	default:
+		return "unknown"
+	}
+	if strings.HasPrefix(name, ticketModalPrefix) {
+		return strings.TrimSuffix(ticketModalPrefix, "_")
+	}
+	if idx := strings.Index(name, ":"); idx >= 0 {
+		return name[:idx]
+	}
+	return name
+}
+
+func trackInteraction(i *discordgo.InteractionCreate) func() {
+	latencyMu.Lock()
+	pendingInteractions[i.ID] = pendingInteraction{Handler: interactionHandlerName(i), ReceivedAt: time.Now()}
+	latencyMu.Unlock()
+	return func() {
+		latencyMu.Lock()
+		defer latencyMu.Unlock()
+		for id, p := range pendingInteractions {
+			if id == i.ID || time.Since(p.ReceivedAt) > interactionPendingMaxAge {
+				delete(pendingInteractions, id)
+			}
+		}
+	}
+}
+
+func (t latencyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		incCounter("potatobot_discord_api_errors_total", metricLabel("status", "network"))
+		reportDiscordAPIError(req, err.Error())
+	} else if resp.StatusCode >= 400 {
+		incCounter("potatobot_discord_api_errors_total", metricLabel("status", strconv.Itoa(resp.StatusCode)))
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			reportDiscordAPIError(req, resp.Status)
+		}
+	}
+	if req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/callback") {
+		parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+		if len(parts) >= 4 && parts[len(parts)-4] == "interactions" {
+			recordInteractionResponse(parts[len(parts)-3])
+		}
+	}
+	return resp, err
+}
+
+func recordInteractionResponse(interactionID string) {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	p, ok := pendingInteractions[interactionID]
+	if !ok {
+		return
+	}
+	delete(pendingInteractions, interactionID)
+	elapsed := time.Since(p.ReceivedAt)
+	observeHistogram("potatobot_interaction_latency_seconds", metricLabel("handler", p.Handler), interactionLatencyBuckets, elapsed.Seconds())
+	h, ok := handlerLatencies[p.Handler]
+	if !ok {
+		h = &handlerLatency{}
+		handlerLatencies[p.Handler] = h
+	}
+	if len(h.samples) < latencySampleWindow {
+		h.samples = append(h.samples, elapsed)
+	} else {
+		h.samples[h.next] = elapsed
+		h.next = (h.next + 1) % latencySampleWindow
+	}
+	h.count++
+	if elapsed > latencyBudget {
+		h.slow++
+	}
+	if len(h.samples) < minLatencySamples || time.Since(h.lastAlert) < latencyAlertCooldown {
+		return
+	}
+	if p95 := percentileDuration(h.samples, 0.95); p95 > latencyBudget {
+		h.lastAlert = time.Now()
+		log.Printf("Warning: Slow interaction handler '%s': p95 first response %s exceeds budget %s over the last %d interactions.", p.Handler, formatLatency(p95), formatLatency(latencyBudget), len(h.samples))
+	}
+}
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	latencyMu.Lock()
+	defer latencyMu.Unlock()
+	handlers := make([]string, 0, len(handlerLatencies))
+	for name := range handlerLatencies {
+		handlers = append(handlers, name)
+	}
+	sort.Strings(handlers)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintf(w, "# HELP potatobot_interaction_latency_budget_seconds First-response budget for interaction handlers.\n")
+	fmt.Fprintf(w, "# TYPE potatobot_interaction_latency_budget_seconds gauge\n")
+	fmt.Fprintf(w, "potatobot_interaction_latency_budget_seconds %g\n", latencyBudget.Seconds())
+	fmt.Fprintf(w, "# HELP potatobot_interaction_first_response_seconds Time from interaction receipt to first response.\n")
+	fmt.Fprintf(w, "# TYPE potatobot_interaction_first_response_seconds summary\n")
+	for _, name := range handlers {
+		h := handlerLatencies[name]
+		for _, q := range []float64{0.5, 0.95, 0.99} {
+			fmt.Fprintf(w, "potatobot_interaction_first_response_seconds{handler=%q,quantile=\"%g\"} %g\n", name, q, percentileDuration(h.samples, q).Seconds())
+		}
+		fmt.Fprintf(w, "potatobot_interaction_first_response_seconds_count{handler=%q} %d\n", name, h.count)
+	}
+	fmt.Fprintf(w, "# HELP potatobot_interaction_slow_total Interactions whose first response exceeded the budget.\n")
+	fmt.Fprintf(w, "# TYPE potatobot_interaction_slow_total counter\n")
+	for _, name := range handlers {
+		fmt.Fprintf(w, "potatobot_interaction_slow_total{handler=%q} %d\n", name, handlerLatencies[name].slow)
+	}
+	writeMetrics(w)
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	leaderboardCheckInterval = 10 * time.Minute
+	leaderboardHour          = 9
+	leaderboardSize          = 10
+)
+
+type staffStanding struct {
+	UserID    string
+	Claims    int
+	Messages  int
+	Responses []time.Duration
+}
+
+func weekStart(t time.Time) time.Time {
+	t = t.In(kstLocation)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, kstLocation)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func recordStaffMessage(m *discordgo.MessageCreate, t *ticket) {
+	if m.Author.ID == t.OwnerID || m.Member == nil || !hasSupportRole(m.Member) {
+		return
+	}
+	week := weekStart(m.Timestamp)
+	id := m.Author.ID + ":" + week.Format("2006-01-02")
+	update := bson.M{"$inc": bson.M{"messages": 1}, "$setOnInsert": bson.M{"user_id": m.Author.ID, "week": week}}
+	if _, err := app().StaffActivity.UpdateOne(context.TODO(), bson.M{"_id": id}, update, options.Update().SetUpsert(true)); err != nil {
+		log.Printf("Could not record staff message for '%s': %v", t.Name(), err)
+	}
+}
+
+func postWeeklyLeaderboard(s *discordgo.Session) error {
+	now := time.Now().In(kstLocation)
+	current := weekStart(now)
+	if now.Weekday() != time.Monday || now.Hour() < leaderboardHour {
+		return nil
+	}
+	if !getConfig().LeaderboardPostedWeek.Before(current) {
+		return nil
+	}
+	from := current.AddDate(0, 0, -7)
+	standings, err := weeklyStandings(from, current)
+	if err != nil {
+		return err
+	}
+	message := &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{leaderboardEmbed(from, current, standings)}}
+	if chartEmbeds, charts, err := trendChartAttachments(from, maxTrendChartEmbeds); err != nil {
+		log.Printf("Could not build trend charts for the weekly report: %v", err)
+	} else {
+		message.Embeds = append(message.Embeds, chartEmbeds...)
+		message.Files = charts
+	}
+	if _, err := s.ChannelMessageSendComplex(getConfig().LogChannelID, message); err != nil {
+		return fmt.Errorf("could not post weekly leaderboard: %w", err)
+	}
+	return updateConfig(func(c *guildConfig) { c.LeaderboardPostedWeek = current })
+}
+
+func weeklyStandings(from, to time.Time) ([]*staffStanding, error) {
+	byUser := make(map[string]*staffStanding)
+	standing := func(id string) *staffStanding {
+		if byUser[id] == nil {
+			byUser[id] = &staffStanding{UserID: id}
+		}
+		return byUser[id]
+	}
+	window := bson.M{"$gte": from, "$lt": to}
+	cursor, err := app().Tickets.Find(context.TODO(), bson.M{"$or": []bson.M{{"claimed_at": window}, {"first_response_at": window}}})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch tickets: %w", err)
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		return nil, fmt.Errorf("could not decode tickets: %w", err)
+	}
+	for _, t := range tickets {
+		if t.AssigneeID != "" && !t.ClaimedAt.Before(from) && t.ClaimedAt.Before(to) {
+			standing(t.AssigneeID).Claims++
+		}
+		if t.FirstResponderID != "" && !t.FirstResponseAt.Before(from) && t.FirstResponseAt.Before(to) {
+			st := standing(t.FirstResponderID)
+			st.Responses = append(st.Responses, t.FirstResponseAt.Sub(t.CreatedAt))
+		}
+	}
+	cursor, err = app().StaffActivity.Find(context.TODO(), bson.M{"week": from})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch staff activity: %w", err)
+	}
+	var activity []struct {
+		UserID   string `bson:"user_id"`
+		Messages int    `bson:"messages"`
+	}
+	if err := cursor.All(context.TODO(), &activity); err != nil {
+		return nil, fmt.Errorf("could not decode staff activity: %w", err)
+	}
+	for _, a := range activity {
+		standing(a.UserID).Messages += a.Messages
+	}
+	standings := make([]*staffStanding, 0, len(byUser))
+	for _, st := range byUser {
+		standings = append(standings, st)
+	}
+	return standings, nil
+}
+
+func leaderboardEmbed(from, to time.Time, standings []*staffStanding) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("주간 상담원 순위 (%s ~ %s)", from.Format("01/02"), to.AddDate(0, 0, -1).Format("01/02")),
+		Color:     colorBlue,
+		Timestamp: time.Now().In(kstLocation).Format(time.RFC3339),
+	}
+	if len(standings) == 0 {
+		embed.Description = "지난주에 기록된 상담 활동이 없습니다."
+		return embed
+	}
+	embed.Fields = []*discordgo.MessageEmbedField{
+		leaderboardField("담당 배정", standings, func(a, b *staffStanding) bool { return a.Claims > b.Claims }, func(st *staffStanding) string {
+			if st.Claims == 0 {
+				return ""
+			}
+			return fmt.Sprintf("%d건", st.Claims)
+		}),
+		leaderboardField("티켓 메시지", standings, func(a, b *staffStanding) bool { return a.Messages > b.Messages }, func(st *staffStanding) string {
+			if st.Messages == 0 {
+				return ""
+			}
+			return fmt.Sprintf("%d개", st.Messages)
+		}),
+		leaderboardField("평균 첫 응답", standings, func(a, b *staffStanding) bool {
+			if len(a.Responses) == 0 || len(b.Responses) == 0 {
+				return len(a.Responses) > 0
+			}
+			return averageDuration(a.Responses) < averageDuration(b.Responses)
+		}, func(st *staffStanding) string {
+			if len(st.Responses) == 0 {
+				return ""
+			}
+			return fmt.Sprintf("%s (%d건)", formatWait(averageDuration(st.Responses)), len(st.Responses))
+		}),
+	}
+	return embed
+}
+
+func leaderboardField(name string, standings []*staffStanding, less func(a, b *staffStanding) bool, value func(*staffStanding) string) *discordgo.MessageEmbedField {
+	sorted := append([]*staffStanding(nil), standings...)
+	sort.SliceStable(sorted, func(a, b int) bool { return less(sorted[a], sorted[b]) })
+	var lines []string
+	for _, st := range sorted {
+		v := value(st)
+		if v == "" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%d. <@%s> · %s", len(lines)+1, st.UserID, v))
+		if len(lines) >= leaderboardSize {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "-")
+	}
+	return &discordgo.MessageEmbedField{Name: name, Value: strings.Join(lines, "\n"), Inline: false}
+}
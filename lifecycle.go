@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultDrainTimeout = 25 * time.Second
+	drainTokenHeader    = "X-Drain-Token"
+)
+
+var (
+	draining             atomic.Bool
+	inFlightInteractions atomic.Int64
+	commandsRegistered   atomic.Bool
+	startedAt            = time.Now()
+)
+
+type healthReport struct {
+	Status        string          `json:"status"`
+	UptimeSeconds int64           `json:"uptime_seconds"`
+	Draining      bool            `json:"draining"`
+	Discord       dependencyState `json:"discord"`
+	MongoDB       dependencyState `json:"mongodb"`
+	OpenTickets   *int64          `json:"open_tickets,omitempty"`
+}
+
+type dependencyState struct {
+	OK        bool    `json:"ok"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	report := healthReport{Status: "ok", UptimeSeconds: int64(time.Since(startedAt).Seconds()), Draining: draining.Load()}
+	b := app()
+	if gatewayReady(b.Session) {
+		report.Discord = dependencyState{OK: true, LatencyMS: float64(b.Session.HeartbeatLatency().Microseconds()) / 1000}
+	} else {
+		report.Discord.Error = "gateway not connected"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	started := time.Now()
+	if b.Store == nil {
+		report.MongoDB.Error = "not connected"
+	} else if err := b.Store.Ping(ctx); err != nil {
+		report.MongoDB.Error = err.Error()
+	} else {
+		report.MongoDB = dependencyState{OK: true, LatencyMS: float64(time.Since(started).Microseconds()) / 1000}
+		if count, err := b.Tickets.CountDocuments(ctx, bson.M{"status": ticketStatusOpen}); err == nil {
+			report.OpenTickets = &count
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Discord.OK || !report.MongoDB.OK {
+		report.Status = "degraded"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+func handleLive(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, "ok")
+}
+
+func handleReady(w http.ResponseWriter, r *http.Request) {
+	if problem := readinessProblem(); problem != "" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintf(w, "not ready: %s", problem)
+		return
+	}
+	fmt.Fprintf(w, "ready")
+}
+
+func registerDrainHandler(mux *http.ServeMux) {
+	token := os.Getenv("DRAIN_TOKEN")
+	if token == "" {
+		log.Println("DRAIN_TOKEN is not set; /drain is disabled and drain mode starts only on SIGTERM.")
+		return
+	}
+	mux.HandleFunc("/drain", func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get(drainTokenHeader)), []byte(token)) != 1 {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		startDraining()
+		fmt.Fprintf(w, "draining")
+	})
+}
+
+func readinessProblem() string {
+	if draining.Load() {
+		return "draining"
+	}
+	b := app()
+	if !gatewayReady(b.Session) {
+		return "discord gateway not connected"
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if b.Store == nil {
+		return "database not connected"
+	}
+	if err := b.Store.Ping(ctx); err != nil {
+		return "database unreachable"
+	}
+	if !commandsRegistered.Load() {
+		return "slash commands not registered"
+	}
+	return ""
+}
+
+func gatewayReady(s *discordgo.Session) bool {
+	if s == nil {
+		return false
+	}
+	s.RLock()
+	defer s.RUnlock()
+	return s.DataReady
+}
+
+func startDraining() {
+	if draining.CompareAndSwap(false, true) {
+		log.Println("Entering drain mode: new ticket interactions will be rejected.")
+	}
+}
+
+func waitForInFlight() {
+	timeout := defaultDrainTimeout
+	if v := os.Getenv("SHUTDOWN_DRAIN_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			timeout = d
+		} else {
+			log.Printf("Invalid SHUTDOWN_DRAIN_TIMEOUT '%s': %v", v, err)
+		}
+	}
+	deadline := time.Now().Add(timeout)
+	for inFlightInteractions.Load() > 0 {
+		if time.Now().After(deadline) {
+			log.Printf("Drain timeout reached with %d interactions still in flight.", inFlightInteractions.Load())
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	log.Println("All in-flight interactions finished.")
+}
+
+func respondDraining(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "점검 중", Description: "봇이 업데이트를 위해 재시작하고 있습니다. 잠시 후 다시 시도해주세요.", Color: colorYellow}}}})
+}
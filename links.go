@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const shareToLinkedCommandName = "연결 티켓으로 공유"
+
+type ticketLink struct {
+	ChannelID string   `bson:"_id"`
+	Linked    []string `bson:"linked"`
+}
+
+func linkedTicketChannels(channelID string) ([]string, error) {
+	var link ticketLink
+	err := app().Links.FindOne(context.TODO(), bson.M{"_id": channelID}).Decode(&link)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return link.Linked, nil
+}
+
+func handleLinkTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	source := requireTicket(s, i)
+	if source == nil {
+		return
+	}
+	targetID := i.ApplicationCommandData().Options[0].ChannelValue(nil).ID
+	target, err := findTicket(targetID)
+	if err == mongo.ErrNoDocuments || targetID == source.ChannelID {
+		respondError(s, i, errInvalidLinkTarget, nil)
+		return
+	}
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	opts := options.Update().SetUpsert(true)
+	_, err = app().Links.UpdateOne(context.TODO(), bson.M{"_id": source.ChannelID}, bson.M{"$addToSet": bson.M{"linked": target.ChannelID}}, opts)
+	if err == nil {
+		_, err = app().Links.UpdateOne(context.TODO(), bson.M{"_id": target.ChannelID}, bson.M{"$addToSet": bson.M{"linked": source.ChannelID}}, opts)
+	}
+	if err != nil {
+		respondError(s, i, errLinkSaveFailed, err)
+		return
+	}
+	s.ChannelMessageSendEmbed(target.ChannelID, &discordgo.MessageEmbed{Title: "티켓 연결", Description: fmt.Sprintf("<@%s> 님이 이 티켓을 <#%s> 티켓과 연결했습니다.", i.Member.User.ID, source.ChannelID), Color: colorBlue})
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "티켓 연결", Description: fmt.Sprintf("이 티켓을 <#%s> 티켓과 연결했습니다.\n메시지 메뉴의 '%s'로 메시지를 공유할 수 있습니다.", target.ChannelID, shareToLinkedCommandName), Color: colorGreen}}}})
+}
+
+func handleUnlinkTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	target := i.ApplicationCommandData().Options[0].ChannelValue(s)
+	if target == nil {
+		respondError(s, i, errInvalidLinkTarget, nil)
+		return
+	}
+	_, err := app().Links.UpdateOne(context.TODO(), bson.M{"_id": i.ChannelID}, bson.M{"$pull": bson.M{"linked": target.ID}})
+	if err == nil {
+		_, err = app().Links.UpdateOne(context.TODO(), bson.M{"_id": target.ID}, bson.M{"$pull": bson.M{"linked": i.ChannelID}})
+	}
+	if err != nil {
+		respondError(s, i, errLinkSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "티켓 연결 해제", Description: fmt.Sprintf("<#%s> 티켓과의 연결을 해제했습니다.", target.ID), Color: colorYellow}}}})
+}
+
+func handleShareToLinked(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	msg := data.Resolved.Messages[data.TargetID]
+	linked, err := linkedTicketChannels(i.ChannelID)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	if len(linked) == 0 {
+		respondError(s, i, errNoLinkedTickets, nil)
+		return
+	}
+	relay := relayEmbed(msg, i.ChannelID, i.Member.User)
+	var shared []string
+	for _, channelID := range linked {
+		if _, err := s.ChannelMessageSendEmbed(channelID, relay); err != nil {
+			log.Printf("Could not relay message %s to linked ticket %s: %v", msg.ID, channelID, err)
+			continue
+		}
+		shared = append(shared, fmt.Sprintf("<#%s>", channelID))
+	}
+	if len(shared) == 0 {
+		respondError(s, i, errRelayFailed, nil)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "공유 완료", Description: fmt.Sprintf("메시지를 %s 티켓에 공유했습니다.", strings.Join(shared, ", ")), Color: colorGreen}}}})
+}
+
+func relayEmbed(msg *discordgo.Message, sourceChannelID string, sharedBy *discordgo.User) *discordgo.MessageEmbed {
+	var description strings.Builder
+	description.WriteString(msg.Content)
+	for _, attachment := range msg.Attachments {
+		description.WriteString(fmt.Sprintf("\n📎 [%s](%s)", attachment.Filename, attachment.URL))
+	}
+	for _, embed := range msg.Embeds {
+		if embed.Title != "" {
+			description.WriteString("\n> **" + embed.Title + "**")
+		}
+		if embed.Description != "" {
+			description.WriteString("\n> " + strings.ReplaceAll(embed.Description, "\n", "\n> "))
+		}
+	}
+	return &discordgo.MessageEmbed{
+		Author:      &discordgo.MessageEmbedAuthor{Name: msg.Author.Username, IconURL: msg.Author.AvatarURL("")},
+		Description: description.String(),
+		Color:       colorGray,
+		Fields:      []*discordgo.MessageEmbedField{{Name: "원본", Value: fmt.Sprintf("<#%s> · [메시지로 이동](https://discord.com/channels/%s/%s/%s)", sourceChannelID, app().GuildID, sourceChannelID, msg.ID), Inline: false}},
+		Footer:      &discordgo.MessageEmbedFooter{Text: "연결 티켓에서 공유됨 · 공유자 " + sharedBy.Username},
+		Timestamp:   msg.Timestamp.In(kstLocation).Format(time.RFC3339),
+	}
+}
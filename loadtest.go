@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	loadTestMaxTickets  = 50
+	loadTestFakeReplies = 5
+	loadTestTokenPrefix = "loadtest-"
+)
+
+var loadTestPhases = []string{"생성", "대화", "담당자 배정", "닫기", "대화록"}
+
+var loadTestConversation = []string{
+	"안녕하세요, 민원 접수 테스트입니다.",
+	"담당자입니다. 어떤 도움이 필요하신가요?",
+	"관련 서류를 어디에 제출해야 하는지 궁금합니다.",
+	"민원실 창구 또는 온라인으로 제출하실 수 있습니다.",
+	"확인했습니다. 감사합니다.",
+}
+
+func loadTestCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "부하테스트",
+		Description:              "연습용 카테고리에 합성 티켓을 만들어 생성·배정·종료·대화록 처리 시간을 측정합니다. (서버 소유자 전용)",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "count", Description: "생성할 합성 티켓 수", Required: true},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "합성 티켓을 만들 민원 창구 (기본: 첫 번째 창구)", Required: false, Choices: ticketTopicChoices()},
+		},
+	}
+}
+
+func isLoadTestInteraction(i *discordgo.InteractionCreate) bool {
+	return strings.HasPrefix(i.Token, loadTestTokenPrefix)
+}
+
+type loadTestTransport struct {
+	base      http.RoundTripper
+	mu        sync.Mutex
+	responses map[string][]byte
+}
+
+func (t *loadTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idx := strings.Index(req.URL.Path, loadTestTokenPrefix)
+	if idx < 0 {
+		return t.base.RoundTrip(req)
+	}
+	token, _, _ := strings.Cut(req.URL.Path[idx:], "/")
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		req.Body.Close()
+		t.mu.Lock()
+		t.responses[token] = body
+		t.mu.Unlock()
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Type": {"application/json"}}, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+}
+
+func (t *loadTestTransport) lastError(token string) string {
+	t.mu.Lock()
+	body := t.responses[token]
+	t.mu.Unlock()
+	var resp discordgo.InteractionResponse
+	if json.Unmarshal(body, &resp) != nil || resp.Data == nil || len(resp.Data.Embeds) == 0 {
+		return "응답 없음"
+	}
+	return strings.TrimSpace(resp.Data.Embeds[0].Title + " " + resp.Data.Embeds[0].Description)
+}
+
+func loadTestSession(s *discordgo.Session) (*discordgo.Session, *loadTestTransport, error) {
+	base := s.Client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	transport := &loadTestTransport{base: base, responses: make(map[string][]byte)}
+	probe, err := discordgo.New(s.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+	probe.State = s.State
+	probe.Ratelimiter = s.Ratelimiter
+	probe.MaxRestRetries = s.MaxRestRetries
+	probe.UserAgent = s.UserAgent
+	probe.Client = &http.Client{Timeout: s.Client.Timeout, Transport: transport}
+	return probe, transport, nil
+}
+
+func loadTestInteraction(s *discordgo.Session, kind discordgo.InteractionType, data discordgo.InteractionData, guildID, channelID string, user *discordgo.User, roleID string, n int, step string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		ID:        strconv.FormatInt(time.Now().UnixNano(), 10),
+		AppID:     s.State.User.ID,
+		Type:      kind,
+		Data:      data,
+		GuildID:   guildID,
+		ChannelID: channelID,
+		Member:    &discordgo.Member{GuildID: guildID, User: user, Roles: []string{roleID}},
+		Token:     fmt.Sprintf("%s%d-%s-%d", loadTestTokenPrefix, n, step, time.Now().UnixNano()),
+		Version:   1,
+	}}
+}
+
+func handleLoadTest(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	cfg := getConfig()
+	if cfg.Sandbox.CategoryID == "" {
+		respondError(s, i, errLoadTestCategoryUnset, nil)
+		return
+	}
+	count := int(options["count"].IntValue())
+	if count < 1 || count > loadTestMaxTickets {
+		respondError(s, i, errLoadTestCount, nil, loadTestMaxTickets)
+		return
+	}
+	topic := ticketOptions()[0].Value
+	if opt, ok := options["topic"]; ok {
+		topic = opt.StringValue()
+	}
+	supportRoleID, ok := cfg.CategorySupportRoles[topic]
+	if !ok {
+		supportRoleID = cfg.DefaultSupportRoleID
+	}
+	probe, transport, err := loadTestSession(s)
+	if err != nil {
+		respondError(s, i, errTicketSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}})
+
+	started := time.Now()
+	durations := make(map[string][]time.Duration)
+	var failures []string
+	for n := 1; n <= count; n++ {
+		result, err := runSyntheticTicket(probe, transport, i, topic, supportRoleID, n)
+		if err != nil {
+			log.Printf("Load test ticket %d failed: %v", n, err)
+			failures = append(failures, fmt.Sprintf("%d번: %v", n, err))
+			continue
+		}
+		for phase, d := range result {
+			durations[phase] = append(durations[phase], d)
+		}
+	}
+
+	var fields []*discordgo.MessageEmbedField
+	for _, phase := range loadTestPhases {
+		samples := durations[phase]
+		if len(samples) == 0 {
+			continue
+		}
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   phase,
+			Value:  fmt.Sprintf("p50 %s\np95 %s\n최대 %s", formatLatency(percentileDuration(samples, 0.5)), formatLatency(percentileDuration(samples, 0.95)), formatLatency(percentileDuration(samples, 1))),
+			Inline: true,
+		})
+	}
+	color := colorGreen
+	description := fmt.Sprintf("%s 창구 합성 티켓 %d개 중 %d개 성공, %d개 실패\n총 소요 시간: %s", topic, count, count-len(failures), len(failures), formatLatency(time.Since(started)))
+	if len(failures) > 0 {
+		color = colorYellow
+		if len(failures) > 5 {
+			failures = append(failures[:5], "…")
+		}
+		description += "\n\n" + strings.Join(failures, "\n")
+	}
+	embeds := []*discordgo.MessageEmbed{{
+		Title:       "부하 테스트 결과",
+		Description: description,
+		Color:       color,
+		Fields:      fields,
+		Timestamp:   time.Now().In(kstLocation).Format(time.RFC3339),
+	}}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+}
+
+func runSyntheticTicket(s *discordgo.Session, transport *loadTestTransport, invoker *discordgo.InteractionCreate, topic, supportRoleID string, n int) (map[string]time.Duration, error) {
+	result := make(map[string]time.Duration)
+	requester := s.State.User
+	answers := []intakeAnswer{
+		{ID: "subject", Label: "제목", Value: fmt.Sprintf("부하 테스트 %d", n)},
+		{ID: "content", Label: "민원 내용", Value: "부하 테스트용 합성 민원입니다."},
+	}
+
+	start := time.Now()
+	create := loadTestInteraction(s, discordgo.InteractionModalSubmit, discordgo.ModalSubmitInteractionData{CustomID: ticketModalPrefix + topic}, invoker.GuildID, invoker.ChannelID, requester, supportRoleID, n, "create")
+	createTicketChannel(s, create, requester.ID, topic, answers)
+	var t ticket
+	filter := bson.M{"owner_id": requester.ID, "category": topic, "sandbox": true, "created_at": bson.M{"$gte": start}}
+	if err := app().Tickets.FindOne(context.TODO(), filter, options.FindOne().SetSort(bson.M{"created_at": -1})).Decode(&t); err != nil {
+		return nil, fmt.Errorf("티켓 생성 실패 (%s)", transport.lastError(create.Token))
+	}
+	result["생성"] = time.Since(start)
+	defer cleanupSyntheticTicket(s, &t)
+
+	start = time.Now()
+	for r := 0; r < loadTestFakeReplies; r++ {
+		if _, err := s.ChannelMessageSend(t.ChannelID, loadTestConversation[r%len(loadTestConversation)]); err != nil {
+			return nil, fmt.Errorf("대화 전송 실패: %w", err)
+		}
+	}
+	result["대화"] = time.Since(start)
+
+	start = time.Now()
+	greeting, err := findTicketMessage(s, t.ChannelID)
+	if err != nil || greeting == nil {
+		return nil, fmt.Errorf("첫 메시지를 찾지 못함: %v", err)
+	}
+	claim := loadTestInteraction(s, discordgo.InteractionMessageComponent, discordgo.MessageComponentInteractionData{CustomID: ticketComponentID(actionClaim, &t), ComponentType: discordgo.ButtonComponent}, invoker.GuildID, t.ChannelID, invoker.Member.User, supportRoleID, n, "claim")
+	claim.Message = greeting
+	handleClaimTicket(s, claim)
+	claimed, err := findTicket(t.ChannelID)
+	if err != nil || claimed.AssigneeID != invoker.Member.User.ID {
+		return nil, fmt.Errorf("담당자 배정 실패 (%s)", transport.lastError(claim.Token))
+	}
+	result["담당자 배정"] = time.Since(start)
+
+	start = time.Now()
+	closeTicketChannel(s, claimed, invoker.Member.User.ID, false)
+	result["닫기"] = time.Since(start)
+
+	start = time.Now()
+	ch, err := s.Channel(t.ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("닫힌 채널을 찾지 못함: %w", err)
+	}
+	createAndSendLog(s, ch)
+	result["대화록"] = time.Since(start)
+	return result, nil
+}
+
+func cleanupSyntheticTicket(s *discordgo.Session, t *ticket) {
+	if _, err := s.ChannelDelete(t.ChannelID); err != nil {
+		log.Printf("Could not delete load test channel '%s': %v", t.Name(), err)
+	}
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"status": ticketStatusDeleted}}); err != nil {
+		log.Printf("Could not mark load test ticket '%s' deleted: %v", t.Name(), err)
+	}
+}
+
+func averageDuration(samples []time.Duration) time.Duration {
+	var total time.Duration
+	for _, d := range samples {
+		total += d
+	}
+	return total / time.Duration(len(samples))
+}
+
+func percentileDuration(samples []time.Duration, p float64) time.Duration {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+func formatLatency(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	liftLockdownID          = "lift_intake_lockdown"
+	lockdownCheckInterval   = time.Minute
+	raidWindow              = time.Minute
+	defaultLockdownDuration = 15 * time.Minute
+	maxPanelMessages        = 10
+)
+
+type raidThrottle struct {
+	PerMinute   int           `bson:"per_minute,omitempty"`
+	LockdownFor time.Duration `bson:"lockdown_for,omitempty"`
+}
+
+type intakeLockdown struct {
+	Until     time.Time `bson:"until,omitempty"`
+	Reason    string    `bson:"reason,omitempty"`
+	StartedBy string    `bson:"started_by,omitempty"`
+}
+
+type panelMessage struct {
+	ChannelID string `bson:"channel_id"`
+	MessageID string `bson:"message_id"`
+}
+
+var (
+	raidMu      sync.Mutex
+	raidHistory []time.Time
+)
+
+func (r raidThrottle) duration() time.Duration {
+	if r.LockdownFor <= 0 {
+		return defaultLockdownDuration
+	}
+	return r.LockdownFor
+}
+
+func (r raidThrottle) label() string {
+	if r.PerMinute <= 0 {
+		return "사용 안 함"
+	}
+	return fmt.Sprintf("서버 전체 분당 %d개 초과 시 %s 잠금", r.PerMinute, formatSLADuration(r.duration()))
+}
+
+func (l intakeLockdown) active() bool {
+	return time.Now().Before(l.Until)
+}
+
+func lockdownLabel(l intakeLockdown) string {
+	if !l.active() {
+		return "접수 중"
+	}
+	return fmt.Sprintf("잠금 (<t:%d:R> 해제) · %s", l.Until.Unix(), l.Reason)
+}
+
+func panelContent(locked bool) ([]*discordgo.MessageEmbed, []discordgo.MessageComponent) {
+	embed := &discordgo.MessageEmbed{Title: institutionName() + " 민원창구", Description: "아래 메뉴에서 원하시는 민원 창구를 선택하여 티켓을 생성해주세요.", Color: colorBlue}
+	placeholder := "문의할 창구를 선택해주세요."
+	if locked {
+		embed.Description = "민원 접수가 일시 중단되었습니다. 잠시 후 다시 이용해주세요."
+		embed.Color = colorGray
+		placeholder = "접수 일시 중단"
+	}
+	return []*discordgo.MessageEmbed{embed}, []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.SelectMenu{CustomID: "ticket_topic_select", Placeholder: placeholder, Options: ticketOptions(), Disabled: locked}}}}
+}
+
+func rememberPanelMessage(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	msg, err := s.InteractionResponse(i.Interaction)
+	if err != nil {
+		log.Printf("Could not look up sent panel message: %v", err)
+		return
+	}
+	err = updateConfig(func(cfg *guildConfig) {
+		panels := []panelMessage{{ChannelID: msg.ChannelID, MessageID: msg.ID}}
+		for _, p := range cfg.PanelMessages {
+			if p.ChannelID != msg.ChannelID && len(panels) < maxPanelMessages {
+				panels = append(panels, p)
+			}
+		}
+		cfg.PanelMessages = panels
+	})
+	if err != nil {
+		log.Printf("Could not remember panel message: %v", err)
+	}
+}
+
+func refreshPanels(s *discordgo.Session, locked bool) {
+	embeds, components := panelContent(locked)
+	for _, p := range getConfig().PanelMessages {
+		_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{ID: p.MessageID, Channel: p.ChannelID, Embeds: &embeds, Components: &components})
+		if err != nil {
+			log.Printf("Could not update panel message %s in %s: %v", p.MessageID, p.ChannelID, err)
+		}
+	}
+}
+
+func admitTicketCreation(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	cfg := getConfig()
+	if hasSupportRole(i.Member) {
+		return true
+	}
+	if cfg.Lockdown.active() {
+		respondError(s, i, errIntakeLockdown, nil, cfg.Lockdown.Until.Unix())
+		return false
+	}
+	limit := cfg.RaidThrottle.PerMinute
+	if limit <= 0 {
+		return true
+	}
+	now := time.Now()
+	raidMu.Lock()
+	recent := raidHistory[:0]
+	for _, at := range raidHistory {
+		if now.Sub(at) < raidWindow {
+			recent = append(recent, at)
+		}
+	}
+	raidHistory = recent
+	if len(raidHistory) < limit {
+		raidHistory = append(raidHistory, now)
+		raidMu.Unlock()
+		return true
+	}
+	if l := getConfig().Lockdown; l.active() {
+		raidMu.Unlock()
+		respondError(s, i, errIntakeLockdown, nil, l.Until.Unix())
+		return false
+	}
+	until := now.Add(cfg.RaidThrottle.duration())
+	reason := fmt.Sprintf("1분 동안 티켓 생성 %d건 초과", limit)
+	saveLockdown(until, reason, "")
+	raidMu.Unlock()
+	announceLockdown(s, until, reason, "")
+	respondError(s, i, errIntakeLockdown, nil, until.Unix())
+	return false
+}
+
+func saveLockdown(until time.Time, reason, userID string) {
+	if err := updateConfig(func(cfg *guildConfig) {
+		cfg.Lockdown = intakeLockdown{Until: until, Reason: reason, StartedBy: userID}
+	}); err != nil {
+		log.Printf("Could not save intake lockdown: %v", err)
+	}
+}
+
+func announceLockdown(s *discordgo.Session, until time.Time, reason, userID string) {
+	log.Printf("Ticket intake locked until %s: %s", until.Format(time.RFC3339), reason)
+	refreshPanels(s, true)
+	description := fmt.Sprintf("%s\n<t:%d:R>에 자동으로 접수가 재개됩니다. 그 전에 해제하려면 아래 버튼을 누르세요.", reason, until.Unix())
+	if userID != "" {
+		description = fmt.Sprintf("<@%s> 님이 접수를 잠갔습니다. %s", userID, description)
+	}
+	_, err := s.ChannelMessageSendComplex(getConfig().LogChannelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{{Title: "🚨 민원 접수 잠금", Description: description, Color: colorRed, Timestamp: time.Now().In(kstLocation).Format(time.RFC3339)}},
+		Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.Button{Label: "지금 해제", Style: discordgo.DangerButton, CustomID: liftLockdownID},
+		}}},
+	})
+	if err != nil {
+		log.Printf("Could not post lockdown alert: %v", err)
+	}
+}
+
+func liftLockdown(s *discordgo.Session, userID string) {
+	if err := updateConfig(func(cfg *guildConfig) { cfg.Lockdown = intakeLockdown{} }); err != nil {
+		log.Printf("Could not clear intake lockdown: %v", err)
+		return
+	}
+	raidMu.Lock()
+	raidHistory = nil
+	raidMu.Unlock()
+	refreshPanels(s, false)
+	description := "잠금 시간이 지나 민원 접수를 재개했습니다."
+	if userID != "" {
+		description = fmt.Sprintf("<@%s> 님이 민원 접수를 재개했습니다.", userID)
+	}
+	s.ChannelMessageSendEmbed(getConfig().LogChannelID, &discordgo.MessageEmbed{Title: "민원 접수 재개", Description: description, Color: colorGreen})
+	log.Println("Ticket intake lockdown lifted.")
+}
+
+func liftExpiredLockdown(s *discordgo.Session) error {
+	l := getConfig().Lockdown
+	if l.Until.IsZero() || l.active() {
+		return nil
+	}
+	liftLockdown(s, "")
+	return nil
+}
+
+func handleLiftLockdown(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{Components: []discordgo.MessageComponent{}}})
+	if getConfig().Lockdown.active() {
+		liftLockdown(s, i.Member.User.ID)
+	}
+}
+
+func lockdownCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	oneValue := 1.0
+	return &discordgo.ApplicationCommand{
+		Name:                     "접수잠금",
+		Description:              "민원 접수를 일시 중단하거나 재개합니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "잠금 여부", Required: true},
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "minutes", Description: "잠금 시간(분), 기본: 자동 잠금 시간", Required: false, MinValue: &oneValue},
+		},
+	}
+}
+
+func handleLockdownCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}})
+	var embed *discordgo.MessageEmbed
+	if options["enabled"].BoolValue() {
+		duration := getConfig().RaidThrottle.duration()
+		if opt, ok := options["minutes"]; ok {
+			duration = time.Duration(opt.IntValue()) * time.Minute
+		}
+		until := time.Now().Add(duration)
+		saveLockdown(until, "관리자 수동 잠금", i.Member.User.ID)
+		announceLockdown(s, until, "관리자 수동 잠금", i.Member.User.ID)
+		embed = &discordgo.MessageEmbed{Title: "민원 접수 잠금", Description: fmt.Sprintf("<t:%d:R>까지 새 민원 접수를 중단했습니다.", until.Unix()), Color: colorYellow}
+	} else {
+		liftLockdown(s, i.Member.User.ID)
+		embed = &discordgo.MessageEmbed{Title: "민원 접수 재개", Description: "패널의 창구 선택 메뉴를 다시 활성화했습니다.", Color: colorGreen}
+	}
+	embeds := []*discordgo.MessageEmbed{embed}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func logResendCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "로그재전송",
+		Description:              "보관된 대화록과 기록으로 티켓 로그를 로그 채널에 다시 보냅니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "code", Description: "접수번호", Required: true},
+		},
+	}
+}
+
+func handleLogResend(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	code := i.ApplicationCommandData().Options[0].StringValue()
+	var t *ticket
+	if id, ok := parseTicketCode(code); ok {
+		t, _ = findTicketByID(id)
+	}
+	if t == nil || t.Status == ticketStatusOpen {
+		respondError(s, i, errLogRecordMissing, nil, code)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}})
+
+	features := featuresFor(t.Category)
+	var files []*discordgo.File
+	transcriptNote := ""
+	if features.Transcripts {
+		content, err := loadTranscriptHTML(t.ChannelID)
+		switch {
+		case errors.Is(err, errTranscriptSealed):
+		case err != nil:
+			transcriptNote = "보관된 대화록을 찾지 못해 기록만 다시 보냈습니다."
+			log.Printf("Could not load transcript of '%s' for log resend: %v", t.Name(), err)
+		default:
+			file, err := writeTempTranscript(content)
+			if err != nil {
+				log.Printf("Error writing transcript file for log resend: %v", err)
+				break
+			}
+			defer removeTempFile(file)
+			files = append(files, &discordgo.File{Name: fmt.Sprintf("transcript-%s.html", t.Name()), ContentType: "text/html", Reader: file})
+		}
+	}
+	var messages []*discordgo.Message
+	if app().Messages != nil {
+		var err error
+		if messages, err = archivedLiveMessages(t.ChannelID); err != nil {
+			log.Printf("Could not load archived messages of '%s' for log resend: %v", t.Name(), err)
+		}
+	}
+	embed := ticketLogEmbed(s, t.Name(), t.Category, t.OwnerID, t, messages)
+	embed.Description = fmt.Sprintf("🔁 <@%s> 님이 다시 보낸 로그입니다.", i.Member.User.ID)
+	if transcriptNote != "" {
+		embed.Description += "\n" + transcriptNote
+	}
+	if !t.ClosedAt.IsZero() {
+		embed.Timestamp = t.ClosedAt.In(kstLocation).Format(time.RFC3339)
+	}
+	sent, err := s.ChannelMessageSendComplex(getConfig().LogChannelID, &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{embed}, Files: files})
+	if err != nil {
+		log.Printf("Error resending ticket log for '%s': %v", t.Name(), err)
+		embeds := []*discordgo.MessageEmbed{{Title: "로그 재전송", Description: fmt.Sprintf("로그 채널에 보내지 못했습니다: %v", err), Color: colorRed}}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+		return
+	}
+	link := fmt.Sprintf("https://discord.com/channels/%s/%s/%s", app().GuildID, sent.ChannelID, sent.ID)
+	if features.Transcripts {
+		if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"transcript_link": link}}); err != nil {
+			log.Printf("Could not record transcript link for '%s': %v", t.Name(), err)
+		}
+	}
+	log.Printf("%s resent the log of ticket '%s'.", i.Member.User.Username, t.Name())
+	embeds := []*discordgo.MessageEmbed{{Title: "로그 재전송", Description: fmt.Sprintf("%s (%s) 로그를 [다시 보냈습니다](%s).", t.Name(), t.Code, link), Color: colorGreen}}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+}
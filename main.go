@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path"
+	"runtime/pprof"
 	"sort"
 	"strings"
 	"syscall"
@@ -22,36 +24,15 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-var (
-	dg               *discordgo.Session
-	err              error
-	mongoClient      *mongo.Client
-	ticketCollection *mongo.Collection
-	guildID          = "1274752368063414292" // 길드 ID 적용
-
-	kstLocation *time.Location
-
-	categorySupportRoles = map[string]string{
-		"일반민원": "1397231132579467294",
-		"법률구조": "1397231132579467294",
-		"부패신고": "1397981755847217325",
-	}
-	defaultSupportRoleID = "1397231132579467294"
-)
-
 const (
 	colorBlue   = 0x0099ff
 	colorGreen  = 0x28a745
 	colorRed    = 0xdc3545
 	colorYellow = 0xffc107
 	colorGray   = 0x95a5a6
-
-	openTicketsCategoryID   = "1398719413016072306"
-	closedTicketsCategoryID = "1398719595384406137"
-	logChannelID            = "1397260754482237652"
 )
 
-var ticketOptions = []discordgo.SelectMenuOption{
+var defaultTicketOptions = []discordgo.SelectMenuOption{
 	{Label: "일반민원", Value: "일반민원", Description: "행정민원, 파산신고, 사업신청은 해당 창구로 문의 바랍니다.", Emoji: &discordgo.ComponentEmoji{Name: "📄"}},
 	{Label: "법률구조", Value: "법률구조", Description: "법률상담은 해당 창구로 문의 바랍니다.", Emoji: &discordgo.ComponentEmoji{Name: "⚖️"}},
 	{Label: "부패신고", Value: "부패신고", Description: "공익신고, 금융신고는 해당 창구로 문의 바랍니다.", Emoji: &discordgo.ComponentEmoji{Name: "🗑️"}},
@@ -63,67 +44,121 @@ type counter struct {
 }
 
 func runHealthCheckServer() {
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "Bot is running!")
-	})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handleHealth)
+	mux.HandleFunc("/healthz", handleLive)
+	mux.HandleFunc("/readyz", handleReady)
+	mux.HandleFunc("/metrics", handleMetrics)
+	registerDrainHandler(mux)
+	registerDebugHandlers(mux)
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8000"
 	}
 	log.Printf("Health check server starting on port %s", port)
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
+	if err := http.ListenAndServe(":"+port, mux); err != nil {
 		log.Fatalf("Failed to start health check server: %v", err)
 	}
 }
 
 func main() {
-	err = godotenv.Load()
-	if err != nil {
+	if err := godotenv.Load(); err != nil {
 		log.Println("Warning: Could not load .env file. Using environment variables from host.")
 	}
-	kstLocation, err = time.LoadLocation("Asia/Seoul")
-	if err != nil {
-		log.Fatalf("Could not load KST location: %v", err)
+
+	if id := os.Getenv("GUILD_ID"); id != "" {
+		updateBot(func(b *bot) { b.GuildID = id })
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "transcript" {
+		runTranscriptCLI(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		runExportCLI(os.Args[2:])
+		return
+	}
+
+	sweepOrphanedTranscripts()
+	startErrorReporting()
+	startEventPublisher()
 	go runHealthCheckServer()
 
-	mongoURI := os.Getenv("MONGO_URI")
-	dbName := os.Getenv("MONGO_DATABASE")
-	collectionName := os.Getenv("MONGO_COLLECTION")
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-	mongoClient, err = mongo.Connect(ctx, options.Client().ApplyURI(mongoURI))
-	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB with URI '%s': %v", mongoURI, err)
+	if err := connectStore(ctx); err != nil {
+		log.Fatalf("%v", err)
 	}
-	defer mongoClient.Disconnect(ctx)
-	err = mongoClient.Ping(ctx, nil)
-	if err != nil {
-		log.Fatalf("Failed to ping MongoDB: %v", err)
+	defer disconnectStore(ctx)
+	if err := migrateLegacyTicketIDs(context.TODO()); err != nil {
+		log.Fatalf("Failed to migrate ticket IDs: %v", err)
+	}
+	if err := ensureTicketIndexes(ctx); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if err := ensureTranscriptIndexes(ctx); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if err := ensureMessageArchiveIndexes(ctx); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	if err := loadGuildConfig(app().GuildID); err != nil {
+		log.Fatalf("Failed to load guild configuration: %v", err)
 	}
-	log.Println("Successfully connected to MongoDB!")
-	ticketCollection = mongoClient.Database(dbName).Collection(collectionName)
 	token := os.Getenv("BOT_TOKEN")
-	dg, err = discordgo.New("Bot " + token)
+	session, err := discordgo.New("Bot " + token)
 	if err != nil {
 		log.Fatalf("Error creating Discord session: %v", err)
 	}
+	instrumentInteractionLatency(session)
 
-	dg.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsGuildMembers | discordgo.IntentsMessageContent
+	session.Identify.Intents = discordgo.IntentsGuilds | discordgo.IntentsGuildMessages | discordgo.IntentsGuildMessageReactions | discordgo.IntentsGuildMembers | discordgo.IntentsMessageContent | discordgo.IntentsGuildPresences | discordgo.IntentsGuildVoiceStates
 
-	dg.AddHandler(ready)
-	dg.AddHandler(interactionCreate)
-	err = dg.Open()
-	if err != nil {
+	registerInteractionRoutes()
+	session.AddHandler(ready)
+	session.AddHandler(interactionCreate)
+	session.AddHandler(messageCreate)
+	session.AddHandler(archiveMessageCreate)
+	session.AddHandler(archiveMessageUpdate)
+	session.AddHandler(archiveMessageDelete)
+	session.AddHandler(archiveMessageDeleteBulk)
+	session.AddHandler(archiveReactionAdd)
+	session.AddHandler(archiveReactionRemove)
+	session.AddHandler(archiveReactionRemoveAll)
+	session.AddHandler(voiceStateUpdate)
+	session.AddHandler(guildRoleDelete)
+	session.AddHandler(guildRoleUpdate)
+	updateBot(func(b *bot) { b.Session = session })
+	if err := session.Open(); err != nil {
 		log.Fatalf("Error opening connection: %v", err)
 	}
-	defer dg.Close()
-	registerCommands()
+	defer session.Close()
+	registerCommands(session)
 	fmt.Println("Bot is now running. Press CTRL+C to exit.")
 	sc := make(chan os.Signal, 1)
 	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM, os.Interrupt)
 	<-sc
+	startDraining()
+	waitForInFlight()
+	flushNotifications(session)
+}
+
+func connectMongo(ctx context.Context) error {
+	mongoURI := os.Getenv("MONGO_URI")
+	dbName := os.Getenv("MONGO_DATABASE")
+	collectionName := os.Getenv("MONGO_COLLECTION")
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURI).SetMonitor(mongoMonitor()))
+	if err != nil {
+		return fmt.Errorf("failed to connect to MongoDB with URI '%s': %w", mongoURI, err)
+	}
+	if err := client.Ping(ctx, nil); err != nil {
+		return fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+	log.Println("Successfully connected to MongoDB!")
+	updateBot(func(b *bot) {
+		attachStore(b, mongoStore{client: client, database: client.Database(dbName)}, collectionName)
+	})
+	return nil
 }
 
 func getNextSequenceValue(sequenceName string) (uint64, error) {
@@ -131,73 +166,232 @@ func getNextSequenceValue(sequenceName string) (uint64, error) {
 	update := bson.M{"$inc": bson.M{"seq": 1}}
 	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
 	var result counter
-	err := ticketCollection.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
+	err := app().Counters.FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&result)
 	if err != nil {
 		return 0, fmt.Errorf("could not update sequence for '%s': %w", sequenceName, err)
 	}
 	return result.Seq, nil
 }
 
-func createTicketChannel(s *discordgo.Session, i *discordgo.InteractionCreate, topicValue, petitionerNickname, petitionContent string) {
-	nextSeq, err := getNextSequenceValue(topicValue)
+func createTicketChannel(s *discordgo.Session, i *discordgo.InteractionCreate, ownerID, topicValue string, answers []intakeAnswer) {
+	if !checkTicketBlock(s, i) || !checkOpenTicketLimit(s, i) || !admitTicketCreation(s, i) {
+		return
+	}
+	cooldown := cooldownApplies(i)
+	if cooldown {
+		if remaining, ok := claimTicketCooldown(i.Member.User.ID, getConfig().TicketCooldown); !ok {
+			respondTicketCooldown(s, i, remaining)
+			return
+		}
+	}
+	nextSeq, err := reserveTicketNumber(topicValue)
 	if err != nil {
-		log.Printf("Could not get next sequence for ticket: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "티켓 번호를 생성하는 데 실패했습니다. 관리자에게 문의하세요.", Color: colorRed}}, Flags: discordgo.MessageFlagsEphemeral}})
+		if cooldown {
+			clearTicketCooldown(i.Member.User.ID)
+		}
+		respondError(s, i, errSequenceFailed, err)
 		return
 	}
-	supportRoleID, ok := categorySupportRoles[topicValue]
+	issued := false
+	defer func() {
+		if !issued {
+			releaseTicketNumber(topicValue, nextSeq)
+			if cooldown {
+				clearTicketCooldown(i.Member.User.ID)
+			}
+		}
+	}()
+	cfg := getConfig()
+	supportRoleID, ok := cfg.CategorySupportRoles[topicValue]
 	if !ok {
 		log.Printf("Warning: No support role configured for category '%s'. Falling back to default.", topicValue)
-		supportRoleID = defaultSupportRoleID
+		supportRoleID = cfg.DefaultSupportRoleID
 	}
 	ticketNumber := fmt.Sprintf("%04d", nextSeq)
 	channelName := fmt.Sprintf("%s-%s", topicValue, ticketNumber)
-	ch, err := s.GuildChannelCreateComplex(i.GuildID, discordgo.GuildChannelCreateData{
-		Name:     channelName,
-		Type:     discordgo.ChannelTypeGuildText,
-		Topic:    fmt.Sprintf("User ID: %s | Ticket ID: %s-%s", i.Member.User.ID, topicValue, ticketNumber),
-		ParentID: openTicketsCategoryID,
-		PermissionOverwrites: []*discordgo.PermissionOverwrite{
-			{ID: i.GuildID, Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionViewChannel},
-			{ID: i.Member.User.ID, Type: discordgo.PermissionOverwriteTypeMember, Allow: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages},
-			{ID: supportRoleID, Type: discordgo.PermissionOverwriteTypeRole, Allow: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages},
-		},
-	})
-	if err != nil {
-		log.Printf("Error creating ticket channel: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: fmt.Sprintf("채널 생성에 실패했습니다: %v", err), Color: colorRed}}}})
+	greeting := ticketGreeting(ownerID)
+	anonymous := featuresFor(topicValue).Anonymous
+	if anonymous {
+		greeting = "안녕하세요! 문의주셔서 감사합니다.\n이 민원은 익명으로 처리되며, 곧 담당자가 도착할 예정입니다."
+	}
+	t := &ticket{
+		GuildID:          i.GuildID,
+		Category:         topicValue,
+		Number:           nextSeq,
+		OwnerID:          ownerID,
+		Nickname:         intakeValue(answers, "nickname"),
+		Subject:          intakeValue(answers, "subject"),
+		Content:          intakeValue(answers, "content"),
+		Intake:           answers,
+		RequesterUrgency: normalizeUrgency(intakeValue(answers, urgencyQuestionID)),
+		Status:           ticketStatusOpen,
+		LiveArchive:      app().Messages != nil,
+		CreatedAt:        time.Now(),
+	}
+	t.AwaitingReplySince = t.CreatedAt
+	if err := assignTicketID(t); err != nil {
+		respondError(s, i, errTicketSaveFailed, err)
 		return
 	}
-	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "티켓 채널 생성 완료", Description: fmt.Sprintf("성공적으로 <#%s> 채널을 생성했습니다.", ch.ID), Color: colorGreen}}, Flags: discordgo.MessageFlagsEphemeral}})
+	if ownerID != i.Member.User.ID {
+		t.OpenedByID = i.Member.User.ID
+	}
+	requirement, needsFiles := cfg.CategoryAttachments[topicValue]
+	t.AttachmentsPending = needsFiles
+	t.startSLATimers()
+	fields := append(intakeFields(answers, anonymous), urgencyField(t))
+	if t.OpenedByID != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{Name: "개설자", Value: fmt.Sprintf("<@%s> 님이 민원인을 대신해 열었습니다.", t.OpenedByID), Inline: false})
+	}
+	assessment, quarantined := shouldQuarantine(i, answers)
+	parentID, staffRoleID := cfg.OpenCategoryID, supportRoleID
+	if quarantined {
+		t.Quarantined, t.SpamScore, t.SpamSignals = true, assessment.Score, assessment.Signals
+		staffRoleID = cfg.SpamPolicy.ModeratorRoleID
+		if cfg.SpamPolicy.ReviewCategoryID != "" {
+			parentID = cfg.SpamPolicy.ReviewCategoryID
+		}
+	}
+	if opensSandboxTicket(i, ownerID) {
+		t.Sandbox = true
+		parentID = cfg.Sandbox.CategoryID
+	}
+	specialists := findSpecialists(s, t, supportRoleID)
+	if featuresFor(topicValue).AutoAssign && !quarantined && !isLoadTestInteraction(i) {
+		if agentID := autoAssignAgent(s, t, supportRoleID, specialists); agentID != "" {
+			t.AssigneeID = agentID
+			t.ClaimedAt = t.CreatedAt
+			t.HandlingSince = t.CreatedAt
+			fields = append(fields, &discordgo.MessageEmbedField{Name: "담당자", Value: fmt.Sprintf("<@%s>", agentID), Inline: false})
+		}
+	}
+	if field := specialistField(specialists); field != nil {
+		fields = append(fields, field)
+	}
+	if field := slaField(t); field != nil {
+		fields = append(fields, field)
+	}
 	messageData := &discordgo.MessageSend{
-		Content: fmt.Sprintf("<@&%s>", supportRoleID),
 		Embeds: []*discordgo.MessageEmbed{{
 			Title:       fmt.Sprintf("%s (#%s)", topicValue, ticketNumber),
-			Description: fmt.Sprintf("안녕하세요, <@%s>님! 문의주셔서 감사합니다.\n곧 담당자가 도착할 예정입니다. 잠시만 기다려주십시오.", i.Member.User.ID),
+			Description: greeting,
 			Color:       colorBlue,
-			Fields: []*discordgo.MessageEmbedField{
-				{Name: "민원인 닉네임", Value: petitionerNickname, Inline: false},
-				{Name: "민원 내용", Value: petitionContent, Inline: false},
-			},
-			Timestamp: time.Now().In(kstLocation).Format(time.RFC3339),
+			Fields:      fields,
+			Footer:      &discordgo.MessageEmbedFooter{Text: "접수번호 " + t.Code},
+			Timestamp:   time.Now().In(kstLocation).Format(time.RFC3339),
 		}},
 		Components: []discordgo.MessageComponent{
 			discordgo.ActionsRow{
 				Components: []discordgo.MessageComponent{
-					discordgo.Button{Label: "티켓 닫기", Style: discordgo.DangerButton, CustomID: "close_ticket_request"},
-					discordgo.Button{Label: "담당자 배정", Style: discordgo.SuccessButton, CustomID: "claim_ticket"},
+					discordgo.Button{Label: "티켓 닫기", Style: discordgo.DangerButton, CustomID: ticketComponentID(actionCloseRequest, t)},
+					discordgo.Button{Label: "담당자 배정", Style: discordgo.SuccessButton, CustomID: ticketComponentID(actionClaim, t), Disabled: t.AssigneeID != ""},
 				},
 			},
 		},
 	}
-	s.ChannelMessageSendComplex(ch.ID, messageData)
+	if row := greetingButtonRow(topicValue); row != nil {
+		messageData.Components = append(messageData.Components, row)
+	}
+	features := featuresFor(topicValue)
+	forum := forumModeEnabled(cfg) && !t.Sandbox && !quarantined && !features.Anonymous && !features.Encrypted
+	topic := fmt.Sprintf("User ID: %s | Ticket ID: %s-%s", ownerID, topicValue, ticketNumber)
+	if t.Sandbox {
+		topic += sandboxTopicTag
+	}
+	var ch *discordgo.Channel
+	if forum {
+		ch, err = createForumTicket(s, cfg.ForumChannelID, channelName, topicValue, messageData)
+	} else {
+		ch, err = withRetry("channel create", func() (*discordgo.Channel, error) {
+			return s.GuildChannelCreateComplex(i.GuildID, discordgo.GuildChannelCreateData{
+				Name:     channelName,
+				Type:     discordgo.ChannelTypeGuildText,
+				Topic:    topic,
+				ParentID: parentID,
+				PermissionOverwrites: []*discordgo.PermissionOverwrite{
+					{ID: i.GuildID, Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionViewChannel},
+					{ID: ownerID, Type: discordgo.PermissionOverwriteTypeMember, Allow: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages},
+					{ID: staffRoleID, Type: discordgo.PermissionOverwriteTypeRole, Allow: discordgo.PermissionViewChannel | discordgo.PermissionSendMessages},
+				},
+			})
+		})
+	}
+	if err != nil {
+		respondError(s, i, errChannelCreateFailed, err)
+		return
+	}
+	t.ChannelID = ch.ID
+	t.Forum = forum
+	if err := insertTicket(t); err != nil {
+		s.ChannelDelete(ch.ID)
+		respondError(s, i, errTicketSaveFailed, err)
+		return
+	}
+	messageData.Content = supportPingContent(s, topicValue, supportRoleID)
+	if t.AssigneeID != "" {
+		messageData.Content = fmt.Sprintf("<@%s> 님이 자동으로 담당자로 배정되었습니다.", t.AssigneeID)
+	}
+	if t.AttachmentsPending {
+		messageData.Content = ""
+	}
+	if quarantined {
+		messageData.Content = ""
+	}
+	if t.OpenedByID != "" && !anonymous {
+		messageData.Content = strings.TrimSpace(fmt.Sprintf("<@%s> %s", ownerID, messageData.Content))
+	}
+	if forum {
+		s.ThreadMemberAdd(ch.ID, ownerID)
+		if messageData.Content != "" {
+			s.ChannelMessageSend(ch.ID, messageData.Content)
+		}
+	} else if _, err := withRetry("message send", func() (*discordgo.Message, error) { return s.ChannelMessageSendComplex(ch.ID, messageData) }); err != nil {
+		discardTicket(s, t)
+		respondError(s, i, errGreetingSendFailed, err)
+		return
+	}
+	issued = true
+	issueTicketNumber(topicValue, nextSeq)
+	if t.AssigneeID != "" {
+		log.Printf("Auto-assigned ticket '%s' to %s.", t.Name(), t.AssigneeID)
+	}
+	defer func() {
+		if lang := detectLanguage(intakeText(answers)); lang != "" {
+			applyTicketLanguage(s, t, lang)
+		}
+		evaluateRules(s, t, ruleEventCreated)
+		if t.AssigneeID != "" {
+			evaluateRules(s, t, ruleEventClaimed)
+		}
+	}()
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "티켓 채널 생성 완료", Description: fmt.Sprintf("성공적으로 <#%s> 채널을 생성했습니다.", ch.ID), Color: colorGreen}}, Flags: discordgo.MessageFlagsEphemeral}})
+	if quarantined {
+		postQuarantineReview(s, t, assessment)
+	} else if t.RequesterUrgency == urgentPriority {
+		alertUrgentTicket(t, fmt.Sprintf("%s 창구에 민원인이 긴급으로 표시한 티켓 %s이(가) 접수되었습니다.", topicValue, t.Name()))
+	}
+	if t.OpenedByID != "" && anonymous {
+		notifyUser(s, ownerID, &discordgo.MessageEmbed{Title: "민원 티켓 개설", Description: fmt.Sprintf("담당자가 %s 창구에 익명 민원 티켓을 열었습니다: <#%s>", topicValue, ch.ID), Color: colorBlue})
+	}
+	postPinnedInfo(s, t)
+	if t.AttachmentsPending {
+		postAttachmentPrompt(s, t, requirement)
+	}
 }
 
 func ready(s *discordgo.Session, event *discordgo.Ready) {
 	log.Printf("Logged in as: %v#%v", s.State.User.Username, s.State.User.Discriminator)
+	go reconcileTickets(s)
+	go backfillMessageArchive(s)
+	go checkConfiguredRoles(s)
+	changeStreamOnce.Do(func() { go watchTicketChanges(s) })
+	schedulerOnce.Do(func() {
+		registerBuiltinJobs(s)
+		go runScheduler()
+	})
 }
 
-func registerCommands() {
+func registerCommands(s *discordgo.Session) {
 	commands := []*discordgo.ApplicationCommand{
 		{Name: "패널", Description: "티켓 생성 패널을 현재 채널에 보냅니다."},
 		{Name: "닫기", Description: "현재 티켓 채널을 닫습니다."},
@@ -205,165 +399,293 @@ func registerCommands() {
 		{Name: "제거", Description: "티켓에서 사용자를 제거합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "제거할 사용자", Required: true}}},
 		{Name: "역할추가", Description: "티켓에 역할을 추가합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionRole, Name: "role", Description: "추가할 역할", Required: true}}},
 		{Name: "역할제거", Description: "티켓에서 역할을 제거합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionRole, Name: "role", Description: "제거할 역할", Required: true}}},
+		{Name: "역할참여자추가", Description: "역할의 현재 구성원을 개별 사용자로 티켓에 추가합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionRole, Name: "role", Description: "구성원을 가져올 역할", Required: true}}},
 		{Name: "담당자변경", Description: "티켓의 담당자를 변경합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "새로 지정할 담당자", Required: true}}},
-	}
+		{Name: "연결", Description: "현재 티켓을 다른 티켓과 연결합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "연결할 티켓 채널", Required: true}}},
+		{Name: "연결해제", Description: "다른 티켓과의 연결을 해제합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "연결을 해제할 티켓 채널", Required: true}}},
+		{Name: shareToLinkedCommandName, Type: discordgo.MessageApplicationCommand},
+		{Name: openForUserCommandName, Type: discordgo.UserApplicationCommand},
+		{Name: ticketHistoryCommandName, Type: discordgo.UserApplicationCommand},
+		settingsCommand(),
+		presetCommand(),
+		lockdownCommand(),
+		blockCommand(),
+		unblockCommand(),
+		topicRoleCommand(),
+		sealedAccessCommand(),
+		rulesCommand(),
+		skillsCommand(),
+		exportCommand(),
+		{Name: "번역", Description: "이 티켓의 번역 도우미를 켜거나 끕니다.", Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "사용 여부", Required: true},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "language", Description: "민원인 언어 (비우면 자동 감지 결과 사용)", Required: false, Choices: languageChoices()},
+		}},
+		{Name: "태그", Description: "티켓에 태그를 추가하거나 제거합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionString, Name: "tag", Description: "태그 (이미 있으면 제거됩니다)", Required: true}}},
+		{Name: "만족도", Description: "만족도 조사 결과를 창구별, 담당자별로 확인합니다.", Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "period", Description: "조회 기간 (기본: 최근 30일)", Required: false, Choices: reportPeriodChoices},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: false, Choices: ticketTopicChoices()},
+		}},
+		{Name: "응답시간", Description: "첫 응답 시간을 창구별, 담당자별로 확인합니다.", Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "period", Description: "조회 기간 (기본: 최근 30일)", Required: false, Choices: reportPeriodChoices},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: false, Choices: ticketTopicChoices()},
+		}},
+		statsCommand(),
+		ticketListCommand(),
+		searchCommand(),
+		transcriptSearchCommand(),
+		alertsCommand(),
+		sandboxCommand(),
+		logResendCommand(),
+		greetingButtonsCommand(),
+		reportCommand(),
+		counterAuditCommand(),
+		loadTestCommand(),
+		{Name: "대화록", Description: "현재 티켓의 대화록을 원하는 스타일로 만들어 받습니다.", Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "theme", Description: "테마 (기본: 서버 설정)", Required: false, Choices: transcriptThemeChoices},
+			{Type: discordgo.ApplicationCommandOptionBoolean, Name: "compact", Description: "같은 작성자의 연속 메시지를 묶어서 표시", Required: false},
+		}},
+		{Name: "음성상담", Description: "이 티켓에 연결된 음성 상담 채널을 만듭니다."},
+		{Name: "지연티켓", Description: "가장 오래 열려 있는 티켓을 확인합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: false, Choices: ticketTopicChoices()}}},
+		{Name: "미응답", Description: "민원인의 마지막 메시지에 아직 답하지 않은 티켓을 오래 기다린 순으로 확인합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: false, Choices: ticketTopicChoices()}}},
+		{Name: "티켓정보", Description: "현재 티켓의 상세 정보를 확인합니다."},
+		{Name: "sla설정", Description: "이 티켓의 처리 기한을 개별 지정합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionString, Name: "duration", Description: "처리 기한 (예: 4h, 2d) 또는 '해제'", Required: true}}},
+		{Name: "우선순위", Description: "티켓의 우선순위를 지정합니다.", Options: []*discordgo.ApplicationCommandOption{{Type: discordgo.ApplicationCommandOptionString, Name: "level", Description: "우선순위", Required: true, Choices: ticketPriorityChoices}}},
+	}
+	failed := 0
 	for _, v := range commands {
-		_, err := dg.ApplicationCommandCreate(dg.State.User.ID, guildID, v)
+		_, err := s.ApplicationCommandCreate(s.State.User.ID, app().GuildID, v)
 		if err != nil {
 			log.Printf("Cannot create '%v' command: %v", v.Name, err)
+			failed++
 		}
 	}
+	registerUserAppCommands(s)
+	commandsRegistered.Store(failed == 0)
 }
 
 func interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	switch i.Type {
-	case discordgo.InteractionApplicationCommand:
-		handleSlashCommands(s, i)
-	case discordgo.InteractionMessageComponent:
-		handleMessageComponent(s, i)
-	case discordgo.InteractionModalSubmit:
-		handleModalSubmit(s, i)
-	}
-}
-
-func handleSlashCommands(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	data := i.ApplicationCommandData()
-	switch data.Name {
-	case "패널":
-		sendTicketPanel(s, i)
-	case "닫기":
-		closeTicket(s, i)
-	case "추가":
-		addUserToTicket(s, i)
-	case "제거":
-		removeUserFromTicket(s, i)
-	case "역할추가":
-		addRoleToTicket(s, i)
-	case "역할제거":
-		removeRoleFromTicket(s, i)
-	case "담당자변경":
-		handleChangeAssignee(s, i)
-	}
-}
-
-func handleMessageComponent(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	data := i.MessageComponentData()
-	switch data.CustomID {
-	case "ticket_topic_select":
-		selectedValue := data.Values[0]
-		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseModal,
-			Data: &discordgo.InteractionResponseData{
-				CustomID: "ticket_modal_submit_" + selectedValue,
-				Title:    "민원인 정보",
-				Components: []discordgo.MessageComponent{
-					discordgo.ActionsRow{
-						Components: []discordgo.MessageComponent{
-							discordgo.TextInput{
-								CustomID:    "nickname",
-								Label:       "민원인 닉네임",
-								Style:       discordgo.TextInputShort,
-								Placeholder: "로블록스 닉네임",
-								Required:    true,
-							},
-						},
-					},
-					discordgo.ActionsRow{
-						Components: []discordgo.MessageComponent{
-							discordgo.TextInput{
-								CustomID:    "content",
-								Label:       "민원 내용",
-								Style:       discordgo.TextInputParagraph,
-								Placeholder: "문의하실 내용을 자세하게 적어주세요.",
-								Required:    true,
-								MaxLength:   4000,
-							},
-						},
-					},
-				},
-			},
-		})
-		if err != nil {
-			log.Printf("Error responding with modal: %v", err)
-		}
-	case "close_ticket_request":
-		handleCloseRequest(s, i)
-	case "confirm_close_ticket":
-		handleConfirmClose(s, i)
-	case "cancel_close_ticket":
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage})
-		s.ChannelMessageDelete(i.ChannelID, i.Message.ID)
-	case "claim_ticket":
-		handleClaimTicket(s, i)
-	case "reopen_ticket":
-		handleReopenTicket(s, i)
-	case "delete_ticket_permanent":
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Flags:  discordgo.MessageFlagsEphemeral,
-				Embeds: []*discordgo.MessageEmbed{{Title: "처리 중...", Description: "대화록을 생성하고 채널을 삭제합니다.", Color: colorGray}},
-			},
-		})
-		ch, _ := s.Channel(i.ChannelID)
-		createAndSendLog(s, ch)
-		time.Sleep(2 * time.Second)
-		s.ChannelDelete(i.ChannelID)
+	router.Dispatch(s, i)
+}
+
+func registerInteractionRoutes() {
+	router.Use(withInFlightTracking, withRecovery, limitComponentClicks)
+
+	router.Command("패널", sendTicketPanel, rejectWhileDraining)
+	router.Command("내티켓", handleMyTickets)
+	router.Command("도움말", handleHelp)
+	router.Command("닫기", handleCloseRequest)
+	router.Command("추가", addUserToTicket)
+	router.Command("제거", removeUserFromTicket)
+	router.Command("역할추가", addRoleToTicket)
+	router.Command("역할제거", removeRoleFromTicket)
+	router.Command("역할참여자추가", addRoleMembersToTicket, supportOnly)
+	router.Command("담당자변경", handleChangeAssignee)
+	router.Command("설정", handleSettings, adminOnly)
+	router.Command("초기설정", handlePreset, adminOnly)
+	router.Command("접수잠금", handleLockdownCommand, adminOnly)
+	router.Command("연습모드", handleSandboxCommand, adminOnly)
+	router.Command("부하테스트", handleLoadTest, ownerOnly)
+	router.Command("차단", handleBlockUser, adminOnly)
+	router.Command("차단해제", handleUnblockUser, adminOnly)
+	router.Command("접수자격", handleTopicRole, adminOnly)
+	router.Command("열람", handleSealedAccess, adminOnly)
+	router.Command("로그재전송", handleLogResend, adminOnly)
+	router.Command("안내버튼", handleGreetingButtons, adminOnly)
+	router.Command("규칙", handleRules, adminOnly)
+	router.Command("번역", handleTranslationToggle, supportOnly)
+	router.Command("대화록내보내기", handleTranscriptExport, adminOnly)
+	router.Command("번호점검", handleCounterAudit, adminOnly)
+	router.Command("스킬", handleSkills, adminOnly)
+	router.Command("태그", handleTicketTag, supportOnly)
+	router.Command("만족도", handleCSATReport, supportOnly)
+	router.Command("응답시간", handleResponseTimeReport, supportOnly)
+	router.Command("통계", handleStats, supportOnly)
+	router.Command("보고서", handleMonthlyReport, supportOnly)
+	router.Command("대화록", handleTranscriptPreview, supportOnly)
+	router.Command("음성상담", handleVoiceSession, supportOnly)
+	router.Command("지연티켓", handleOverdueTickets, supportOnly)
+	router.Command("미응답", handleUnansweredTickets, supportOnly)
+	router.Command("티켓목록", handleTicketList, supportOnly)
+	router.Command("검색", handleSearch, supportOnly)
+	router.Command("알림설정", handleAlertSettings, supportOnly)
+	router.Command("대화검색", handleTranscriptSearch, supportOnly)
+	router.Command("sla설정", handleSLAOverride, supportOnly)
+	router.Command("티켓정보", handleTicketInfo, supportOnly)
+	router.Command("우선순위", handleTicketPriority, supportOnly)
+	router.Command("연결", handleLinkTicket, supportOnly)
+	router.Command("연결해제", handleUnlinkTicket, supportOnly)
+	router.Command(shareToLinkedCommandName, handleShareToLinked, supportOnly)
+	router.Command(openForUserCommandName, handleOpenTicketFor, supportOnly, rejectWhileDraining)
+	router.Command(ticketHistoryCommandName, handleTicketHistoryCommand, supportOnly)
+
+	router.Component("ticket_topic_select", handleTopicSelect, rejectWhileDraining)
+	router.Component("cancel_close_ticket", handleCancelClose)
+	router.Component(liftLockdownID, handleLiftLockdown, adminOnly)
+	router.Component(appealButtonID, handleAppealButton)
+	router.TicketAction(actionCloseRequest, handleCloseRequest)
+	router.TicketAction(closeCodeSelectID, handleCloseCodeSelect)
+	router.TicketAction(actionConfirmSelf, handleConfirmSelfClose)
+	router.TicketAction(actionClaim, handleClaimTicket)
+	router.TicketAction(actionReopen, handleReopenTicket)
+	router.TicketAction(actionDeletePermanent, handleDeletePermanent)
+	router.TicketAction(actionReleaseQuarantine, handleReleaseQuarantine)
+	router.ComponentPrefix(verificationChallengePrefix, handleVerificationChallenge)
+	router.ComponentPrefix(roleFixPrefix, handleRoleFix, adminOnly)
+	router.ComponentPrefix(closedCleanupPrefix, handleClosedCleanup, adminOnly)
+	router.ComponentPrefix(reassignPrefix, handleReassignSuggestion, supportOnly)
+	router.ComponentPrefix(appealDecisionPrefix, handleAppealDecision, adminOnly)
+	router.ComponentPrefix(proxyTopicPrefix, handleProxyTopicSelect, supportOnly, rejectWhileDraining)
+	router.ComponentPrefix(ticketHistoryPrefix, handleTicketHistoryPage, supportOnly)
+	router.ComponentPrefix(ticketListPrefix, handleTicketListPage, supportOnly)
+	router.ComponentPrefix(greetingButtonPrefix, handleGreetingButton)
+	router.ComponentPrefix("csat_rate:", handleCSATRating)
+	router.ComponentPrefix("csat_comment:", handleCSATCommentButton)
+
+	router.ModalPrefix("csat_comment_submit:", handleCSATCommentSubmit)
+	router.ModalPrefix(closeReasonModalPrefix, handleCloseReasonSubmit)
+	router.ModalPrefix(appealModalID, handleAppealSubmit)
+	router.ModalPrefix(ticketModalPrefix, handleTicketModalSubmit, rejectWhileDraining)
+}
+
+func handleTopicSelect(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	selectedValue := i.MessageComponentData().Values[0]
+	if lockdown := getConfig().Lockdown; lockdown.active() && !hasSupportRole(i.Member) {
+		respondError(s, i, errIntakeLockdown, nil, lockdown.Until.Unix())
+		return
 	}
+	if !checkTicketBlock(s, i) || !checkTopicRole(s, i, selectedValue) || !checkIntakeRequirements(s, i) || !checkOpenTicketLimit(s, i) || !checkTicketCooldown(s, i) {
+		return
+	}
+	if needsChallenge(i) {
+		sendVerificationChallenge(s, i, selectedValue)
+		return
+	}
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseModal, Data: intakeModal(selectedValue)})
+	if err != nil {
+		log.Printf("Error responding with modal: %v", err)
+	}
+}
+
+func handleCancelClose(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage})
+	s.ChannelMessageDelete(i.ChannelID, i.Message.ID)
 }
 
-func handleModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
+func handleDeletePermanent(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	ch, _ := s.Channel(i.ChannelID)
+	if !allowDestructive(ch) {
+		respondError(s, i, errSandboxProtected, nil)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags:  discordgo.MessageFlagsEphemeral,
+			Embeds: []*discordgo.MessageEmbed{{Title: "처리 중...", Description: "대화록을 생성하고 채널을 삭제합니다.", Color: colorGray}},
+		},
+	})
+	time.Sleep(2 * time.Second)
+	if err := purgeClosedTicket(s, ch); err != nil {
+		log.Printf("Error deleting ticket: %v", err)
+	}
+}
+
+func handleTicketModalSubmit(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	data := i.ModalSubmitData()
-	topicValue := strings.TrimPrefix(data.CustomID, "ticket_modal_submit_")
-	nickname := data.Components[0].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
-	content := data.Components[1].(*discordgo.ActionsRow).Components[0].(*discordgo.TextInput).Value
-	createTicketChannel(s, i, topicValue, nickname, content)
+	topicValue := strings.TrimPrefix(data.CustomID, ticketModalPrefix)
+	if !checkTopicRole(s, i, topicValue) || !checkIntakeRequirements(s, i) {
+		return
+	}
+	if needsChallenge(i) {
+		respondError(s, i, errVerificationFailed, nil)
+		return
+	}
+	createTicketChannel(s, i, i.Member.User.ID, topicValue, parseIntakeAnswers(topicValue, data))
 }
 
 func sendTicketPanel(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "강원특별자치도청 민원창구", Description: "아래 메뉴에서 원하시는 민원 창구를 선택하여 티켓을 생성해주세요.", Color: colorBlue}}, Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.SelectMenu{CustomID: "ticket_topic_select", Placeholder: "문의할 창구를 선택해주세요.", Options: ticketOptions}}}}}})
+	embeds, components := panelContent(getConfig().Lockdown.active())
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: embeds, Components: components}}); err != nil {
+		log.Printf("Error sending ticket panel: %v", err)
+		return
+	}
+	rememberPanelMessage(s, i)
 }
 
 func handleCloseRequest(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "닫기 확인", Description: "정말로 티켓을 닫으시겠습니까?\n닫힌 티켓은 관리자만 다시 열 수 있습니다.", Color: colorYellow}}, Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.Button{Label: "닫기 확인", Style: discordgo.DangerButton, CustomID: "confirm_close_ticket"}, discordgo.Button{Label: "취소", Style: discordgo.SecondaryButton, CustomID: "cancel_close_ticket"}}}}}})
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	if t.Status != ticketStatusOpen {
+		respondError(s, i, errTicketNotOpen, nil)
+		return
+	}
+	if t.OwnerID == i.Member.User.ID {
+		handleSelfCloseRequest(s, i, t)
+		return
+	}
+	sendCloseCodeSelect(s, i, t)
 }
 
-func handleConfirmClose(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "처리 중...", Description: "티켓을 닫고 보관 처리하고 있습니다.", Color: colorGray}}, Components: []discordgo.MessageComponent{}}})
-	ch, _ := s.Channel(i.ChannelID)
-	userID := getUserIDFromTopic(ch.Topic)
-	if userID == "" {
-		log.Println("Error: Could not find user ID in channel topic.")
-		return
+func closeTicketChannel(s *discordgo.Session, t *ticket, closedByID string, selfResolved bool) {
+	now := time.Now()
+	pauseHandling(t.ChannelID, now)
+	closeUpdate := bson.M{"status": ticketStatusClosed, "closed_at": now, "closed_by": closedByID, "self_resolved": selfResolved, "resolution_time": now.Sub(t.CreatedAt)}
+	var err error
+	if t.Forum {
+		if err = setForumTicketClosed(s, t, true); err != nil {
+			log.Printf("Error locking forum ticket: %v", err)
+		}
+	} else {
+		if ch, err := s.Channel(t.ChannelID); err == nil {
+			closeUpdate["overwrites"] = snapshotOverwrites(ch.PermissionOverwrites)
+		} else {
+			log.Printf("Could not snapshot permissions of ticket '%s': %v", t.Name(), err)
+		}
+		if err := retryDiscord("permission set", func() error {
+			return s.ChannelPermissionSet(t.ChannelID, t.OwnerID, discordgo.PermissionOverwriteTypeMember, 0, discordgo.PermissionViewChannel)
+		}); err != nil {
+			log.Printf("Error hiding ticket '%s' from its owner: %v", t.Name(), err)
+		}
+		_, err = withRetry("channel edit", func() (*discordgo.Channel, error) {
+			return s.ChannelEditComplex(t.ChannelID, &discordgo.ChannelEdit{ParentID: getConfig().ClosedCategoryID})
+		})
+		if err != nil {
+			log.Printf("Error moving channel to closed category: %v", err)
+		}
 	}
-	s.ChannelPermissionSet(ch.ID, userID, discordgo.PermissionOverwriteTypeMember, 0, discordgo.PermissionViewChannel)
-	_, err := s.ChannelEditComplex(ch.ID, &discordgo.ChannelEdit{
-		ParentID: closedTicketsCategoryID,
-	})
-	if err != nil {
-		log.Printf("Error moving channel to closed category: %v", err)
+	closeVoiceSession(s, t)
+	description := fmt.Sprintf("<@%s> 님이 티켓을 닫았습니다. 아래 버튼을 사용하여 티켓을 관리하세요.", closedByID)
+	if selfResolved {
+		description = fmt.Sprintf("민원인 <@%s> 님이 해결됨으로 티켓을 닫았습니다. 아래 버튼을 사용하여 티켓을 관리하세요.", closedByID)
 	}
-	adminPanel := &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{{Title: "관리자 패널", Description: fmt.Sprintf("<@%s> 님이 티켓을 닫았습니다. 아래 버튼을 사용하여 티켓을 관리하세요.", i.Member.User.ID), Color: colorGray}}, Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
-		discordgo.Button{Label: "티켓 재오픈", Style: discordgo.SuccessButton, CustomID: "reopen_ticket"},
-		discordgo.Button{Label: "티켓 삭제", Style: discordgo.DangerButton, CustomID: "delete_ticket_permanent"},
+	adminPanel := &discordgo.MessageSend{Embeds: []*discordgo.MessageEmbed{{Title: "관리자 패널", Description: description, Color: colorGray, Fields: closeReasonFields(t)}}, Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "티켓 재오픈", Style: discordgo.SuccessButton, CustomID: ticketComponentID(actionReopen, t)},
+		discordgo.Button{Label: "티켓 삭제", Style: discordgo.DangerButton, CustomID: ticketComponentID(actionDeletePermanent, t)},
 	}}}}
-	s.ChannelMessageSendComplex(ch.ID, adminPanel)
-	s.ChannelMessageDelete(i.ChannelID, i.Message.ID)
+	withRetry("message send", func() (*discordgo.Message, error) { return s.ChannelMessageSendComplex(t.ChannelID, adminPanel) })
+	err = updateTicket(t.ChannelID, bson.M{"$set": closeUpdate, "$inc": bson.M{"open_duration": now.Sub(t.openedAt())}})
+	if err != nil {
+		log.Printf("Error recording ticket close: %v", err)
+	}
+	if featuresFor(t.Category).CSAT {
+		sendCSATPrompt(s, t)
+	}
+	t.Status = ticketStatusClosed
+	incCounter("potatobot_tickets_closed_total", metricLabel("category", t.Category))
+	evaluateRules(s, t, ruleEventClosed)
 }
 
 func handleClaimTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	ch, _ := s.Channel(i.ChannelID)
-	ticketOwnerID := getUserIDFromTopic(ch.Topic)
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
 	clickerID := i.Member.User.ID
 
-	if clickerID == ticketOwnerID {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Flags:  discordgo.MessageFlagsEphemeral,
-				Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "티켓을 개설한 본인은 담당자가 될 수 없습니다.", Color: colorRed}},
-			},
-		})
+	if clickerID == t.OwnerID {
+		respondError(s, i, errOwnerCannotClaim, nil)
 		return
 	}
 
@@ -376,29 +698,21 @@ func handleClaimTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 
 	if !isSupportMember {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Flags:  discordgo.MessageFlagsEphemeral,
-				Embeds: []*discordgo.MessageEmbed{{Title: "권한 없음", Description: "지원팀 역할이 없습니다.", Color: colorRed}},
-			},
-		})
+		respondError(s, i, errNoSupportRole, nil)
 		return
 	}
-	originalEmbed := i.Message.Embeds[0]
-	for _, field := range originalEmbed.Fields {
-		if field.Name == "담당자" {
-			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "이미 담당자가 배정된 티켓입니다.", Color: colorRed}}}})
-			return
-		}
+	if t.AssigneeID != "" {
+		respondError(s, i, errAlreadyClaimed, nil)
+		return
 	}
+	originalEmbed := i.Message.Embeds[0]
 	originalEmbed.Fields = append(originalEmbed.Fields, &discordgo.MessageEmbedField{Name: "담당자", Value: i.Member.Mention(), Inline: false})
 	components := i.Message.Components
 	for _, row := range components {
 		if actionsRow, ok := row.(*discordgo.ActionsRow); ok {
 			for j, comp := range actionsRow.Components {
 				if button, ok := comp.(*discordgo.Button); ok {
-					if button.CustomID == "claim_ticket" {
+					if action, _ := parseTicketComponentID(button.CustomID); action == actionClaim {
 						button.Disabled = true
 						actionsRow.Components[j] = button
 					}
@@ -408,30 +722,31 @@ func handleClaimTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	}
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{originalEmbed}, Components: components}})
 	s.ChannelMessageSendEmbed(i.ChannelID, &discordgo.MessageEmbed{Title: "담당자 배정", Description: fmt.Sprintf("<@%s> 님이 이 티켓의 담당자로 배정되었습니다.", i.Member.User.ID), Color: colorGreen})
+	now := time.Now()
+	err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"assignee_id": clickerID, "claimed_at": now}})
+	if err != nil {
+		log.Printf("Error recording ticket claim: %v", err)
+	}
+	resumeHandling(t.ChannelID, now)
+	t.AssigneeID = clickerID
+	evaluateRules(s, t, ruleEventClaimed)
+	checkClaimLimit(s, t, clickerID)
 }
 
 func handleChangeAssignee(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	targetUser := i.ApplicationCommandData().Options[0].UserValue(s)
 	executor := i.Member
-	ch, _ := s.Channel(i.ChannelID)
-	if !strings.Contains(ch.Topic, "User ID:") {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "이 명령어는 티켓 채널에서만 사용할 수 있습니다.", Color: colorRed}}}})
+	t := requireTicket(s, i)
+	if t == nil {
 		return
 	}
-	var ticketMessage *discordgo.Message
-	messages, err := s.ChannelMessages(i.ChannelID, 100, "", "", "")
+	ticketMessage, err := findTicketMessage(s, i.ChannelID)
 	if err != nil {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "티켓 정보를 찾는 데 실패했습니다.", Color: colorRed}}}})
+		respondError(s, i, errTicketFetchFailed, err)
 		return
 	}
-	for _, msg := range messages {
-		if msg.Author.ID == s.State.User.ID && len(msg.Embeds) > 0 && len(msg.Components) > 0 {
-			ticketMessage = msg
-			break
-		}
-	}
 	if ticketMessage == nil {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "원본 티켓 메시지를 찾을 수 없습니다.", Color: colorRed}}}})
+		respondError(s, i, errTicketMessageMissing, nil)
 		return
 	}
 	isManager := false
@@ -441,96 +756,87 @@ func handleChangeAssignee(s *discordgo.Session, i *discordgo.InteractionCreate)
 			break
 		}
 	}
-	currentAssigneeID := ""
-	for _, field := range ticketMessage.Embeds[0].Fields {
-		if field.Name == "담당자" {
-			currentAssigneeID = strings.Trim(field.Value, "<@!>")
-		}
-	}
-	if !isManager && executor.User.ID != currentAssigneeID {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "권한 없음", Description: "관리자 또는 현재 담당자만 이 명령어를 사용할 수 있습니다.", Color: colorRed}}}})
+	if !isManager && executor.User.ID != t.AssigneeID {
+		respondError(s, i, errNotManagerOrAssignee, nil)
 		return
 	}
 	perms, err := s.UserChannelPermissions(targetUser.ID, i.ChannelID)
 	if err != nil {
-		log.Printf("Could not get user permissions for channel: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "대상 사용자의 권한을 확인하는 데 실패했습니다.", Color: colorRed}}}})
+		respondError(s, i, errPermissionLookup, err)
 		return
 	}
 	if (perms & discordgo.PermissionViewChannel) != discordgo.PermissionViewChannel {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: fmt.Sprintf("%s 님은 이 채널을 볼 수 없어 담당자로 지정할 수 없습니다.", targetUser.Username), Color: colorRed}}}})
+		respondError(s, i, errAssigneeCannotView, nil, targetUser.Username)
 		return
 	}
-	originalEmbed := ticketMessage.Embeds[0]
-	assigneeFieldExists := false
-	for _, field := range originalEmbed.Fields {
-		if field.Name == "담당자" {
-			field.Value = targetUser.Mention()
-			assigneeFieldExists = true
-			break
-		}
-	}
-	if !assigneeFieldExists {
-		originalEmbed.Fields = append(originalEmbed.Fields, &discordgo.MessageEmbedField{Name: "담당자", Value: targetUser.Mention(), Inline: false})
+	if err := setTicketMessageAssignee(s, ticketMessage, targetUser.Mention()); err != nil {
+		respondError(s, i, errTicketMessageEdit, err)
+		return
 	}
-	for _, row := range ticketMessage.Components {
-		if actionsRow, ok := row.(*discordgo.ActionsRow); ok {
-			for j, comp := range actionsRow.Components {
-				if button, ok := comp.(*discordgo.Button); ok && button.CustomID == "claim_ticket" && !button.Disabled {
-					button.Disabled = true
-					actionsRow.Components[j] = button
-				}
-			}
-		}
+	now := time.Now()
+	pauseHandling(t.ChannelID, now)
+	update := bson.M{"assignee_id": targetUser.ID}
+	if t.AssigneeID == "" {
+		update["claimed_at"] = now
 	}
-	editedEmbeds := []*discordgo.MessageEmbed{originalEmbed}
-	_, err = s.ChannelMessageEditComplex(&discordgo.MessageEdit{
-		Channel:    i.ChannelID,
-		ID:         ticketMessage.ID,
-		Embeds:     &editedEmbeds,
-		Components: &ticketMessage.Components,
-	})
-	if err != nil {
-		log.Printf("Error editing ticket message: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "티켓 메시지를 수정하는 데 실패했습니다.", Color: colorRed}}}})
-		return
+	if err := updateTicket(t.ChannelID, bson.M{"$set": update}); err != nil {
+		log.Printf("Error recording assignee change: %v", err)
 	}
+	resumeHandling(t.ChannelID, now)
 	s.ChannelMessageSendEmbed(i.ChannelID, &discordgo.MessageEmbed{
 		Title:       "담당자 변경",
 		Description: fmt.Sprintf("담당자가 <@%s> 님에서 <@%s> 님으로 변경되었습니다.", executor.User.ID, targetUser.ID),
 		Color:       colorYellow,
 	})
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "성공", Description: "담당자를 성공적으로 변경했습니다.", Color: colorGreen}}}})
+	checkClaimLimit(s, t, targetUser.ID)
 }
 
 func handleReopenTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage})
-	ch, _ := s.Channel(i.ChannelID)
-	_, err := s.ChannelEditComplex(ch.ID, &discordgo.ChannelEdit{
-		ParentID: openTicketsCategoryID,
-	})
-	if err != nil {
-		log.Printf("Error moving channel to open category: %v", err)
+	var err error
+	if t.Forum {
+		if err = setForumTicketClosed(s, t, false); err != nil {
+			log.Printf("Error unlocking forum ticket: %v", err)
+		}
+	} else {
+		_, err = withRetry("channel edit", func() (*discordgo.Channel, error) {
+			return s.ChannelEditComplex(t.ChannelID, &discordgo.ChannelEdit{
+				ParentID:             getConfig().OpenCategoryID,
+				PermissionOverwrites: t.restoreOverwrites(),
+			})
+		})
+		if err != nil {
+			log.Printf("Error moving channel to open category: %v", err)
+		}
+		if len(t.Overwrites) == 0 || err != nil {
+			retryDiscord("permission set", func() error {
+				return s.ChannelPermissionSet(t.ChannelID, t.OwnerID, discordgo.PermissionOverwriteTypeMember, discordgo.PermissionViewChannel, 0)
+			})
+		}
 	}
-	userID := getUserIDFromTopic(ch.Topic)
-	if userID == "" {
-		log.Println("Error: Could not find user ID in channel topic.")
-		return
+	err = updateTicket(t.ChannelID, bson.M{"$set": bson.M{"status": ticketStatusOpen, "self_resolved": false, "reopened_at": time.Now()}, "$inc": bson.M{"reopen_count": 1}, "$unset": bson.M{"closed_at": "", "closed_by": "", "close_code": "", "close_reason": "", "resolution": "", "overwrites": "", "inactivity_warning_id": "", "inactivity_warned_at": ""}})
+	if err != nil {
+		log.Printf("Error recording ticket reopen: %v", err)
 	}
-	s.ChannelPermissionSet(ch.ID, userID, discordgo.PermissionOverwriteTypeMember, discordgo.PermissionViewChannel, 0)
-	s.ChannelMessageDelete(ch.ID, i.Message.ID)
-	s.ChannelMessageSendEmbed(ch.ID, &discordgo.MessageEmbed{Title: "티켓 재오픈", Description: fmt.Sprintf("<@%s> 님이 티켓을 다시 열었습니다. <@%s>님, 다시 문의를 진행해주세요.", i.Member.User.ID, userID), Color: colorGreen})
+	s.ChannelMessageDelete(t.ChannelID, i.Message.ID)
+	s.ChannelMessageSendEmbed(t.ChannelID, &discordgo.MessageEmbed{Title: "티켓 재오픈", Description: fmt.Sprintf("<@%s> 님이 티켓을 다시 열었습니다. <@%s>님, 다시 문의를 진행해주세요.", i.Member.User.ID, t.OwnerID), Color: colorGreen})
+	t.Status = ticketStatusOpen
+	evaluateRules(s, t, ruleEventReopened)
 }
 
-func createAndSendLog(s *discordgo.Session, channel *discordgo.Channel) {
+func fetchAllMessages(s *discordgo.Session, channelID string) ([]*discordgo.Message, error) {
 	var allMessages []*discordgo.Message
 	var lastMessageID string
 
 	for {
-		messages, err := s.ChannelMessages(channel.ID, 100, lastMessageID, "", "")
+		messages, err := s.ChannelMessages(channelID, 100, lastMessageID, "", "")
 		if err != nil {
-			log.Printf("Error fetching messages for log: %v", err)
-			return
+			return nil, err
 		}
 		if len(messages) == 0 {
 			break
@@ -541,26 +847,58 @@ func createAndSendLog(s *discordgo.Session, channel *discordgo.Channel) {
 	for i, j := 0, len(allMessages)-1; i < j; i, j = i+1, j-1 {
 		allMessages[i], allMessages[j] = allMessages[j], allMessages[i]
 	}
+	return allMessages, nil
+}
 
-	htmlContent := generateHTML(channel, allMessages)
-	fileName := fmt.Sprintf("transcript-%s.html", channel.Name)
-	err = os.WriteFile(fileName, []byte(htmlContent), 0644)
+func createAndSendLog(s *discordgo.Session, channel *discordgo.Channel) {
+	allMessages, err := ticketMessages(s, channel)
 	if err != nil {
-		log.Printf("Error writing transcript file for log: %v", err)
+		log.Printf("Error fetching messages for log: %v", err)
 		return
 	}
-	defer os.Remove(fileName)
 
-	file, err := os.Open(fileName)
+	features := featuresFor(ticketCategory(channel))
+	var files []*discordgo.File
+	if features.Transcripts {
+		var htmlContent string
+		pprof.Do(context.Background(), pprof.Labels("task", "transcript", "ticket", channel.Name), func(context.Context) {
+			htmlContent = generateHTML(channel, allMessages)
+		})
+		observeHistogram("potatobot_transcript_size_bytes", metricLabel("category", ticketCategory(channel)), transcriptSizeBuckets, float64(len(htmlContent)))
+		saveTranscript(channel, allMessages, htmlContent)
+		if !features.Encrypted {
+			file, err := writeTempTranscript(htmlContent)
+			if err != nil {
+				log.Printf("Error writing transcript file for log: %v", err)
+				return
+			}
+			defer removeTempFile(file)
+			fileName := fmt.Sprintf("transcript-%s.html", channel.Name)
+			files = append(files, &discordgo.File{Name: fileName, ContentType: "text/html", Reader: file})
+		}
+	}
+
+	logMessage := &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{ticketLogEmbed(s, channel.Name, ticketCategory(channel), ticketOwnerID(channel), ticketForChannel(channel), allMessages)},
+		Files:  files,
+	}
+	sent, err := s.ChannelMessageSendComplex(getConfig().LogChannelID, logMessage)
 	if err != nil {
-		log.Printf("Error opening transcript file for log: %v", err)
+		log.Printf("Error sending ticket log for '%s': %v", channel.Name, err)
 		return
 	}
-	defer file.Close()
+	if features.Transcripts && app().Tickets != nil {
+		link := fmt.Sprintf("https://discord.com/channels/%s/%s/%s", app().GuildID, sent.ChannelID, sent.ID)
+		if err := updateTicket(channel.ID, bson.M{"$set": bson.M{"transcript_link": link}}); err != nil {
+			log.Printf("Could not record transcript link for '%s': %v", channel.Name, err)
+		}
+	}
+}
 
-	guild, _ := s.Guild(guildID)
-	ownerID := getUserIDFromTopic(channel.Topic)
-	ownerMember, _ := s.GuildMember(guildID, ownerID)
+func ticketLogEmbed(s *discordgo.Session, name, category, ownerID string, t *ticket, allMessages []*discordgo.Message) *discordgo.MessageEmbed {
+	features := featuresFor(category)
+	guild, _ := s.Guild(app().GuildID)
+	ownerMember, _ := s.GuildMember(app().GuildID, ownerID)
 
 	messageCounts := make(map[string]int)
 	participants := make(map[string]*discordgo.User)
@@ -586,33 +924,49 @@ func createAndSendLog(s *discordgo.Session, channel *discordgo.Channel) {
 	var membersBuilder strings.Builder
 	for _, member := range sortedMembers {
 		user := participants[member.ID]
+		if features.Anonymous && member.ID == ownerID {
+			membersBuilder.WriteString(fmt.Sprintf("%d - %s\n", member.Count, anonymousDisplayName))
+			continue
+		}
 		membersBuilder.WriteString(fmt.Sprintf("%d - @%s#%s\n", member.Count, user.Username, user.Discriminator))
 	}
+	if membersBuilder.Len() == 0 {
+		membersBuilder.WriteString("집계할 메시지가 없습니다.\n")
+	}
+
+	ownerAuthor := &discordgo.MessageEmbedAuthor{Name: anonymousDisplayName}
+	ownerValue := anonymousDisplayName
+	if !features.Anonymous && ownerMember == nil {
+		ownerAuthor = &discordgo.MessageEmbedAuthor{Name: ownerID}
+		ownerValue = fmt.Sprintf("<@%s>", ownerID)
+	} else if !features.Anonymous {
+		ownerAuthor = &discordgo.MessageEmbedAuthor{Name: ownerMember.User.Username, IconURL: ownerMember.User.AvatarURL("")}
+		ownerValue = ownerMember.Mention()
+	}
+	transcriptValue := "```" + membersBuilder.String() + "```"
+	if !features.Transcripts {
+		transcriptValue += "\n이 민원 종류는 대화록 파일을 보관하지 않습니다."
+	} else if features.Encrypted {
+		transcriptValue += "\n대화록은 암호화되어 보관됩니다. 관리자는 /열람 명령어로 확인할 수 있습니다."
+	}
 
 	logEmbed := &discordgo.MessageEmbed{
-		Author: &discordgo.MessageEmbedAuthor{
-			Name:    ownerMember.User.Username,
-			IconURL: ownerMember.User.AvatarURL(""),
-		},
-		Color: colorGray,
+		Author: ownerAuthor,
+		Color:  colorGray,
 		Fields: []*discordgo.MessageEmbedField{
-			{Name: "민원인", Value: ownerMember.Mention(), Inline: true},
-			{Name: "티켓 이름", Value: channel.Name, Inline: true},
-			{Name: "민원 종류", Value: strings.Split(channel.Name, "-")[0], Inline: true},
-			{Name: "대화 기록", Value: "```" + membersBuilder.String() + "```", Inline: false},
+			{Name: "민원인", Value: ownerValue, Inline: true},
+			{Name: "티켓 이름", Value: name, Inline: true},
+			{Name: "민원 종류", Value: category, Inline: true},
 		},
 		Footer: &discordgo.MessageEmbedFooter{
-			Text:    "강원특별자치도청",
+			Text:    institutionName(),
 			IconURL: guild.IconURL(""),
 		},
 		Timestamp: time.Now().In(kstLocation).Format(time.RFC3339),
 	}
-
-	logMessage := &discordgo.MessageSend{
-		Embeds: []*discordgo.MessageEmbed{logEmbed},
-		Files:  []*discordgo.File{{Name: fileName, ContentType: "text/html", Reader: file}},
-	}
-	s.ChannelMessageSendComplex(logChannelID, logMessage)
+	logEmbed.Fields = append(logEmbed.Fields, closeReasonFields(t)...)
+	logEmbed.Fields = append(logEmbed.Fields, &discordgo.MessageEmbedField{Name: "대화 기록", Value: transcriptValue, Inline: false})
+	return logEmbed
 }
 
 func imageToBase64(url string) string {
@@ -638,24 +992,104 @@ func imageToBase64(url string) string {
 	return fmt.Sprintf("data:%s;base64,%s", contentType, base64Str)
 }
 
+func inlineImage(url string) string {
+	return fmt.Sprintf(`src="%s" data-src="%s"`, imageToBase64(url), html.EscapeString(url))
+}
+
+func formatFileSize(size int) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
+
+func attachmentIcon(a *discordgo.MessageAttachment) string {
+	ext := strings.ToLower(path.Ext(a.Filename))
+	switch {
+	case ext == ".pdf":
+		return "📕"
+	case ext == ".hwp" || ext == ".hwpx" || ext == ".doc" || ext == ".docx" || ext == ".txt":
+		return "📝"
+	case ext == ".xls" || ext == ".xlsx" || ext == ".csv":
+		return "📊"
+	case ext == ".zip" || ext == ".7z" || ext == ".rar":
+		return "🗜️"
+	case strings.HasPrefix(a.ContentType, "video/"):
+		return "🎞️"
+	case strings.HasPrefix(a.ContentType, "audio/"):
+		return "🎵"
+	}
+	return "📎"
+}
+
+func attachmentCardHTML(a *discordgo.MessageAttachment) string {
+	return fmt.Sprintf(`<div class="attachment-file"><span class="attachment-file-icon">%s</span><div><div class="attachment-file-name"><a href="%s" target="_blank">%s</a></div><div class="attachment-file-size">%s</div></div></div>`,
+		attachmentIcon(a), html.EscapeString(a.URL), html.EscapeString(a.Filename), formatFileSize(a.Size))
+}
+
 func generateHTML(channel *discordgo.Channel, messages []*discordgo.Message) string {
+	return renderTranscript(channel, messages, defaultTranscriptStyle())
+}
+
+func renderTranscript(channel *discordgo.Channel, messages []*discordgo.Message, style transcriptStyle) string {
 	var sb strings.Builder
 	sb.WriteString(`<!DOCTYPE html><html><head><meta charset="UTF-8"><title>Transcript for #` + html.EscapeString(channel.Name) + `</title>`)
-	sb.WriteString(`<style>body{background-color:#313338;color:#dcddde;font-family: 'Whitney', 'Helvetica Neue', Helvetica, Arial, sans-serif;}.container{padding:20px;max-width:800px;margin:auto;}.message{display:flex;margin-bottom:20px;}.avatar{width:40px;height:40px;border-radius:50%;margin-right:15px;}.message-content{display:flex;flex-direction:column;}.header{display:flex;align-items:center;margin-bottom:2px;}.username{font-weight:500;color:#fff;}.bot-tag{background-color:#5865f2;color:#fff;font-size:0.65em;padding:2px 4px;border-radius:3px;margin-left:5px;vertical-align:middle;}.timestamp{font-size:0.75em;color:#949ba4;margin-left:10px;}.content{line-height:1.375em;white-space:pre-wrap;}.attachment-image{max-width:400px;max-height:300px;border-radius:5px;margin-top:5px;}.embed{background-color:#2b2d31;border-left:4px solid #4f545c;border-radius:5px;padding:10px;margin-top:5px;display:grid;grid-template-columns:auto 1fr;}.embed-content{grid-column:2/3;}.embed-thumbnail{grid-column:3/4;grid-row:1/5;margin-left:10px;}.embed-thumbnail img{max-width:80px;max-height:80px;border-radius:5px;}.embed-author{display:flex;align-items:center;margin-bottom:5px;font-size:0.875em;}.embed-author-icon{width:24px;height:24px;border-radius:50%;margin-right:8px;}.embed-author-name a{color:#00a8fc;text-decoration:none;font-weight:500;}.embed-title{font-weight:bold;color:#fff;margin-bottom:5px;}.embed-title a{color:#00a8fc;text-decoration:none;}.embed-description{font-size:0.9em;margin-bottom:10px;}.embed-fields{display:flex;flex-wrap:wrap;gap:10px;}.embed-field{min-width:150px;flex-grow:1;}.embed-field-inline{flex-basis:25%;}.embed-field-name{font-weight:bold;margin-bottom:2px;font-size:0.875em;}.embed-field-value{font-size:0.875em;}.embed-image img{max-width:100%;border-radius:5px;margin-top:10px;}.embed-footer{display:flex;align-items:center;font-size:0.75em;margin-top:10px;color:#949ba4;}.embed-footer-icon{width:20px;height:20px;border-radius:50%;margin-right:8px;}</style>`)
+	sb.WriteString(`<style>body{background-color:#313338;color:#dcddde;font-family: 'Whitney', 'Helvetica Neue', Helvetica, Arial, sans-serif;}.container{padding:20px;max-width:800px;margin:auto;}.message{display:flex;margin-bottom:20px;}.avatar{width:40px;height:40px;border-radius:50%;margin-right:15px;}.message-content{display:flex;flex-direction:column;}.header{display:flex;align-items:center;margin-bottom:2px;}.username{font-weight:500;color:#fff;}.bot-tag{background-color:#5865f2;color:#fff;font-size:0.65em;padding:2px 4px;border-radius:3px;margin-left:5px;vertical-align:middle;}.timestamp{font-size:0.75em;color:#949ba4;margin-left:10px;}.content{line-height:1.375em;white-space:pre-wrap;}.attachment-image{max-width:400px;max-height:300px;border-radius:5px;margin-top:5px;}.embed{background-color:#2b2d31;border-left:4px solid #4f545c;border-radius:5px;padding:10px;margin-top:5px;display:grid;grid-template-columns:auto 1fr;}.embed-content{grid-column:2/3;}.embed-thumbnail{grid-column:3/4;grid-row:1/5;margin-left:10px;}.embed-thumbnail img{max-width:80px;max-height:80px;border-radius:5px;}.embed-author{display:flex;align-items:center;margin-bottom:5px;font-size:0.875em;}.embed-author-icon{width:24px;height:24px;border-radius:50%;margin-right:8px;}.embed-author-name a{color:#00a8fc;text-decoration:none;font-weight:500;}.embed-title{font-weight:bold;color:#fff;margin-bottom:5px;}.embed-title a{color:#00a8fc;text-decoration:none;}.embed-description{font-size:0.9em;margin-bottom:10px;}.embed-fields{display:flex;flex-wrap:wrap;gap:10px;}.embed-field{min-width:150px;flex-grow:1;}.embed-field-inline{flex-basis:25%;}.embed-field-name{font-weight:bold;margin-bottom:2px;font-size:0.875em;}.embed-field-value{font-size:0.875em;}.embed-image img{max-width:100%;border-radius:5px;margin-top:10px;}.embed-footer{display:flex;align-items:center;font-size:0.75em;margin-top:10px;color:#949ba4;}.embed-footer-icon{width:20px;height:20px;border-radius:50%;margin-right:8px;}.system-line{color:#949ba4;font-size:0.875em;margin:0 0 20px 55px;}.attachment-file{display:flex;align-items:center;background-color:#2b2d31;border:1px solid #1e1f22;border-radius:5px;padding:10px;margin-top:5px;max-width:400px;}.attachment-file-icon{font-size:1.75em;margin-right:10px;}.attachment-file-name a{color:#00a8fc;text-decoration:none;word-break:break-all;}.attachment-file-size{font-size:0.75em;color:#949ba4;}.deleted-message{text-decoration:line-through;opacity:0.6;}.message-note{font-size:0.75em;color:#949ba4;margin-top:2px;}.revisions{font-size:0.875em;color:#949ba4;margin-top:2px;}.revisions summary{cursor:pointer;}.revision{border-left:2px solid #4f545c;padding-left:6px;margin-top:4px;white-space:pre-wrap;}.mention{background-color:rgba(88,101,242,0.3);color:#c9cdfb;border-radius:3px;padding:0 2px;font-weight:500;}.reactions{display:flex;flex-wrap:wrap;gap:4px;margin-top:4px;}.reaction{background-color:#2b2d31;border:1px solid #3f4147;border-radius:8px;padding:1px 6px;font-size:0.875em;}.reaction img{width:16px;height:16px;vertical-align:middle;}` + style.css() + `</style>`)
 	sb.WriteString(`</head><body><div class="container"><h1>Transcript for #` + html.EscapeString(channel.Name) + `</h1>`)
+	t := ticketForChannel(channel)
+	if t != nil && (t.CloseCode != "" || t.CloseReason != "") {
+		sb.WriteString(`<div class="embed"><div class="embed-content">`)
+		if t.CloseCode != "" {
+			sb.WriteString(`<div class="embed-title">종료 코드</div><div class="embed-description">` + html.EscapeString(t.CloseCode) + `</div>`)
+		}
+		if t.CloseReason != "" {
+			sb.WriteString(`<div class="embed-title">종료 사유</div><div class="embed-description">` + html.EscapeString(t.CloseReason) + `</div>`)
+		}
+		if t.Resolution != "" {
+			sb.WriteString(`<div class="embed-title">처리 결과</div><div class="embed-description">` + html.EscapeString(t.Resolution) + `</div>`)
+		}
+		sb.WriteString(`</div></div>`)
+	}
+	if t != nil {
+		sb.WriteString(voiceSessionHTML(t))
+	}
 
+	ownerID := ticketOwnerID(channel)
+	anonymous := featuresFor(ticketCategory(channel)).Anonymous
+	messages, history := withMessageHistory(channel, messages)
+	anonymousID := ""
+	if anonymous {
+		anonymousID = ownerID
+	}
+	var events []ticketEvent
+	if t != nil {
+		events = t.Events
+	}
+	var prev *discordgo.Message
 	for _, msg := range messages {
+		for len(events) > 0 && !events[0].At.After(msg.Timestamp) {
+			sb.WriteString(eventLineHTML(events[0]))
+			events = events[1:]
+			prev = nil
+		}
+		if isEventEcho(msg) {
+			continue
+		}
 		if msg.Author.Bot && len(msg.Embeds) > 0 && msg.Embeds[0].Title == "관리자 패널" {
 			continue
 		}
 		var contentBuilder strings.Builder
 		if msg.Content != "" {
-			contentBuilder.WriteString(fmt.Sprintf("<div>%s</div>", html.EscapeString(msg.Content)))
+			contentBuilder.WriteString(fmt.Sprintf("<div>%s</div>", mentionsHTML(msg.Content, anonymousID)))
 		}
 		for _, attachment := range msg.Attachments {
 			if strings.HasPrefix(attachment.ContentType, "image/") {
-				base64Image := imageToBase64(attachment.URL)
-				contentBuilder.WriteString(fmt.Sprintf(`<a href="%s" target="_blank"><img class="attachment-image" src="%s" alt="Attachment"></a>`, attachment.URL, base64Image))
+				contentBuilder.WriteString(fmt.Sprintf(`<a href="%s" target="_blank"><img class="attachment-image" %s alt="Attachment"></a>`, attachment.URL, inlineImage(attachment.URL)))
+			} else {
+				contentBuilder.WriteString(attachmentCardHTML(attachment))
 			}
 		}
 		for _, embed := range msg.Embeds {
@@ -666,13 +1100,11 @@ func generateHTML(channel *discordgo.Channel, messages []*discordgo.Message) str
 			contentBuilder.WriteString(fmt.Sprintf(`<div class="embed" style="border-left-color: %s;">`, borderColor))
 			var thumbnailHTML string
 			if embed.Thumbnail != nil {
-				base64Thumb := imageToBase64(embed.Thumbnail.URL)
-				thumbnailHTML = fmt.Sprintf(`<div class="embed-thumbnail"><img src="%s" alt="Thumbnail"></div>`, base64Thumb)
+				thumbnailHTML = fmt.Sprintf(`<div class="embed-thumbnail"><img %s alt="Thumbnail"></div>`, inlineImage(embed.Thumbnail.URL))
 			}
 			contentBuilder.WriteString(`<div class="embed-content">`)
 			if embed.Author != nil {
-				base64AuthorIcon := imageToBase64(embed.Author.IconURL)
-				contentBuilder.WriteString(fmt.Sprintf(`<div class="embed-author"><img class="embed-author-icon" src="%s"><span class="embed-author-name"><a href="%s" target="_blank">%s</a></span></div>`, base64AuthorIcon, embed.Author.URL, html.EscapeString(embed.Author.Name)))
+				contentBuilder.WriteString(fmt.Sprintf(`<div class="embed-author"><img class="embed-author-icon" %s><span class="embed-author-name"><a href="%s" target="_blank">%s</a></span></div>`, inlineImage(embed.Author.IconURL), embed.Author.URL, html.EscapeString(embed.Author.Name)))
 			}
 			if embed.Title != "" {
 				if embed.URL != "" {
@@ -682,7 +1114,7 @@ func generateHTML(channel *discordgo.Channel, messages []*discordgo.Message) str
 				}
 			}
 			if embed.Description != "" {
-				contentBuilder.WriteString(fmt.Sprintf(`<div class="embed-description">%s</div>`, html.EscapeString(embed.Description)))
+				contentBuilder.WriteString(fmt.Sprintf(`<div class="embed-description">%s</div>`, mentionsHTML(embed.Description, anonymousID)))
 			}
 			if len(embed.Fields) > 0 {
 				contentBuilder.WriteString(`<div class="embed-fields">`)
@@ -691,40 +1123,58 @@ func generateHTML(channel *discordgo.Channel, messages []*discordgo.Message) str
 					if field.Inline {
 						fieldClass += " embed-field-inline"
 					}
-					contentBuilder.WriteString(fmt.Sprintf(`<div class="%s"><div class="embed-field-name">%s</div><div class="embed-field-value">%s</div></div>`, fieldClass, html.EscapeString(field.Name), html.EscapeString(field.Value)))
+					contentBuilder.WriteString(fmt.Sprintf(`<div class="%s"><div class="embed-field-name">%s</div><div class="embed-field-value">%s</div></div>`, fieldClass, html.EscapeString(field.Name), mentionsHTML(field.Value, anonymousID)))
 				}
 				contentBuilder.WriteString(`</div>`)
 			}
 			if embed.Image != nil {
-				base64Image := imageToBase64(embed.Image.URL)
-				contentBuilder.WriteString(fmt.Sprintf(`<div class="embed-image"><a href="%s" target="_blank"><img src="%s" alt="Embed Image"></a></div>`, embed.Image.URL, base64Image))
+				contentBuilder.WriteString(fmt.Sprintf(`<div class="embed-image"><a href="%s" target="_blank"><img %s alt="Embed Image"></a></div>`, embed.Image.URL, inlineImage(embed.Image.URL)))
 			}
 			contentBuilder.WriteString(`</div>`)
 			contentBuilder.WriteString(thumbnailHTML)
 			if embed.Footer != nil {
 				contentBuilder.WriteString(`<div class="embed-footer">`)
 				if embed.Footer.IconURL != "" {
-					base64FooterIcon := imageToBase64(embed.Footer.IconURL)
-					contentBuilder.WriteString(fmt.Sprintf(`<img class="embed-footer-icon" src="%s">`, base64FooterIcon))
+					contentBuilder.WriteString(fmt.Sprintf(`<img class="embed-footer-icon" %s>`, inlineImage(embed.Footer.IconURL)))
 				}
 				contentBuilder.WriteString(fmt.Sprintf(`<span class="embed-footer-text">%s</span></div>`, html.EscapeString(embed.Footer.Text)))
 			}
 			contentBuilder.WriteString(`</div>`)
 		}
-		if contentBuilder.Len() > 0 {
+		if len(msg.Reactions) > 0 && contentBuilder.Len() > 0 {
+			contentBuilder.WriteString(reactionsHTML(msg.Reactions))
+		}
+		body := contentBuilder.String()
+		if h, ok := history[msg.ID]; ok || msg.EditedTimestamp != nil {
+			body = messageHistoryHTML(body, msg, h, anonymousID)
+		}
+		if body != "" && style.Compact && groupsWith(prev, msg) {
+			sb.WriteString(fmt.Sprintf(`<div class="message grouped"><div class="avatar-spacer"></div><div class="message-content"><div class="content">%s</div></div></div>`, body))
+			prev = msg
+		} else if body != "" {
+			prev = msg
 			botTag := ""
 			if msg.Author.Bot {
 				botTag = `<span class="bot-tag">BOT</span>`
 			}
-			sb.WriteString(fmt.Sprintf(`<div class="message"><img class="avatar" src="%s"><div class="message-content"><div class="header"><span class="username">%s</span>%s<span class="timestamp">%s</span></div><div class="content">%s</div></div></div>`,
-				imageToBase64(msg.Author.AvatarURL("")),
-				html.EscapeString(msg.Author.Username),
+			avatar := inlineImage(msg.Author.AvatarURL(""))
+			username := msg.Author.Username
+			if anonymous && msg.Author.ID == ownerID {
+				avatar = `src=""`
+				username = anonymousDisplayName
+			}
+			sb.WriteString(fmt.Sprintf(`<div class="message"><img class="avatar" %s><div class="message-content"><div class="header"><span class="username">%s</span>%s<span class="timestamp">%s</span></div><div class="content">%s</div></div></div>`,
+				avatar,
+				html.EscapeString(username),
 				botTag,
 				msg.Timestamp.In(kstLocation).Format("2006-01-02 15:04:05"),
-				contentBuilder.String(),
+				body,
 			))
 		}
 	}
+	for _, e := range events {
+		sb.WriteString(eventLineHTML(e))
+	}
 	sb.WriteString(`</div></body></html>`)
 	return sb.String()
 }
@@ -740,20 +1190,26 @@ func getUserIDFromTopic(topic string) string {
 	return ""
 }
 
-func closeTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
-	ch, _ := s.Channel(i.ChannelID)
-	if ch.Topic == "" {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "이 명령어는 티켓 채널에서만 사용할 수 있습니다.", Color: colorRed}}}})
-		return
-	}
-	handleCloseRequest(s, i)
-}
-
 func addUserToTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	user := i.ApplicationCommandData().Options[0].UserValue(s)
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	if t.Forum {
+		if err := s.ThreadMemberAdd(t.ChannelID, user.ID); err != nil {
+			respondError(s, i, errAddUserFailed, err)
+			return
+		}
+		if err := updateTicket(t.ChannelID, bson.M{"$addToSet": bson.M{"participants": user.ID}}); err != nil {
+			log.Printf("Error recording ticket participant: %v", err)
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "사용자 추가", Description: fmt.Sprintf("<@%s> 님을 티켓에 추가했습니다.", user.ID), Color: colorGreen}}}})
+		return
+	}
 	ch, err := s.Channel(i.ChannelID)
 	if err != nil {
-		log.Printf("Could not get channel info: %v", err)
+		respondError(s, i, errChannelLookupFailed, err)
 		return
 	}
 	for _, po := range ch.PermissionOverwrites {
@@ -764,24 +1220,32 @@ func addUserToTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			}
 		}
 	}
-	err = s.ChannelPermissionSet(i.ChannelID, user.ID, discordgo.PermissionOverwriteTypeMember, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0)
+	err = retryDiscord("permission set", func() error {
+		return s.ChannelPermissionSet(i.ChannelID, user.ID, discordgo.PermissionOverwriteTypeMember, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0)
+	})
 	if err != nil {
-		log.Printf("Error adding user to ticket: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "티켓에 사용자를 추가하는 데 실패했습니다.", Color: colorRed}}, Flags: discordgo.MessageFlagsEphemeral}})
+		respondError(s, i, errAddUserFailed, err)
 		return
 	}
+	if err := updateTicket(t.ChannelID, bson.M{"$addToSet": bson.M{"participants": user.ID}}); err != nil {
+		log.Printf("Error recording ticket participant: %v", err)
+	}
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "사용자 추가", Description: fmt.Sprintf("<@%s> 님을 티켓에 추가했습니다.", user.ID), Color: colorGreen}}}})
 }
 
 func addRoleToTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	role := i.ApplicationCommandData().Options[0].RoleValue(s, i.GuildID)
-	ch, err := s.Channel(i.ChannelID)
-	if err != nil {
-		log.Printf("Could not get channel info: %v", err)
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	if t.Forum {
+		respondError(s, i, errForumRoleUnsupported, nil)
 		return
 	}
-	if ch.Topic == "" {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "이 명령어는 티켓 채널에서만 사용할 수 있습니다.", Color: colorRed}}, Flags: discordgo.MessageFlagsEphemeral}})
+	ch, err := s.Channel(i.ChannelID)
+	if err != nil {
+		respondError(s, i, errChannelLookupFailed, err)
 		return
 	}
 	for _, po := range ch.PermissionOverwrites {
@@ -792,20 +1256,123 @@ func addRoleToTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
 			}
 		}
 	}
-	err = s.ChannelPermissionSet(i.ChannelID, role.ID, discordgo.PermissionOverwriteTypeRole, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0)
+	err = retryDiscord("permission set", func() error {
+		return s.ChannelPermissionSet(i.ChannelID, role.ID, discordgo.PermissionOverwriteTypeRole, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0)
+	})
 	if err != nil {
-		log.Printf("Error adding role to ticket: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "티켓에 역할을 추가하는 데 실패했습니다.", Color: colorRed}}, Flags: discordgo.MessageFlagsEphemeral}})
+		respondError(s, i, errAddRoleFailed, err)
 		return
 	}
+	if err := updateTicket(t.ChannelID, bson.M{"$addToSet": bson.M{"participant_roles": role.ID}}); err != nil {
+		log.Printf("Error recording ticket participant role: %v", err)
+	}
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "역할 추가", Description: fmt.Sprintf("<@&%s> 역할을 티켓에 추가했습니다.", role.ID), Color: colorGreen}}}})
 }
 
+const maxRoleImportMembers = 50
+
+func addRoleMembersToTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	role := i.ApplicationCommandData().Options[0].RoleValue(s, i.GuildID)
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	members, err := roleMembers(s, i.GuildID, role.ID)
+	if err != nil {
+		respondError(s, i, errAddUserFailed, err)
+		return
+	}
+	if len(members) == 0 {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "추가할 사용자 없음", Description: fmt.Sprintf("<@&%s> 역할을 가진 사용자가 없습니다.", role.ID), Color: colorYellow}}, Flags: discordgo.MessageFlagsEphemeral}})
+		return
+	}
+	if len(members) > maxRoleImportMembers {
+		respondError(s, i, errRoleImportTooLarge, nil, len(members), maxRoleImportMembers)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource})
+	existing := make(map[string]bool)
+	if !t.Forum {
+		if ch, err := s.Channel(i.ChannelID); err == nil {
+			for _, po := range ch.PermissionOverwrites {
+				if po.Type == discordgo.PermissionOverwriteTypeMember && po.Allow&discordgo.PermissionViewChannel == discordgo.PermissionViewChannel {
+					existing[po.ID] = true
+				}
+			}
+		}
+	}
+	var added []string
+	failed, skipped := 0, 0
+	for _, m := range members {
+		if m.User.Bot || existing[m.User.ID] {
+			skipped++
+			continue
+		}
+		if t.Forum {
+			err = s.ThreadMemberAdd(t.ChannelID, m.User.ID)
+		} else {
+			err = retryDiscord("permission set", func() error {
+				return s.ChannelPermissionSet(i.ChannelID, m.User.ID, discordgo.PermissionOverwriteTypeMember, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0)
+			})
+		}
+		if err != nil {
+			log.Printf("Error adding role member %s to ticket '%s': %v", m.User.ID, t.Name(), err)
+			failed++
+			continue
+		}
+		added = append(added, m.User.ID)
+	}
+	if len(added) > 0 {
+		if err := updateTicket(t.ChannelID, bson.M{"$addToSet": bson.M{"participants": bson.M{"$each": added}}}); err != nil {
+			log.Printf("Error recording ticket participants: %v", err)
+		}
+	}
+	description := fmt.Sprintf("<@&%s> 역할의 현재 구성원 %d명을 개별 사용자로 티켓에 추가했습니다.\n역할 구성원이 바뀌어도 이 티켓의 접근 권한은 유지됩니다.", role.ID, len(added))
+	if skipped > 0 {
+		description += fmt.Sprintf("\n이미 참여 중이거나 봇인 %d명은 건너뛰었습니다.", skipped)
+	}
+	if failed > 0 {
+		description += fmt.Sprintf("\n%d명은 추가하지 못했습니다.", failed)
+	}
+	embeds := []*discordgo.MessageEmbed{{Title: "역할 구성원 추가", Description: description, Color: colorGreen}}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+}
+
+func roleMembers(s *discordgo.Session, guildID, roleID string) ([]*discordgo.Member, error) {
+	var matched []*discordgo.Member
+	after := ""
+	for {
+		page, err := s.GuildMembers(guildID, after, 1000)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range page {
+			if containsID(m.Roles, roleID) {
+				matched = append(matched, m)
+			}
+		}
+		if len(page) < 1000 {
+			return matched, nil
+		}
+		after = page[len(page)-1].User.ID
+	}
+}
+
+func hasSupportRole(member *discordgo.Member) bool {
+	for _, roleID := range member.Roles {
+		if isConfiguredSupportRole(roleID) {
+			return true
+		}
+	}
+	return false
+}
+
 func isConfiguredSupportRole(roleID string) bool {
-	if roleID == defaultSupportRoleID {
+	cfg := getConfig()
+	if roleID == cfg.DefaultSupportRoleID {
 		return true
 	}
-	for _, id := range categorySupportRoles {
+	for _, id := range cfg.CategorySupportRoles {
 		if id == roleID {
 			return true
 		}
@@ -815,28 +1382,43 @@ func isConfiguredSupportRole(roleID string) bool {
 
 func removeUserFromTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	user := i.ApplicationCommandData().Options[0].UserValue(s)
-	err := s.ChannelPermissionDelete(i.ChannelID, user.ID)
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	var err error
+	if t.Forum {
+		err = s.ThreadMemberRemove(t.ChannelID, user.ID)
+	} else {
+		err = retryDiscord("permission delete", func() error { return s.ChannelPermissionDelete(i.ChannelID, user.ID) })
+	}
 	if err != nil {
-		log.Printf("Error removing user from ticket: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "티켓에서 사용자를 제거하는 데 실패했습니다.", Color: colorRed}}, Flags: discordgo.MessageFlagsEphemeral}})
+		respondError(s, i, errRemoveUserFailed, err)
 		return
 	}
+	if err := updateTicket(t.ChannelID, bson.M{"$pull": bson.M{"participants": user.ID}}); err != nil {
+		log.Printf("Error recording ticket participant removal: %v", err)
+	}
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "사용자 제거", Description: fmt.Sprintf("<@%s> 님을 티켓에서 제거했습니다.", user.ID), Color: colorYellow}}}})
 }
 
 func removeRoleFromTicket(s *discordgo.Session, i *discordgo.InteractionCreate) {
 	role := i.ApplicationCommandData().Options[0].RoleValue(s, i.GuildID)
-	ch, err := s.Channel(i.ChannelID)
-	if err != nil {
-		log.Printf("Could not get channel info: %v", err)
+	t := requireTicket(s, i)
+	if t == nil {
 		return
 	}
-	if ch.Topic == "" {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "이 명령어는 티켓 채널에서만 사용할 수 있습니다.", Color: colorRed}}, Flags: discordgo.MessageFlagsEphemeral}})
+	if t.Forum {
+		respondError(s, i, errForumRoleUnsupported, nil)
+		return
+	}
+	ch, err := s.Channel(i.ChannelID)
+	if err != nil {
+		respondError(s, i, errChannelLookupFailed, err)
 		return
 	}
 	if isConfiguredSupportRole(role.ID) {
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "제거 불가", Description: "기본 지원 역할은 티켓에서 제거할 수 없습니다.", Color: colorRed}}, Flags: discordgo.MessageFlagsEphemeral}})
+		respondError(s, i, errSupportRoleRemoval, nil)
 		return
 	}
 	hasPermissions := false
@@ -850,11 +1432,13 @@ func removeRoleFromTicket(s *discordgo.Session, i *discordgo.InteractionCreate)
 		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "역할 없음", Description: fmt.Sprintf("<@&%s> 역할은 이미 이 티켓에 참여하고 있습니다.", role.ID), Color: colorYellow}}, Flags: discordgo.MessageFlagsEphemeral}})
 		return
 	}
-	err = s.ChannelPermissionDelete(i.ChannelID, role.ID)
+	err = retryDiscord("permission delete", func() error { return s.ChannelPermissionDelete(i.ChannelID, role.ID) })
 	if err != nil {
-		log.Printf("Error removing role from ticket: %v", err)
-		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "오류", Description: "티켓에서 역할을 제거하는 데 실패했습니다.", Color: colorRed}}, Flags: discordgo.MessageFlagsEphemeral}})
+		respondError(s, i, errRemoveRoleFailed, err)
 		return
 	}
+	if err := updateTicket(t.ChannelID, bson.M{"$pull": bson.M{"participant_roles": role.ID}}); err != nil {
+		log.Printf("Error recording ticket participant role removal: %v", err)
+	}
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "역할 제거", Description: fmt.Sprintf("<@&%s> 역할을 티켓에서 제거했습니다.", role.ID), Color: colorYellow}}}})
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const mentionCacheTTL = time.Hour
+
+var mentionPattern = regexp.MustCompile(`<(@!?|@&|#)(\d+)>`)
+
+type cachedMention struct {
+	name    string
+	expires time.Time
+}
+
+var (
+	mentionCacheMu sync.Mutex
+	mentionCache   = make(map[string]cachedMention)
+)
+
+func mentionName(kind, id string) (string, bool) {
+	key := kind + id
+	mentionCacheMu.Lock()
+	if c, ok := mentionCache[key]; ok && time.Now().Before(c.expires) {
+		mentionCacheMu.Unlock()
+		return c.name, true
+	}
+	mentionCacheMu.Unlock()
+	s := app().Session
+	if s == nil {
+		return "", false
+	}
+	var name string
+	switch kind {
+	case "@&":
+		if role, err := s.State.Role(app().GuildID, id); err == nil {
+			name = role.Name
+		} else if roles, err := s.GuildRoles(app().GuildID); err == nil {
+			for _, role := range roles {
+				if role.ID == id {
+					name = role.Name
+				}
+			}
+		}
+	case "#":
+		if ch, err := s.State.Channel(id); err == nil {
+			name = ch.Name
+		} else if ch, err := s.Channel(id); err == nil {
+			name = ch.Name
+		}
+	default:
+		if m, err := s.State.Member(app().GuildID, id); err == nil {
+			name = memberDisplayName(m)
+		} else if m, err := s.GuildMember(app().GuildID, id); err == nil {
+			name = memberDisplayName(m)
+		} else if u, err := s.User(id); err == nil {
+			name = u.Username
+		}
+	}
+	if name == "" {
+		return "", false
+	}
+	mentionCacheMu.Lock()
+	mentionCache[key] = cachedMention{name: name, expires: time.Now().Add(mentionCacheTTL)}
+	mentionCacheMu.Unlock()
+	return name, true
+}
+
+func mentionsHTML(text, anonymousID string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		sb.WriteString(html.EscapeString(text[last:loc[0]]))
+		last = loc[1]
+		kind, id := text[loc[2]:loc[3]], text[loc[4]:loc[5]]
+		if kind == "@!" {
+			kind = "@"
+		}
+		prefix := "@"
+		if kind == "#" {
+			prefix = "#"
+		}
+		name, ok := mentionName(kind, id)
+		if kind == "@" && id == anonymousID {
+			name, ok = anonymousDisplayName, true
+		}
+		if !ok {
+			sb.WriteString(html.EscapeString(text[loc[0]:loc[1]]))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf(`<span class="mention">%s%s</span>`, prefix, html.EscapeString(name)))
+	}
+	sb.WriteString(html.EscapeString(text[last:]))
+	return sb.String()
+}
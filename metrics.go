@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+var (
+	metricsMu  sync.Mutex
+	counters   = make(map[string]map[string]float64)
+	histograms = make(map[string]map[string]*histogram)
+)
+
+var (
+	interactionLatencyBuckets = []float64{0.1, 0.25, 0.5, 1, 1.5, 2.5, 3, 5}
+	mongoDurationBuckets      = []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+	transcriptSizeBuckets     = []float64{10_000, 50_000, 100_000, 500_000, 1_000_000, 5_000_000, 15_000_000}
+)
+
+var metricHelp = map[string]string{
+	"potatobot_tickets_opened_total":         "Tickets opened per category.",
+	"potatobot_tickets_closed_total":         "Tickets closed per category.",
+	"potatobot_tickets_quarantined_total":    "Tickets held for spam review per category.",
+	"potatobot_ticket_rollbacks_total":       "Tickets rolled back because the greeting message could not be sent.",
+	"potatobot_component_rate_limited_total": "Component clicks rejected by the per-user rate limiter.",
+	"potatobot_external_alerts_total":        "External alert deliveries by sink and result.",
+	"potatobot_ticket_events_total":          "Ticket lifecycle events sent to the message broker by result.",
+	"potatobot_discord_api_errors_total":     "Discord API requests that failed or returned an error status.",
+	"potatobot_discord_api_retries_total":    "Discord API calls retried after a transient failure.",
+	"potatobot_interaction_latency_seconds":  "Time from interaction receipt to first response.",
+	"potatobot_mongo_operation_seconds":      "Duration of MongoDB commands.",
+	"potatobot_interaction_panics_total":     "Interaction handlers that panicked and were recovered.",
+	"potatobot_transcript_size_bytes":        "Size of generated HTML transcripts.",
+}
+
+type histogram struct {
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+func metricLabel(name, value string) string {
+	return fmt.Sprintf("%s=%q", name, value)
+}
+
+func incCounter(name, labels string) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if counters[name] == nil {
+		counters[name] = make(map[string]float64)
+	}
+	counters[name][labels]++
+}
+
+func observeHistogram(name, labels string, buckets []float64, v float64) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if histograms[name] == nil {
+		histograms[name] = make(map[string]*histogram)
+	}
+	h, ok := histograms[name][labels]
+	if !ok {
+		h = &histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+		histograms[name][labels] = h
+	}
+	for n, upper := range h.buckets {
+		if v <= upper {
+			h.counts[n]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+func mongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(_ context.Context, e *event.CommandSucceededEvent) {
+			observeHistogram("potatobot_mongo_operation_seconds", metricLabel("command", e.CommandName)+","+metricLabel("outcome", "success"), mongoDurationBuckets, e.Duration.Seconds())
+		},
+		Failed: func(_ context.Context, e *event.CommandFailedEvent) {
+			observeHistogram("potatobot_mongo_operation_seconds", metricLabel("command", e.CommandName)+","+metricLabel("outcome", "failure"), mongoDurationBuckets, e.Duration.Seconds())
+			reportError("mongo", fmt.Sprintf("%s failed: %s", e.CommandName, e.Failure), "", map[string]string{"command": e.CommandName, "database": e.DatabaseName})
+		},
+	}
+}
+
+func writeMetrics(w io.Writer) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	for _, name := range sortedKeys(counters) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, metricHelp[name], name)
+		for _, labels := range sortedKeys(counters[name]) {
+			fmt.Fprintf(w, "%s{%s} %g\n", name, labels, counters[name][labels])
+		}
+	}
+	for _, name := range sortedKeys(histograms) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, metricHelp[name], name)
+		for _, labels := range sortedKeys(histograms[name]) {
+			h := histograms[name][labels]
+			prefix := labels
+			if prefix != "" {
+				prefix += ","
+			}
+			for n, upper := range h.buckets {
+				fmt.Fprintf(w, "%s_bucket{%sle=\"%g\"} %d\n", name, prefix, upper, h.counts[n])
+			}
+			fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, prefix, h.count)
+			fmt.Fprintf(w, "%s_sum{%s} %g\n", name, labels, h.sum)
+			fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.count)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	defaultDigestThreshold = 5
+	digestWindow           = time.Minute
+	maxDigestLines         = 20
+)
+
+var (
+	notifyMu        sync.Mutex
+	notifyQueues    = make(map[string]*notificationQueue)
+	digestThreshold = loadDigestThreshold()
+)
+
+type notificationQueue struct {
+	windowStart time.Time
+	sent        int
+	pending     []*discordgo.MessageEmbed
+	timer       *time.Timer
+}
+
+func loadDigestThreshold() int {
+	v := os.Getenv("NOTIFY_DIGEST_THRESHOLD")
+	if v == "" {
+		return defaultDigestThreshold
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		log.Printf("Invalid NOTIFY_DIGEST_THRESHOLD '%s': %v", v, err)
+		return defaultDigestThreshold
+	}
+	return n
+}
+
+func notifyChannel(s *discordgo.Session, channelID string, embed *discordgo.MessageEmbed) {
+	if channelID == "" {
+		return
+	}
+	notifyMu.Lock()
+	defer notifyMu.Unlock()
+	q, ok := notifyQueues[channelID]
+	if !ok {
+		q = &notificationQueue{}
+		notifyQueues[channelID] = q
+	}
+	now := time.Now()
+	if now.Sub(q.windowStart) >= digestWindow && len(q.pending) == 0 {
+		q.windowStart = now
+		q.sent = 0
+	}
+	if q.sent < digestThreshold && len(q.pending) == 0 {
+		q.sent++
+		go func() {
+			if _, err := s.ChannelMessageSendEmbed(channelID, embed); err != nil {
+				log.Printf("Could not send notification to %s: %v", channelID, err)
+			}
+		}()
+		return
+	}
+	q.pending = append(q.pending, embed)
+	if q.timer == nil {
+		q.timer = time.AfterFunc(time.Until(q.windowStart.Add(digestWindow)), func() { flushNotificationQueue(s, channelID) })
+	}
+}
+
+func notifyUser(s *discordgo.Session, userID string, embed *discordgo.MessageEmbed) {
+	dm, err := s.UserChannelCreate(userID)
+	if err != nil {
+		log.Printf("Could not open DM for notification to %s: %v", userID, err)
+		return
+	}
+	notifyChannel(s, dm.ID, embed)
+}
+
+func flushNotificationQueue(s *discordgo.Session, channelID string) {
+	notifyMu.Lock()
+	q := notifyQueues[channelID]
+	pending := q.pending
+	q.pending = nil
+	q.timer = nil
+	q.windowStart = time.Now()
+	q.sent = 1
+	notifyMu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+	embed := pending[0]
+	if len(pending) > 1 {
+		embed = digestEmbed(pending)
+	}
+	if _, err := s.ChannelMessageSendEmbed(channelID, embed); err != nil {
+		log.Printf("Could not send notification digest to %s: %v", channelID, err)
+	}
+}
+
+func flushNotifications(s *discordgo.Session) {
+	notifyMu.Lock()
+	var channels []string
+	for channelID, q := range notifyQueues {
+		if q.timer != nil {
+			q.timer.Stop()
+			channels = append(channels, channelID)
+		}
+	}
+	notifyMu.Unlock()
+	for _, channelID := range channels {
+		flushNotificationQueue(s, channelID)
+	}
+}
+
+func digestEmbed(pending []*discordgo.MessageEmbed) *discordgo.MessageEmbed {
+	var lines []string
+	color := colorBlue
+	for n, e := range pending {
+		if e.Color == colorRed {
+			color = colorRed
+		}
+		if n >= maxDigestLines {
+			continue
+		}
+		line := "• **" + e.Title + "**"
+		if e.Description != "" {
+			line += " " + strings.ReplaceAll(e.Description, "\n", " ")
+		}
+		if len([]rune(line)) > 180 {
+			line = string([]rune(line)[:180]) + "…"
+		}
+		lines = append(lines, line)
+	}
+	if len(pending) > maxDigestLines {
+		lines = append(lines, fmt.Sprintf("…외 %d건", len(pending)-maxDigestLines))
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("알림 요약 (%d건)", len(pending)),
+		Description: strings.Join(lines, "\n"),
+		Color:       color,
+		Footer:      &discordgo.MessageEmbedFooter{Text: "짧은 시간에 알림이 많아 한 번에 모아 보냈습니다."},
+		Timestamp:   time.Now().In(kstLocation).Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func openTicketsOwnedBy(userID string) ([]ticket, error) {
+	filter := bson.M{"owner_id": userID, "guild_id": app().GuildID, "status": ticketStatusOpen}
+	cursor, err := app().Tickets.Find(context.TODO(), filter, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+func checkOpenTicketLimit(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	limit := getConfig().MaxOpenTickets
+	if limit <= 0 || hasSupportRole(i.Member) {
+		return true
+	}
+	tickets, err := openTicketsOwnedBy(i.Member.User.ID)
+	if err != nil {
+		log.Printf("Could not count open tickets of %s: %v", i.Member.User.ID, err)
+		return true
+	}
+	if len(tickets) < limit {
+		return true
+	}
+	logError(i, errOpenTicketLimit, nil, limit)
+	lines := make([]string, len(tickets))
+	for n, t := range tickets {
+		lines[n] = fmt.Sprintf("<#%s> · %s", t.ChannelID, ticketStatusLabel(t))
+	}
+	embed := errOpenTicketLimit.embed(limit)
+	embed.Fields = []*discordgo.MessageEmbedField{{Name: "열린 티켓", Value: strings.Join(lines, "\n")}}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+	return false
+}
+
+func openTicketLimitLabel(limit int) string {
+	if limit <= 0 {
+		return "제한 없음"
+	}
+	return fmt.Sprintf("1인당 %d개", limit)
+}
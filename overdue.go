@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxOverdueTickets = 15
+
+func (t *ticket) openedAt() time.Time {
+	if !t.ReopenedAt.IsZero() {
+		return t.ReopenedAt
+	}
+	return t.CreatedAt
+}
+
+func handleOverdueTickets(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	filter := bson.M{"status": ticketStatusOpen}
+	if len(i.ApplicationCommandData().Options) > 0 {
+		filter["category"] = i.ApplicationCommandData().Options[0].StringValue()
+	}
+	cursor, err := app().Tickets.Find(context.TODO(), filter, options.Find().SetSort(bson.M{"created_at": 1}).SetLimit(maxOverdueTickets))
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	total, _ := app().Tickets.CountDocuments(context.TODO(), filter)
+	now := time.Now()
+	var lines []string
+	for _, t := range tickets {
+		line := fmt.Sprintf("<#%s> · %s 경과", t.ChannelID, formatWait(now.Sub(t.CreatedAt)))
+		if t.AssigneeID != "" {
+			line += fmt.Sprintf(" · <@%s>", t.AssigneeID)
+		} else {
+			line += " · 미배정"
+		}
+		if t.ReopenCount > 0 {
+			line += fmt.Sprintf(" · ♻️ 재오픈 %d회", t.ReopenCount)
+		}
+		if ticketSLABreached(&t, now) {
+			line += " · ⚠️ 기한 초과"
+		}
+		lines = append(lines, line)
+	}
+	embed := &discordgo.MessageEmbed{Title: "지연 티켓", Description: strings.Join(lines, "\n"), Color: colorYellow, Footer: &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("열린 티켓 %d개 중 오래된 순 %d개", total, len(tickets))}}
+	if len(tickets) == 0 {
+		embed.Description = "열려 있는 티켓이 없습니다."
+		embed.Color = colorGreen
+		embed.Footer = nil
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const maxPinnedInfo = 5
+
+type pinnedInfo struct {
+	Title   string `bson:"title"`
+	Content string `bson:"content"`
+}
+
+func ticketPinSettingsGroup() *discordgo.ApplicationCommandOption {
+	topicOption := &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: true, Choices: ticketTopicChoices()}
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Name:        "안내",
+		Description: "티켓 생성 직후 자동으로 게시하고 고정할 창구별 안내문을 관리합니다.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "보기", Description: "창구의 고정 안내문을 확인합니다.", Options: []*discordgo.ApplicationCommandOption{topicOption}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "추가", Description: "고정 안내문을 추가합니다.", Options: []*discordgo.ApplicationCommandOption{
+				topicOption,
+				{Type: discordgo.ApplicationCommandOptionString, Name: "title", Description: "안내문 제목 (예: 필요 서류)", Required: true, MaxLength: 100},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "content", Description: "안내문 내용 (\\n으로 줄바꿈)", Required: true, MaxLength: 4000},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "삭제", Description: "고정 안내문을 삭제합니다.", Options: []*discordgo.ApplicationCommandOption{
+				topicOption,
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "number", Description: "/설정 안내 보기에 표시된 번호", Required: true},
+			}},
+		},
+	}
+}
+
+func handleTicketPinSettings(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range sub.Options {
+		options[opt.Name] = opt
+	}
+	topic := options["topic"].StringValue()
+	pins := getConfig().CategoryPins[topic]
+	var summary string
+	var updated []pinnedInfo
+	switch sub.Name {
+	case "보기":
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{pinnedInfoEmbed(topic, pins)}}})
+		return
+	case "추가":
+		if len(pins) >= maxPinnedInfo {
+			respondError(s, i, errPinnedInfoLimit, nil, maxPinnedInfo)
+			return
+		}
+		pin := pinnedInfo{Title: strings.TrimSpace(options["title"].StringValue()), Content: strings.ReplaceAll(options["content"].StringValue(), `\n`, "\n")}
+		updated = append(append([]pinnedInfo(nil), pins...), pin)
+		summary = fmt.Sprintf("%s 창구에 '%s' 안내문을 추가했습니다.", topic, pin.Title)
+	case "삭제":
+		n := int(options["number"].IntValue())
+		if n < 1 || n > len(pins) {
+			respondError(s, i, errPinnedInfoNotFound, nil, n)
+			return
+		}
+		updated = append(updated, pins[:n-1]...)
+		updated = append(updated, pins[n:]...)
+		summary = fmt.Sprintf("%s 창구에서 '%s' 안내문을 삭제했습니다.", topic, pins[n-1].Title)
+	}
+	err := updateConfig(func(cfg *guildConfig) {
+		if len(updated) == 0 {
+			delete(cfg.CategoryPins, topic)
+		} else {
+			cfg.CategoryPins[topic] = updated
+		}
+	})
+	if err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "설정 변경", Description: summary + "\n변경 사항은 다음 접수부터 적용됩니다.", Color: colorGreen}, pinnedInfoEmbed(topic, updated)}}})
+}
+
+func pinnedInfoEmbed(topic string, pins []pinnedInfo) *discordgo.MessageEmbed {
+	var lines []string
+	for n, pin := range pins {
+		lines = append(lines, fmt.Sprintf("**%d.** %s (%d자)", n+1, pin.Title, len([]rune(pin.Content))))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, "등록된 안내문이 없습니다.")
+	}
+	return &discordgo.MessageEmbed{Title: topic + " 고정 안내문", Description: strings.Join(lines, "\n"), Color: colorBlue}
+}
+
+func postPinnedInfo(s *discordgo.Session, t *ticket) {
+	for _, pin := range getConfig().CategoryPins[t.Category] {
+		msg, err := s.ChannelMessageSendEmbed(t.ChannelID, &discordgo.MessageEmbed{Title: "📌 " + pin.Title, Description: pin.Content, Color: colorGray})
+		if err != nil {
+			log.Printf("Could not post pinned info '%s' in '%s': %v", pin.Title, t.Name(), err)
+			continue
+		}
+		if err := s.ChannelMessagePin(t.ChannelID, msg.ID); err != nil {
+			log.Printf("Could not pin info '%s' in '%s': %v", pin.Title, t.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var presenceLabels = map[discordgo.Status]string{
+	discordgo.StatusOnline:       "🟢 온라인",
+	discordgo.StatusIdle:         "🌙 자리 비움",
+	discordgo.StatusDoNotDisturb: "⛔ 방해 금지",
+	discordgo.StatusInvisible:    "⚫ 오프라인",
+	discordgo.StatusOffline:      "⚫ 오프라인",
+}
+
+func recordOwnerActivity(m *discordgo.MessageCreate, t *ticket) {
+	if m.Author.ID != t.OwnerID {
+		return
+	}
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"owner_active_at": m.Timestamp}}); err != nil {
+		log.Printf("Could not record requester activity for '%s': %v", t.Name(), err)
+	}
+}
+
+func ownerPresenceField(s *discordgo.Session, t *ticket) *discordgo.MessageEmbedField {
+	status := "알 수 없음 (접속 상태 권한 없음)"
+	if p, err := s.State.Presence(t.GuildID, t.OwnerID); err == nil {
+		if label, ok := presenceLabels[p.Status]; ok {
+			status = label
+		}
+	} else if s.State.TrackPresences && s.Identify.Intents&discordgo.IntentsGuildPresences != 0 {
+		status = presenceLabels[discordgo.StatusOffline]
+	}
+	lastSeen := "티켓에서 아직 메시지를 보내지 않았습니다."
+	if !t.OwnerActiveAt.IsZero() {
+		lastSeen = fmt.Sprintf("티켓 마지막 활동: <t:%d:R>", t.OwnerActiveAt.Unix())
+	}
+	return &discordgo.MessageEmbedField{Name: "민원인 접속 상태", Value: status + "\n" + lastSeen, Inline: false}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	defaultInstitutionName = "강원특별자치도청"
+	defaultGreeting        = "안녕하세요, {user}님! 문의주셔서 감사합니다.\n곧 담당자가 도착할 예정입니다. 잠시만 기다려주십시오."
+)
+
+type ticketTopic struct {
+	Value       string `bson:"value"`
+	Label       string `bson:"label"`
+	Description string `bson:"description,omitempty"`
+	Emoji       string `bson:"emoji,omitempty"`
+}
+
+type topicPreset struct {
+	Topic      ticketTopic
+	Features   categoryFeatures
+	SLA        categorySLA
+	Inactivity inactivityPolicy
+}
+
+type configPreset struct {
+	Label         string
+	Institution   string
+	Greeting      string
+	RetentionDays int
+	Topics        []topicPreset
+}
+
+var configPresetOrder = []string{"도청", "시군청", "읍면동"}
+
+var configPresets = map[string]configPreset{
+	"도청": {
+		Label:         "도청 (광역자치단체)",
+		Institution:   defaultInstitutionName,
+		Greeting:      defaultGreeting,
+		RetentionDays: 90,
+		Topics: []topicPreset{
+			{Topic: ticketTopic{Value: "일반민원", Label: "일반민원", Description: "행정민원, 파산신고, 사업신청은 해당 창구로 문의 바랍니다.", Emoji: "📄"}, Features: categoryFeatures{Transcripts: true, CSAT: true}, SLA: categorySLA{FirstResponse: 4 * time.Hour, Resolution: 7 * 24 * time.Hour}, Inactivity: inactivityPolicy{WarnAfter: 72 * time.Hour, CloseAfter: 48 * time.Hour}},
+			{Topic: ticketTopic{Value: "법률구조", Label: "법률구조", Description: "법률상담은 해당 창구로 문의 바랍니다.", Emoji: "⚖️"}, Features: categoryFeatures{Transcripts: true, CSAT: true}, SLA: categorySLA{FirstResponse: 24 * time.Hour, Resolution: 14 * 24 * time.Hour}},
+			{Topic: ticketTopic{Value: "부패신고", Label: "부패신고", Description: "공익신고, 금융신고는 해당 창구로 문의 바랍니다.", Emoji: "🗑️"}, Features: categoryFeatures{Anonymous: true}, SLA: categorySLA{FirstResponse: 24 * time.Hour, Resolution: 30 * 24 * time.Hour}},
+		},
+	},
+	"시군청": {
+		Label:         "시·군청 (기초자치단체)",
+		Institution:   "시청",
+		Greeting:      "안녕하세요, {user}님! 민원을 접수해주셔서 감사합니다.\n담당 부서에서 확인 후 답변드리겠습니다.",
+		RetentionDays: 60,
+		Topics: []topicPreset{
+			{Topic: ticketTopic{Value: "일반민원", Label: "일반민원", Description: "행정 절차, 제도 안내 등 일반 문의", Emoji: "📄"}, Features: categoryFeatures{Transcripts: true, CSAT: true}, SLA: categorySLA{FirstResponse: 4 * time.Hour, Resolution: 7 * 24 * time.Hour}, Inactivity: inactivityPolicy{WarnAfter: 72 * time.Hour, CloseAfter: 48 * time.Hour}},
+			{Topic: ticketTopic{Value: "생활불편", Label: "생활불편", Description: "도로 파손, 가로등 고장, 불법 주정차 등 생활 불편 신고", Emoji: "🚧"}, Features: categoryFeatures{Transcripts: true, CSAT: true, AutoAssign: true}, SLA: categorySLA{FirstResponse: 2 * time.Hour, Resolution: 3 * 24 * time.Hour}, Inactivity: inactivityPolicy{WarnAfter: 48 * time.Hour, CloseAfter: 24 * time.Hour}},
+			{Topic: ticketTopic{Value: "인허가", Label: "인허가", Description: "건축, 영업, 옥외광고 등 인허가 관련 문의", Emoji: "🏗️"}, Features: categoryFeatures{Transcripts: true, CSAT: true}, SLA: categorySLA{FirstResponse: 24 * time.Hour, Resolution: 14 * 24 * time.Hour}},
+			{Topic: ticketTopic{Value: "부패신고", Label: "부패신고", Description: "공익신고, 공직자 비위 신고", Emoji: "🗑️"}, Features: categoryFeatures{Anonymous: true}, SLA: categorySLA{FirstResponse: 24 * time.Hour, Resolution: 30 * 24 * time.Hour}},
+		},
+	},
+	"읍면동": {
+		Label:         "읍·면·동 행정복지센터",
+		Institution:   "행정복지센터",
+		Greeting:      "안녕하세요, {user}님! 행정복지센터입니다.\n문의 내용을 확인하고 곧 안내드리겠습니다.",
+		RetentionDays: 30,
+		Topics: []topicPreset{
+			{Topic: ticketTopic{Value: "제증명", Label: "제증명", Description: "주민등록, 가족관계, 인감 등 증명서 발급 문의", Emoji: "📑"}, Features: categoryFeatures{Transcripts: true, CSAT: true, AutoAssign: true}, SLA: categorySLA{FirstResponse: time.Hour, Resolution: 24 * time.Hour}, Inactivity: inactivityPolicy{WarnAfter: 24 * time.Hour, CloseAfter: 24 * time.Hour}},
+			{Topic: ticketTopic{Value: "복지상담", Label: "복지상담", Description: "기초생활보장, 긴급복지, 돌봄 서비스 상담", Emoji: "🤝"}, Features: categoryFeatures{Transcripts: true, CSAT: true}, SLA: categorySLA{FirstResponse: 4 * time.Hour, Resolution: 7 * 24 * time.Hour}},
+			{Topic: ticketTopic{Value: "일반민원", Label: "일반민원", Description: "전입신고, 쓰레기 배출 등 생활 민원", Emoji: "📄"}, Features: categoryFeatures{Transcripts: true, CSAT: true}, SLA: categorySLA{FirstResponse: 4 * time.Hour, Resolution: 3 * 24 * time.Hour}, Inactivity: inactivityPolicy{WarnAfter: 48 * time.Hour, CloseAfter: 24 * time.Hour}},
+		},
+	},
+}
+
+func ticketOptions() []discordgo.SelectMenuOption {
+	topics := getConfig().Topics
+	if len(topics) == 0 {
+		return defaultTicketOptions
+	}
+	options := make([]discordgo.SelectMenuOption, 0, len(topics))
+	for _, t := range topics {
+		option := discordgo.SelectMenuOption{Label: t.Label, Value: t.Value, Description: t.Description}
+		if t.Emoji != "" {
+			option.Emoji = &discordgo.ComponentEmoji{Name: t.Emoji}
+		}
+		options = append(options, option)
+	}
+	return options
+}
+
+func institutionName() string {
+	if name := getConfig().InstitutionName; name != "" {
+		return name
+	}
+	return defaultInstitutionName
+}
+
+func ticketGreeting(userID string) string {
+	greeting := getConfig().Greeting
+	if greeting == "" {
+		greeting = defaultGreeting
+	}
+	return strings.ReplaceAll(greeting, "{user}", fmt.Sprintf("<@%s>", userID))
+}
+
+func presetCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, key := range configPresetOrder {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: configPresets[key].Label, Value: key})
+	}
+	return &discordgo.ApplicationCommand{
+		Name:                     "초기설정",
+		Description:              "기관 유형에 맞는 민원 창구, 인사말, SLA, 보관 기간 기본값을 한 번에 적용합니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "preset", Description: "기관 유형", Required: true, Choices: choices},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "기관명 (예: 춘천시청, 기본: 기관 유형 이름)", Required: false, MaxLength: 50},
+		},
+	}
+}
+
+func handlePreset(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	key := options["preset"].StringValue()
+	preset := configPresets[key]
+	name := preset.Institution
+	if opt, ok := options["name"]; ok && strings.TrimSpace(opt.StringValue()) != "" {
+		name = strings.TrimSpace(opt.StringValue())
+	}
+	values := make([]string, len(preset.Topics))
+	for n, tp := range preset.Topics {
+		values[n] = tp.Topic.Value
+	}
+	orphaned, err := app().Tickets.Distinct(context.TODO(), "category", bson.M{"status": ticketStatusOpen, "category": bson.M{"$nin": values}})
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	if len(orphaned) > 0 {
+		var topics []string
+		for _, v := range orphaned {
+			topics = append(topics, fmt.Sprint(v))
+		}
+		respondError(s, i, errPresetOpenTickets, nil, strings.Join(topics, ", "))
+		return
+	}
+	err = updateConfig(func(cfg *guildConfig) {
+		cfg.Preset = key
+		cfg.InstitutionName = name
+		cfg.Greeting = preset.Greeting
+		cfg.ClosedRetentionDays = preset.RetentionDays
+		cfg.Topics = nil
+		for _, tp := range preset.Topics {
+			cfg.Topics = append(cfg.Topics, tp.Topic)
+			cfg.CategoryFeatures[tp.Topic.Value] = tp.Features
+			cfg.CategorySLAs[tp.Topic.Value] = tp.SLA
+			cfg.CategoryInactivity[tp.Topic.Value] = tp.Inactivity
+		}
+	})
+	if err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	log.Printf("Applied '%s' preset as '%s' by %s.", key, name, i.Member.User.ID)
+	var lines []string
+	for _, tp := range preset.Topics {
+		lines = append(lines, fmt.Sprintf("%s %s: 첫 응답 %s · 해결 %s", tp.Topic.Emoji, tp.Topic.Label, formatSLADuration(tp.SLA.FirstResponse), formatSLADuration(tp.SLA.Resolution)))
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{
+		Title:       fmt.Sprintf("%s 기본값 적용 완료", preset.Label),
+		Description: fmt.Sprintf("**%s** 민원창구로 설정했습니다. 창구 선택지는 명령어 갱신 후 반영되며, /패널로 패널을 다시 보내주세요.\n세부 항목은 /설정에서 언제든 바꿀 수 있습니다.", name),
+		Color:       colorGreen,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "민원 창구", Value: strings.Join(lines, "\n"), Inline: false},
+			{Name: "닫힌 티켓 보관", Value: fmt.Sprintf("%d일", preset.RetentionDays), Inline: true},
+			{Name: "지원 역할", Value: "새 창구는 기본 지원 역할을 사용합니다. /설정 지원역할로 창구별로 지정하세요.", Inline: false},
+		},
+	}}}})
+	go registerCommands(s)
+}
+
+func institutionLabel(cfg guildConfig) string {
+	name := cfg.InstitutionName
+	if name == "" {
+		name = defaultInstitutionName
+	}
+	if preset, ok := configPresets[cfg.Preset]; ok {
+		return fmt.Sprintf("%s (%s 기본값)", name, preset.Label)
+	}
+	return name
+}
+
+func retentionLabel(days int) string {
+	if days <= 0 {
+		return fmt.Sprintf("%d일 (환경 변수 기본값)", int(closedRetention().Hours()/24))
+	}
+	return fmt.Sprintf("%d일", days)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	openForUserCommandName = "티켓 열기"
+	proxyTopicPrefix       = "proxy_ticket_topic:"
+)
+
+func handleOpenTicketFor(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	target, ok := data.Resolved.Users[data.TargetID]
+	if _, isMember := data.Resolved.Members[data.TargetID]; !ok || !isMember || target.Bot {
+		respondError(s, i, errProxyTargetInvalid, nil)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{
+		Flags:  discordgo.MessageFlagsEphemeral,
+		Embeds: []*discordgo.MessageEmbed{{Title: "대신 티켓 열기", Description: "<@" + target.ID + "> 님의 이름으로 열 민원 창구를 선택해주세요.\n민원인에게는 티켓 채널에서 멘션으로 알림이 갑니다.", Color: colorBlue}},
+		Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+			discordgo.SelectMenu{CustomID: proxyTopicPrefix + target.ID, Placeholder: "민원 창구 선택", Options: ticketOptions()},
+		}}},
+	}})
+}
+
+func handleProxyTopicSelect(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.MessageComponentData()
+	ownerID := strings.TrimPrefix(data.CustomID, proxyTopicPrefix)
+	topicValue := data.Values[0]
+	member, err := s.State.Member(i.GuildID, ownerID)
+	if err != nil {
+		member, err = s.GuildMember(i.GuildID, ownerID)
+	}
+	if err != nil || member.User.Bot {
+		respondError(s, i, errProxyTargetInvalid, nil)
+		return
+	}
+	log.Printf("%s is opening a '%s' ticket on behalf of %s.", i.Member.User.ID, topicValue, ownerID)
+	createTicketChannel(s, i, ownerID, topicValue, nil)
+}
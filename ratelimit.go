@@ -0,0 +1,75 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	componentBurst         = 3
+	componentRefill        = 2 * time.Second
+	componentBucketIdle    = 10 * time.Minute
+	componentPruneInterval = 10 * time.Minute
+)
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+var (
+	componentBucketsMu sync.Mutex
+	componentBuckets   = make(map[string]*tokenBucket)
+)
+
+func allowComponentClick(userID, customID string, now time.Time) bool {
+	componentBucketsMu.Lock()
+	defer componentBucketsMu.Unlock()
+	key := userID + "|" + customID
+	b, ok := componentBuckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: componentBurst, updated: now}
+		componentBuckets[key] = b
+	}
+	b.tokens += float64(now.Sub(b.updated)) / float64(componentRefill)
+	if b.tokens > componentBurst {
+		b.tokens = componentBurst
+	}
+	b.updated = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func pruneComponentBuckets() error {
+	componentBucketsMu.Lock()
+	defer componentBucketsMu.Unlock()
+	cutoff := time.Now().Add(-componentBucketIdle)
+	for key, b := range componentBuckets {
+		if b.updated.Before(cutoff) {
+			delete(componentBuckets, key)
+		}
+	}
+	return nil
+}
+
+func limitComponentClicks(next interactionHandler) interactionHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.Type != discordgo.InteractionMessageComponent {
+			next(s, i)
+			return
+		}
+		customID := i.MessageComponentData().CustomID
+		if !allowComponentClick(interactionUser(i).ID, customID, time.Now()) {
+			action, _ := parseTicketComponentID(customID)
+			incCounter("potatobot_component_rate_limited_total", metricLabel("component", action))
+			respondError(s, i, errComponentRateLimited, nil)
+			return
+		}
+		next(s, i)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAllowComponentClickBurst(t *testing.T) {
+	now := time.Now()
+	for n := 0; n < componentBurst; n++ {
+		if !allowComponentClick("burst-user", "close", now) {
+			t.Fatalf("click %d within the burst was rejected", n+1)
+		}
+	}
+	if allowComponentClick("burst-user", "close", now) {
+		t.Fatal("click beyond the burst was allowed")
+	}
+	if !allowComponentClick("burst-user", "claim", now) {
+		t.Fatal("a different component shares the bucket")
+	}
+	if !allowComponentClick("burst-user", "close", now.Add(componentRefill)) {
+		t.Fatal("click after a refill interval was rejected")
+	}
+}
+
+func TestAllowComponentClickConcurrent(t *testing.T) {
+	now := time.Now()
+	const clicks = 100
+	var allowed atomic.Int64
+	var wg sync.WaitGroup
+	for n := 0; n < clicks; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if allowComponentClick("concurrent-user", "close", now) {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		pruneComponentBuckets()
+	}()
+	wg.Wait()
+	if got := allowed.Load(); got != componentBurst {
+		t.Fatalf("%d of %d simultaneous clicks were allowed, want %d", got, clicks, componentBurst)
+	}
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	reassignPrefix            = "reassign:"
+	assigneeIdleCheckInterval = 10 * time.Minute
+	maxReassignSuggestions    = 3
+)
+
+type reassignPolicy struct {
+	IdleAfter time.Duration `bson:"idle_after,omitempty"`
+	MaxClaims int           `bson:"max_claims,omitempty"`
+}
+
+type agentSuggestion struct {
+	AgentID string
+	Load    int
+	Skills  []string
+	OnDuty  bool
+}
+
+func (p reassignPolicy) label() string {
+	idle := "사용 안 함"
+	if p.IdleAfter > 0 {
+		idle = formatSLADuration(p.IdleAfter) + " 무응답 시 해제"
+	}
+	limit := "제한 없음"
+	if p.MaxClaims > 0 {
+		limit = fmt.Sprintf("1인당 %d개", p.MaxClaims)
+	}
+	return fmt.Sprintf("담당자 무응답: %s · 담당 한도: %s", idle, limit)
+}
+
+func recordAssigneeActivity(m *discordgo.MessageCreate, t *ticket) {
+	if t.AssigneeID == "" || m.Author.ID != t.AssigneeID {
+		return
+	}
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"assignee_active_at": m.Timestamp}}); err != nil {
+		log.Printf("Could not record assignee activity for '%s': %v", t.Name(), err)
+	}
+}
+
+func suggestReplacementAgents(s *discordgo.Session, t *ticket, exclude string) []agentSuggestion {
+	cfg := getConfig()
+	supportRoleID, ok := cfg.CategorySupportRoles[t.Category]
+	if !ok {
+		supportRoleID = cfg.DefaultSupportRoleID
+	}
+	loads, _, err := activeClaimLoads(t.Category)
+	if err != nil {
+		log.Printf("Could not load claim data for reassignment suggestions: %v", err)
+	}
+	candidates := make(map[string]*agentSuggestion)
+	for _, id := range onDutyAgents(s, supportRoleID) {
+		candidates[id] = &agentSuggestion{AgentID: id, OnDuty: true}
+	}
+	for id, skills := range cfg.AgentSkills {
+		if matched := matchingSkills(t, skills); len(matched) > 0 {
+			if c, ok := candidates[id]; ok {
+				c.Skills = matched
+			} else {
+				candidates[id] = &agentSuggestion{AgentID: id, Skills: matched}
+			}
+		}
+	}
+	var suggestions []agentSuggestion
+	for id, c := range candidates {
+		if id == t.OwnerID || id == exclude {
+			continue
+		}
+		c.Load = loads[id]
+		if limit := cfg.Reassignment.MaxClaims; limit > 0 && c.Load >= limit {
+			continue
+		}
+		suggestions = append(suggestions, *c)
+	}
+	sort.Slice(suggestions, func(a, b int) bool {
+		if suggestions[a].OnDuty != suggestions[b].OnDuty {
+			return suggestions[a].OnDuty
+		}
+		if len(suggestions[a].Skills) != len(suggestions[b].Skills) {
+			return len(suggestions[a].Skills) > len(suggestions[b].Skills)
+		}
+		if suggestions[a].Load != suggestions[b].Load {
+			return suggestions[a].Load < suggestions[b].Load
+		}
+		return suggestions[a].AgentID < suggestions[b].AgentID
+	})
+	if len(suggestions) > maxReassignSuggestions {
+		suggestions = suggestions[:maxReassignSuggestions]
+	}
+	return suggestions
+}
+
+func postReassignSuggestions(s *discordgo.Session, t *ticket, exclude, reason string) {
+	suggestions := suggestReplacementAgents(s, t, exclude)
+	embed := &discordgo.MessageEmbed{Title: "담당자 재배정 추천", Description: reason, Color: colorYellow}
+	if len(suggestions) == 0 {
+		embed.Description += "\n\n추천할 수 있는 담당자가 없습니다. /담당자변경으로 직접 지정해주세요."
+		s.ChannelMessageSendEmbed(t.ChannelID, embed)
+		return
+	}
+	var lines []string
+	var buttons []discordgo.MessageComponent
+	for _, a := range suggestions {
+		line := fmt.Sprintf("<@%s> · 담당 %d개", a.AgentID, a.Load)
+		if len(a.Skills) > 0 {
+			line += " · " + strings.Join(a.Skills, ", ")
+		}
+		if !a.OnDuty {
+			line += " · 현재 자리 비움"
+		}
+		lines = append(lines, line)
+		label := a.AgentID
+		if member, err := s.State.Member(app().GuildID, a.AgentID); err == nil {
+			label = memberDisplayName(member)
+		}
+		buttons = append(buttons, discordgo.Button{Label: "배정: " + label, Style: discordgo.PrimaryButton, CustomID: reassignPrefix + a.AgentID})
+	}
+	embed.Fields = []*discordgo.MessageEmbedField{{Name: "추천 담당자", Value: strings.Join(lines, "\n")}}
+	_, err := s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+		Embeds:          []*discordgo.MessageEmbed{embed},
+		Components:      []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}},
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Printf("Could not post reassignment suggestions for '%s': %v", t.Name(), err)
+	}
+}
+
+func checkClaimLimit(s *discordgo.Session, t *ticket, agentID string) {
+	limit := getConfig().Reassignment.MaxClaims
+	if limit <= 0 {
+		return
+	}
+	loads, _, err := activeClaimLoads(t.Category)
+	if err != nil {
+		log.Printf("Could not check claim limit for %s: %v", agentID, err)
+		return
+	}
+	if loads[agentID] <= limit {
+		return
+	}
+	postReassignSuggestions(s, t, agentID, fmt.Sprintf("<@%s> 님의 담당 티켓이 %d개로 한도(%d개)를 넘었습니다. 관리자는 아래 담당자 중 한 명에게 넘길 수 있습니다.", agentID, loads[agentID], limit))
+}
+
+func releaseIdleAssignments(s *discordgo.Session) error {
+	idle := getConfig().Reassignment.IdleAfter
+	if idle <= 0 {
+		return nil
+	}
+	cursor, err := app().Tickets.Find(context.TODO(), bson.M{"status": ticketStatusOpen, "assignee_id": bson.M{"$nin": []interface{}{"", nil}}})
+	if err != nil {
+		return fmt.Errorf("could not list assigned tickets: %w", err)
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		return fmt.Errorf("could not decode assigned tickets: %w", err)
+	}
+	for idx := range tickets {
+		t := &tickets[idx]
+		last := t.ClaimedAt
+		if t.AssigneeActiveAt.After(last) {
+			last = t.AssigneeActiveAt
+		}
+		if last.IsZero() || time.Since(last) < idle {
+			continue
+		}
+		previous := t.AssigneeID
+		pauseHandling(t.ChannelID, time.Now())
+		result, err := app().Tickets.UpdateOne(context.TODO(), bson.M{"channel_id": t.ChannelID, "assignee_id": previous}, bson.M{"$set": bson.M{"assignee_id": ""}})
+		if err != nil {
+			log.Printf("Could not release idle assignment of '%s': %v", t.Name(), err)
+			continue
+		}
+		if result.ModifiedCount == 0 {
+			continue
+		}
+		t.AssigneeID = ""
+		if msg, err := findTicketMessage(s, t.ChannelID); err == nil && msg != nil {
+			if err := clearTicketMessageAssignee(s, msg); err != nil {
+				log.Printf("Could not update ticket message of '%s': %v", t.Name(), err)
+			}
+		}
+		log.Printf("Released '%s' from idle assignee %s.", t.Name(), previous)
+		postReassignSuggestions(s, t, previous, fmt.Sprintf("<@%s> 님이 %s 동안 응답하지 않아 담당자 배정을 해제했습니다. 관리자는 아래 담당자 중 한 명에게 배정할 수 있습니다.", previous, formatSLADuration(idle)))
+	}
+	return nil
+}
+
+func clearTicketMessageAssignee(s *discordgo.Session, ticketMessage *discordgo.Message) error {
+	embed := ticketMessage.Embeds[0]
+	fields := embed.Fields[:0]
+	for _, field := range embed.Fields {
+		if field.Name != "담당자" {
+			fields = append(fields, field)
+		}
+	}
+	embed.Fields = fields
+	for _, row := range ticketMessage.Components {
+		if actionsRow, ok := row.(*discordgo.ActionsRow); ok {
+			for j, comp := range actionsRow.Components {
+				if button, ok := comp.(*discordgo.Button); ok {
+					if action, _ := parseTicketComponentID(button.CustomID); action == actionClaim {
+						button.Disabled = false
+						actionsRow.Components[j] = button
+					}
+				}
+			}
+		}
+	}
+	embeds := []*discordgo.MessageEmbed{embed}
+	_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{Channel: ticketMessage.ChannelID, ID: ticketMessage.ID, Embeds: &embeds, Components: &ticketMessage.Components})
+	return err
+}
+
+func handleReassignSuggestion(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	if t.Status != ticketStatusOpen {
+		respondError(s, i, errTicketNotOpen, nil)
+		return
+	}
+	agentID := strings.TrimPrefix(i.MessageComponentData().CustomID, reassignPrefix)
+	if agentID == t.AssigneeID {
+		respondError(s, i, errAlreadyClaimed, nil)
+		return
+	}
+	perms, err := s.UserChannelPermissions(agentID, t.ChannelID)
+	if err != nil {
+		respondError(s, i, errPermissionLookup, err)
+		return
+	}
+	if perms&discordgo.PermissionViewChannel != discordgo.PermissionViewChannel {
+		respondError(s, i, errAssigneeCannotView, nil, agentID)
+		return
+	}
+	ticketMessage, err := findTicketMessage(s, t.ChannelID)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	if ticketMessage == nil {
+		respondError(s, i, errTicketMessageMissing, nil)
+		return
+	}
+	if err := setTicketMessageAssignee(s, ticketMessage, fmt.Sprintf("<@%s>", agentID)); err != nil {
+		respondError(s, i, errTicketMessageEdit, err)
+		return
+	}
+	now := time.Now()
+	pauseHandling(t.ChannelID, now)
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"assignee_id": agentID, "claimed_at": now, "assignee_active_at": now}}); err != nil {
+		log.Printf("Error recording reassignment: %v", err)
+	}
+	resumeHandling(t.ChannelID, now)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{Embeds: i.Message.Embeds, Components: []discordgo.MessageComponent{}}})
+	s.ChannelMessageSendEmbed(t.ChannelID, &discordgo.MessageEmbed{
+		Title:       "담당자 재배정",
+		Description: fmt.Sprintf("<@%s> 님이 <@%s> 님을 이 티켓의 담당자로 배정했습니다.", i.Member.User.ID, agentID),
+		Color:       colorGreen,
+	})
+	t.AssigneeID = agentID
+	evaluateRules(s, t, ruleEventClaimed)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const maxReconcileListed = 15
+
+type reconcileReport struct {
+	Repaired      []string
+	StatusFixed   []string
+	Orphaned      []string
+	MissingMarked int
+}
+
+func reconcileTickets(s *discordgo.Session) {
+	if app().Tickets == nil {
+		return
+	}
+	report, err := runReconciliation(s)
+	if err != nil {
+		log.Printf("Ticket reconciliation failed: %v", err)
+		return
+	}
+	log.Printf("Ticket reconciliation finished: %d repaired, %d status fixed, %d orphaned channels, %d missing channels marked deleted.", len(report.Repaired), len(report.StatusFixed), len(report.Orphaned), report.MissingMarked)
+	if len(report.Repaired) == 0 && len(report.StatusFixed) == 0 && len(report.Orphaned) == 0 && report.MissingMarked == 0 {
+		return
+	}
+	notifyChannel(s, getConfig().LogChannelID, reconcileEmbed(report))
+}
+
+func runReconciliation(s *discordgo.Session) (*reconcileReport, error) {
+	cfg := getConfig()
+	channels, err := s.GuildChannels(app().GuildID)
+	if err != nil {
+		return nil, fmt.Errorf("could not list guild channels: %w", err)
+	}
+	report := &reconcileReport{}
+	present := make(map[string]bool)
+	for _, ch := range channels {
+		if ch.Type != discordgo.ChannelTypeGuildText {
+			continue
+		}
+		var status string
+		switch ch.ParentID {
+		case cfg.OpenCategoryID:
+			status = ticketStatusOpen
+		case cfg.ClosedCategoryID:
+			status = ticketStatusClosed
+		default:
+			continue
+		}
+		present[ch.ID] = true
+		t, err := findTicket(ch.ID)
+		if err == nil {
+			if t.Status != status {
+				if err := updateTicket(ch.ID, bson.M{"$set": bson.M{"status": status}}); err != nil {
+					log.Printf("Could not fix status of ticket '%s': %v", ch.Name, err)
+					continue
+				}
+				report.StatusFixed = append(report.StatusFixed, fmt.Sprintf("<#%s> (%s → %s)", ch.ID, t.Status, status))
+			}
+			continue
+		}
+		if err != mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("could not look up ticket for channel %s: %w", ch.ID, err)
+		}
+		t = ticketFromChannel(ch, status)
+		if t == nil {
+			report.Orphaned = append(report.Orphaned, fmt.Sprintf("<#%s>", ch.ID))
+			continue
+		}
+		if err := insertTicket(t); err != nil {
+			log.Printf("Could not repair ticket '%s': %v", ch.Name, err)
+			continue
+		}
+		report.Repaired = append(report.Repaired, fmt.Sprintf("<#%s>", ch.ID))
+	}
+	cursor, err := app().Tickets.Find(context.TODO(), bson.M{"status": bson.M{"$ne": ticketStatusDeleted}})
+	if err != nil {
+		return nil, fmt.Errorf("could not list tickets: %w", err)
+	}
+	var known []ticket
+	if err := cursor.All(context.TODO(), &known); err != nil {
+		return nil, fmt.Errorf("could not decode tickets: %w", err)
+	}
+	for _, t := range known {
+		if present[t.ChannelID] {
+			continue
+		}
+		if _, err := s.Channel(t.ChannelID); err == nil {
+			continue
+		}
+		if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"status": ticketStatusDeleted}}); err != nil {
+			log.Printf("Could not mark missing ticket '%s' as deleted: %v", t.Name(), err)
+			continue
+		}
+		report.MissingMarked++
+	}
+	return report, nil
+}
+
+func ticketFromChannel(ch *discordgo.Channel, status string) *ticket {
+	ownerID := getUserIDFromTopic(ch.Topic)
+	parts := strings.SplitN(ch.Name, "-", 2)
+	if ownerID == "" || len(parts) != 2 || !isTicketTopic(parts[0]) {
+		return nil
+	}
+	number, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil
+	}
+	createdAt, _ := discordgo.SnowflakeTimestamp(ch.ID)
+	t := &ticket{
+		ChannelID: ch.ID,
+		GuildID:   ch.GuildID,
+		Category:  parts[0],
+		Number:    number,
+		OwnerID:   ownerID,
+		Status:    status,
+		CreatedAt: createdAt,
+	}
+	for _, overwrite := range ch.PermissionOverwrites {
+		if overwrite.ID == ownerID || overwrite.ID == ch.GuildID || overwrite.Allow&discordgo.PermissionViewChannel == 0 {
+			continue
+		}
+		if overwrite.Type == discordgo.PermissionOverwriteTypeMember {
+			t.Participants = append(t.Participants, overwrite.ID)
+		} else if !isConfiguredSupportRole(overwrite.ID) {
+			t.ParticipantRoles = append(t.ParticipantRoles, overwrite.ID)
+		}
+	}
+	return t
+}
+
+func reconcileEmbed(report *reconcileReport) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{Title: "티켓 데이터 정합성 점검", Description: "봇 시작 시 티켓 채널과 데이터베이스를 대조한 결과입니다.", Color: colorYellow}
+	if len(report.Repaired) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: fmt.Sprintf("복구된 티켓 (%d)", len(report.Repaired)), Value: reconcileList(report.Repaired), Inline: false})
+	}
+	if len(report.StatusFixed) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: fmt.Sprintf("상태 보정 (%d)", len(report.StatusFixed)), Value: reconcileList(report.StatusFixed), Inline: false})
+	}
+	if len(report.Orphaned) > 0 {
+		embed.Color = colorRed
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: fmt.Sprintf("⚠️ 복구할 수 없는 채널 (%d)", len(report.Orphaned)), Value: reconcileList(report.Orphaned) + "\n채널 주제에서 민원인을 확인할 수 없습니다. 직접 확인 후 정리해주세요.", Inline: false})
+	}
+	if report.MissingMarked > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "삭제 처리", Value: fmt.Sprintf("채널이 없어진 티켓 %d건을 삭제됨으로 표시했습니다.", report.MissingMarked), Inline: false})
+	}
+	return embed
+}
+
+func reconcileList(items []string) string {
+	if len(items) <= maxReconcileListed {
+		return strings.Join(items, ", ")
+	}
+	return strings.Join(items[:maxReconcileListed], ", ") + fmt.Sprintf(" 외 %d건", len(items)-maxReconcileListed)
+}
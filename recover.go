@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func recoverInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := debug.Stack()
+	incidentID := fmt.Sprintf("%x", time.Now().UnixNano()&0xffffffff)
+	logError(i, errInternalPanic, fmt.Errorf("panic: %v", r), incidentID)
+	log.Printf("Stack trace for incident %s:\n%s", incidentID, stack)
+	incCounter("potatobot_interaction_panics_total", metricLabel("handler", interactionHandlerName(i)))
+	tags := interactionErrorTags(i)
+	tags["incident"] = incidentID
+	reportError("panic", fmt.Sprintf("panic in %s: %v", interactionName(i), r), string(stack), tags)
+	embed := errInternalPanic.embed(incidentID)
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}}); err != nil {
+		s.FollowupMessageCreate(i.Interaction, false, &discordgo.WebhookParams{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}})
+	}
+	trace := string(stack)
+	if len(trace) > 3500 {
+		trace = trace[:3500] + "\n..."
+	}
+	notifyChannel(s, getConfig().LogChannelID, &discordgo.MessageEmbed{
+		Title:       "처리 중 오류 발생",
+		Description: fmt.Sprintf("`%s` 처리 중 예기치 않은 오류가 발생했습니다.\n```\n%s\n```", interactionName(i), strings.ReplaceAll(trace, "```", "'''")),
+		Color:       colorRed,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "사건 번호", Value: incidentID, Inline: true},
+			{Name: "채널", Value: fmt.Sprintf("<#%s>", i.ChannelID), Inline: true},
+			{Name: "오류", Value: fmt.Sprintf("%v", r), Inline: false},
+		},
+		Timestamp: time.Now().In(kstLocation).Format(time.RFC3339),
+	})
+}
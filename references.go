@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const maxTicketReferencesPerMessage = 5
+
+var ticketReferencePattern = regexp.MustCompile(`#([^\s#<>-]+)-(\d{4,})\b`)
+
+func messageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if m.Author == nil || m.Author.Bot || m.GuildID == "" {
+		return
+	}
+	handleTicketReferences(s, m)
+	if t := openTicketForMessage(s, m); t != nil {
+		handleIntakeAttachments(s, m, t)
+		handleFirstResponse(s, m, t)
+		recordStaffMessage(m, t)
+		recordAssigneeActivity(m, t)
+		recordOwnerActivity(m, t)
+		trackAwaitingReply(m, t)
+		handleTicketLanguage(s, m, t)
+	}
+}
+
+func openTicketForMessage(s *discordgo.Session, m *discordgo.MessageCreate) *ticket {
+	ch, err := s.State.Channel(m.ChannelID)
+	if err != nil || !isTicketTopic(ticketCategory(ch)) {
+		return nil
+	}
+	t := ticketForChannel(ch)
+	if t == nil || t.Status != ticketStatusOpen {
+		return nil
+	}
+	return t
+}
+
+func handleTicketReferences(s *discordgo.Session, m *discordgo.MessageCreate) {
+	matches := ticketReferencePattern.FindAllStringSubmatch(m.Content, -1)
+	codes := ticketCodePattern.FindAllString(m.Content, -1)
+	if len(matches) == 0 && len(codes) == 0 {
+		return
+	}
+	if m.Member == nil {
+		return
+	}
+	if !hasSupportRole(m.Member) {
+		return
+	}
+	ch, err := s.State.Channel(m.ChannelID)
+	if err != nil {
+		return
+	}
+	staffChannel := isStaffOnlyChannel(s, ch)
+	if !staffChannel && !isTicketTopic(ticketCategory(ch)) {
+		return
+	}
+	seen := make(map[string]bool)
+	var fields []*discordgo.MessageEmbedField
+	for _, match := range matches {
+		if !isTicketTopic(match[1]) {
+			continue
+		}
+		name := match[1] + "-" + match[2]
+		if seen[name] || len(fields) >= maxTicketReferencesPerMessage {
+			continue
+		}
+		seen[name] = true
+		number, _ := strconv.ParseUint(match[2], 10, 64)
+		t, err := findTicketByNumber(match[1], number)
+		if err != nil && err != mongo.ErrNoDocuments {
+			log.Printf("Could not look up referenced ticket '%s': %v", name, err)
+			continue
+		}
+		fields = append(fields, ticketReferenceField(name, t, staffChannel))
+	}
+	for _, code := range codes {
+		id, ok := parseTicketCode(code)
+		if !ok || seen[code] || len(fields) >= maxTicketReferencesPerMessage {
+			continue
+		}
+		seen[code] = true
+		t, err := findTicketByID(id)
+		if err != nil && err != mongo.ErrNoDocuments {
+			log.Printf("Could not look up referenced ticket '%s': %v", code, err)
+			continue
+		}
+		if t != nil && t.Status == ticketStatusDeleted {
+			t = nil
+		}
+		fields = append(fields, ticketReferenceField(code, t, staffChannel))
+	}
+	if len(fields) == 0 {
+		return
+	}
+	_, err = s.ChannelMessageSendComplex(m.ChannelID, &discordgo.MessageSend{
+		Embeds:          []*discordgo.MessageEmbed{{Title: "티켓 참조", Color: colorBlue, Fields: fields}},
+		Reference:       m.Reference(),
+		AllowedMentions: &discordgo.MessageAllowedMentions{},
+	})
+	if err != nil {
+		log.Printf("Could not reply with ticket references: %v", err)
+	}
+}
+
+func isTicketTopic(topic string) bool {
+	for _, option := range ticketOptions() {
+		if option.Value == topic {
+			return true
+		}
+	}
+	return false
+}
+
+func isStaffOnlyChannel(s *discordgo.Session, ch *discordgo.Channel) bool {
+	if ch.ID == getConfig().LogChannelID {
+		return true
+	}
+	if isTicketTopic(ticketCategory(ch)) {
+		return false
+	}
+	for _, c := range []*discordgo.Channel{ch, parentChannel(s, ch)} {
+		if c == nil {
+			continue
+		}
+		for _, o := range c.PermissionOverwrites {
+			if o.ID == ch.GuildID && o.Type == discordgo.PermissionOverwriteTypeRole {
+				return o.Deny&discordgo.PermissionViewChannel != 0
+			}
+		}
+	}
+	return false
+}
+
+func parentChannel(s *discordgo.Session, ch *discordgo.Channel) *discordgo.Channel {
+	if ch.ParentID == "" {
+		return nil
+	}
+	parent, err := s.State.Channel(ch.ParentID)
+	if err != nil {
+		return nil
+	}
+	return parent
+}
+
+func ticketReferenceField(name string, t *ticket, showOwner bool) *discordgo.MessageEmbedField {
+	if t == nil {
+		return &discordgo.MessageEmbedField{Name: "#" + name, Value: "티켓을 찾을 수 없습니다. 이미 삭제된 티켓일 수 있습니다.", Inline: false}
+	}
+	status := "🟢 진행 중"
+	if t.Status == ticketStatusClosed {
+		status = "⚪ 종료됨"
+	}
+	owner := fmt.Sprintf("<@%s>", t.OwnerID)
+	if featuresFor(t.Category).Anonymous {
+		owner = anonymousDisplayName
+	} else if !showOwner {
+		owner = "(직원 전용 채널에서만 표시)"
+	}
+	assignee := "미배정"
+	if t.AssigneeID != "" {
+		assignee = fmt.Sprintf("<@%s>", t.AssigneeID)
+	}
+	lines := []string{
+		fmt.Sprintf("접수번호: %s", t.Code),
+		fmt.Sprintf("채널: <#%s>", t.ChannelID),
+		"상태: " + status,
+		"민원인: " + owner,
+		"담당자: " + assignee,
+	}
+	return &discordgo.MessageEmbedField{Name: "#" + name, Value: strings.Join(lines, "\n"), Inline: true}
+}
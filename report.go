@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func reportCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "보고서",
+		Description: "한 달 동안 종료된 티켓 목록을 CSV 또는 Excel 파일로 받습니다.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "month", Description: "보고서를 만들 달 (예: 2026-01)", Required: true},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "format", Description: "파일 형식 (기본: CSV)", Required: false, Choices: exportFormatChoices},
+		},
+	}
+}
+
+func handleMonthlyReport(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	value := options["month"].StringValue()
+	month, err := parseExportMonth(value)
+	if err != nil {
+		respondError(s, i, errInvalidExportMonth, nil, value)
+		return
+	}
+	format := exportFormatCSV
+	if opt, ok := options["format"]; ok {
+		format = opt.StringValue()
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}})
+	label := month.Format("2006-01")
+	guildName := i.GuildID
+	if guild, err := s.State.Guild(i.GuildID); err == nil {
+		guildName = guild.Name
+	}
+	var buf bytes.Buffer
+	count, err := writeMonthlyReport(month, format, reportMetadata{GuildName: guildName, GeneratedBy: interactionUser(i).Username}, &buf)
+	if err != nil {
+		embeds := []*discordgo.MessageEmbed{{Title: "월간 보고서", Description: fmt.Sprintf("%s 보고서를 만들지 못했습니다: %v", label, err), Color: colorRed}}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+		return
+	}
+	if count == 0 {
+		embeds := []*discordgo.MessageEmbed{{Title: "월간 보고서", Description: fmt.Sprintf("%s에 종료된 티켓이 없습니다.", label), Color: colorYellow}}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+		return
+	}
+	embeds := []*discordgo.MessageEmbed{{Title: "월간 보고서", Description: fmt.Sprintf("%s에 종료된 티켓 %d건의 목록입니다.", label, count), Color: colorGreen}}
+	file := &discordgo.File{Name: fmt.Sprintf("tickets-%s.csv", label), ContentType: "text/csv", Reader: &buf}
+	if format == exportFormatXLSX {
+		file = &discordgo.File{Name: fmt.Sprintf("tickets-%s.xlsx", label), ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", Reader: &buf}
+	}
+	files := []*discordgo.File{file}
+	if chartEmbeds, charts, err := trendChartAttachments(month, maxTrendChartEmbeds); err != nil {
+		log.Printf("Could not build trend charts for %s: %v", label, err)
+	} else {
+		embeds = append(embeds, chartEmbeds...)
+		files = append(files, charts...)
+	}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds, Files: files})
+}
+
+type reportMetadata struct {
+	GuildName   string
+	GeneratedBy string
+}
+
+func selfResolvedCell(selfResolved bool) string {
+	if selfResolved {
+		return "예"
+	}
+	return "아니오"
+}
+
+func writeMonthlyReport(month time.Time, format string, meta reportMetadata, buf *bytes.Buffer) (int, error) {
+	filter := bson.M{"closed_at": bson.M{"$gte": month, "$lt": month.AddDate(0, 1, 0)}}
+	cursor, err := app().Tickets.Find(context.TODO(), filter, options.Find().SetSort(bson.M{"closed_at": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("could not fetch tickets: %w", err)
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		return 0, fmt.Errorf("could not decode tickets: %w", err)
+	}
+	locale := getConfig().ExportLocale
+	sheet := exportSheet{Name: "티켓", Rows: [][]string{{"접수번호", "번호", "창구", "민원인 ID", "민원인", "담당자 ID", "접수 시각", "종료 시각", "처리 시간(분)", "실처리 시간(분)", "담당자별 실처리(분)", "종료 코드", "직접 해결", "만족도"}}}
+	for _, t := range tickets {
+		rating := ""
+		if t.Rating > 0 {
+			rating = strconv.Itoa(t.Rating)
+		}
+		resolution := ""
+		if t.ResolutionTime > 0 {
+			resolution = locale.number(int64(t.ResolutionTime / time.Minute))
+		}
+		handling := ""
+		if total := t.totalHandlingTime(); total > 0 {
+			handling = locale.number(int64(total / time.Minute))
+		}
+		sheet.Rows = append(sheet.Rows, []string{
+			t.Code,
+			t.Name(),
+			t.Category,
+			t.OwnerID,
+			t.Nickname,
+			t.AssigneeID,
+			locale.date(t.CreatedAt),
+			locale.date(t.ClosedAt),
+			resolution,
+			handling,
+			handlingBreakdown(t.HandlingTime, locale),
+			t.CloseCode,
+			selfResolvedCell(t.SelfResolved),
+			rating,
+		})
+	}
+	if format != exportFormatXLSX {
+		return len(tickets), writeCSVSheet(buf, sheet)
+	}
+	info := exportSheet{Name: "정보", Rows: [][]string{
+		{"항목", "값"},
+		{"서버", meta.GuildName},
+		{"기간", fmt.Sprintf("%s ~ %s", locale.date(month), locale.date(month.AddDate(0, 1, 0).Add(-time.Second)))},
+		{"종료 티켓 수", locale.number(int64(len(tickets)))},
+		{"작성자", meta.GeneratedBy},
+		{"생성 시각", locale.date(time.Now())},
+		{"서식", locale.label()},
+	}}
+	return len(tickets), writeXLSX(buf, []exportSheet{sheet, info})
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type responseBucket struct {
+	samples []time.Duration
+}
+
+func (b *responseBucket) summary() string {
+	return fmt.Sprintf("평균 %s · 중앙값 %s · %d건", formatWait(averageDuration(b.samples)), formatWait(percentileDuration(b.samples, 0.5)), len(b.samples))
+}
+
+func handleFirstResponse(s *discordgo.Session, m *discordgo.MessageCreate, t *ticket) {
+	if !t.FirstResponseAt.IsZero() || m.Author.ID == t.OwnerID || m.Member == nil || !hasSupportRole(m.Member) {
+		return
+	}
+	t.FirstResponseAt = m.Timestamp
+	t.FirstResponderID = m.Author.ID
+	result, err := app().Tickets.UpdateOne(context.TODO(), bson.M{"channel_id": t.ChannelID, "first_response_at": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"first_response_at": t.FirstResponseAt, "first_responder_id": t.FirstResponderID}})
+	if err != nil {
+		log.Printf("Could not record first response for '%s': %v", t.Name(), err)
+		return
+	}
+	if result.ModifiedCount > 0 && !t.FirstResponseDue.IsZero() {
+		refreshSLAField(s, t)
+	}
+}
+
+func handleResponseTimeReport(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	days, since := reportPeriod(options)
+	filter := bson.M{"created_at": bson.M{"$gte": since}, "first_response_at": bson.M{"$exists": true}}
+	category := ""
+	if opt, ok := options["topic"]; ok {
+		category = opt.StringValue()
+		filter["category"] = category
+	}
+	cursor, err := app().Tickets.Find(context.TODO(), filter)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	total := &responseBucket{}
+	byCategory := make(map[string]*responseBucket)
+	byResponder := make(map[string]*responseBucket)
+	for _, t := range tickets {
+		d := t.FirstResponseAt.Sub(t.CreatedAt)
+		total.samples = append(total.samples, d)
+		if byCategory[t.Category] == nil {
+			byCategory[t.Category] = &responseBucket{}
+		}
+		byCategory[t.Category].samples = append(byCategory[t.Category].samples, d)
+		if t.FirstResponderID != "" {
+			if byResponder[t.FirstResponderID] == nil {
+				byResponder[t.FirstResponderID] = &responseBucket{}
+			}
+			byResponder[t.FirstResponderID].samples = append(byResponder[t.FirstResponderID].samples, d)
+		}
+	}
+	title := fmt.Sprintf("첫 응답 시간 (최근 %d일)", days)
+	if category != "" {
+		title = fmt.Sprintf("%s 첫 응답 시간 (최근 %d일)", category, days)
+	}
+	embed := &discordgo.MessageEmbed{Title: title, Color: colorBlue, Timestamp: time.Now().In(kstLocation).Format(time.RFC3339)}
+	if len(tickets) == 0 {
+		embed.Description = "해당 기간에 첫 응답이 기록된 티켓이 없습니다."
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+		return
+	}
+	embed.Description = "전체: " + total.summary()
+	var categoryLines []string
+	for _, option := range ticketOptions() {
+		if b, ok := byCategory[option.Value]; ok {
+			categoryLines = append(categoryLines, fmt.Sprintf("**%s**: %s", option.Value, b.summary()))
+		}
+	}
+	embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "창구별", Value: strings.Join(categoryLines, "\n"), Inline: false})
+	responders := make([]string, 0, len(byResponder))
+	for id := range byResponder {
+		responders = append(responders, id)
+	}
+	sort.Slice(responders, func(a, b int) bool {
+		return len(byResponder[responders[a]].samples) > len(byResponder[responders[b]].samples)
+	})
+	var responderLines []string
+	for n, id := range responders {
+		if n >= 10 {
+			break
+		}
+		responderLines = append(responderLines, fmt.Sprintf("<@%s>: %s", id, byResponder[id].summary()))
+	}
+	if len(responderLines) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "담당자별", Value: strings.Join(responderLines, "\n"), Inline: false})
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
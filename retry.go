@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	discordRetryAttempts = 3
+	discordRetryBase     = 250 * time.Millisecond
+	discordRetryMaxDelay = 2 * time.Second
+)
+
+func withRetry[T any](op string, call func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		result, err := call()
+		delay, retry := discordRetryDelay(err, attempt)
+		if !retry || attempt >= discordRetryAttempts {
+			return result, err
+		}
+		log.Printf("Discord %s failed (attempt %d/%d), retrying in %s: %v", op, attempt, discordRetryAttempts, delay.Round(time.Millisecond), err)
+		incCounter("potatobot_discord_api_retries_total", metricLabel("op", op))
+		time.Sleep(delay)
+	}
+}
+
+func retryDiscord(op string, call func() error) error {
+	_, err := withRetry(op, func() (struct{}, error) { return struct{}{}, call() })
+	return err
+}
+
+func discordRetryDelay(err error, attempt int) (time.Duration, bool) {
+	if err == nil {
+		return 0, false
+	}
+	backoff := discordRetryBase << (attempt - 1)
+	if backoff > discordRetryMaxDelay {
+		backoff = discordRetryMaxDelay
+	}
+	jittered := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+	var rateLimit *discordgo.RateLimitError
+	if errors.As(err, &rateLimit) {
+		return rateLimitDelay(rateLimit.RetryAfter, jittered)
+	}
+	var restErr *discordgo.RESTError
+	if errors.As(err, &restErr) && restErr.Response != nil {
+		if restErr.Response.StatusCode == http.StatusTooManyRequests {
+			seconds, _ := strconv.ParseFloat(restErr.Response.Header.Get("Retry-After"), 64)
+			return rateLimitDelay(time.Duration(seconds*float64(time.Second)), jittered)
+		}
+		return jittered, restErr.Response.StatusCode >= 500
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return jittered, true
+	}
+	return 0, false
+}
+
+func rateLimitDelay(retryAfter, jitter time.Duration) (time.Duration, bool) {
+	if retryAfter > discordRetryMaxDelay {
+		return 0, false
+	}
+	return retryAfter + jitter/4, true
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func checkTopicRole(s *discordgo.Session, i *discordgo.InteractionCreate, topic string) bool {
+	roleID := getConfig().CategoryRequiredRoles[topic]
+	if roleID == "" || memberHasRole(i.Member, roleID) {
+		return true
+	}
+	respondError(s, i, errTopicRoleMissing, nil, topic, roleID)
+	return false
+}
+
+func topicRoleSummary(roles map[string]string) string {
+	if len(roles) == 0 {
+		return ""
+	}
+	topics := make([]string, 0, len(roles))
+	for topic := range roles {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	var lines []string
+	for _, topic := range topics {
+		lines = append(lines, fmt.Sprintf("%s: <@&%s> 역할 필요", topic, roles[topic]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func topicRoleCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "접수자격",
+		Description:              "특정 민원 창구를 이용하는 데 필요한 역할을 지정합니다. 역할을 비우면 제한을 해제합니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: true, Choices: ticketTopicChoices()},
+			{Type: discordgo.ApplicationCommandOptionRole, Name: "role", Description: "이 창구에 필요한 역할 (예: 인증된 주민)", Required: false},
+		},
+	}
+}
+
+func handleTopicRole(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	topic := options["topic"].StringValue()
+	roleID := ""
+	if opt, ok := options["role"]; ok {
+		roleID = opt.RoleValue(nil, "").ID
+	}
+	err := updateConfig(func(cfg *guildConfig) {
+		if roleID == "" {
+			delete(cfg.CategoryRequiredRoles, topic)
+		} else {
+			cfg.CategoryRequiredRoles[topic] = roleID
+		}
+	})
+	if err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	summary := fmt.Sprintf("이제 누구나 %s 창구에 민원을 접수할 수 있습니다.", topic)
+	if roleID != "" {
+		summary = fmt.Sprintf("%s 창구는 이제 <@&%s> 역할이 있는 사용자만 접수할 수 있습니다.", topic, roleID)
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "접수 자격 변경", Description: summary, Color: colorGreen}}}})
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const roleFixPrefix = "fix_role:"
+
+var (
+	roleNamesMu sync.Mutex
+	roleNames   = make(map[string]string)
+)
+
+type roleSetting struct {
+	Key    string
+	Label  string
+	RoleID string
+}
+
+func configuredRoleSettings(cfg guildConfig) []roleSetting {
+	settings := []roleSetting{{Key: "default", Label: "기본 지원 역할", RoleID: cfg.DefaultSupportRoleID}}
+	for _, option := range ticketOptions() {
+		if id, ok := cfg.CategorySupportRoles[option.Value]; ok {
+			settings = append(settings, roleSetting{Key: "topic:" + option.Value, Label: option.Value + " 지원 역할", RoleID: id})
+		}
+	}
+	if cfg.SLAEscalation.RoleID != "" {
+		settings = append(settings, roleSetting{Key: "escalation", Label: "SLA 초과 알림 역할", RoleID: cfg.SLAEscalation.RoleID})
+	}
+	if cfg.Verification.RequiredRoleID != "" {
+		settings = append(settings, roleSetting{Key: "verify_required", Label: "접수 필요 역할", RoleID: cfg.Verification.RequiredRoleID})
+	}
+	if cfg.Verification.BypassRoleID != "" {
+		settings = append(settings, roleSetting{Key: "verify_bypass", Label: "확인 면제 역할", RoleID: cfg.Verification.BypassRoleID})
+	}
+	return settings
+}
+
+func settingsForRole(roleID string) []roleSetting {
+	var matched []roleSetting
+	for _, setting := range configuredRoleSettings(getConfig()) {
+		if setting.RoleID == roleID {
+			matched = append(matched, setting)
+		}
+	}
+	return matched
+}
+
+func checkConfiguredRoles(s *discordgo.Session) {
+	roles, err := s.GuildRoles(app().GuildID)
+	if err != nil {
+		log.Printf("Could not fetch guild roles to check configuration: %v", err)
+		return
+	}
+	existing := make(map[string]bool, len(roles))
+	roleNamesMu.Lock()
+	for _, role := range roles {
+		existing[role.ID] = true
+		roleNames[role.ID] = role.Name
+	}
+	roleNamesMu.Unlock()
+	var broken []roleSetting
+	for _, setting := range configuredRoleSettings(getConfig()) {
+		if !existing[setting.RoleID] {
+			broken = append(broken, setting)
+		}
+	}
+	if len(broken) > 0 {
+		alertBrokenRoles(s, "설정된 역할을 찾을 수 없음", "봇 시작 시 확인한 결과, 아래 설정이 서버에 없는 역할을 가리키고 있습니다.", broken)
+	}
+}
+
+func guildRoleDelete(s *discordgo.Session, e *discordgo.GuildRoleDelete) {
+	roleNamesMu.Lock()
+	name := roleNames[e.RoleID]
+	delete(roleNames, e.RoleID)
+	roleNamesMu.Unlock()
+	broken := settingsForRole(e.RoleID)
+	if len(broken) == 0 {
+		return
+	}
+	if name == "" {
+		name = e.RoleID
+	}
+	log.Printf("Warning: Configured role '%s' was deleted; %d settings now point to a missing role.", name, len(broken))
+	alertBrokenRoles(s, "설정된 역할이 삭제됨", fmt.Sprintf("`@%s` 역할이 삭제되어 아래 설정이 동작하지 않습니다. 지원 역할 확인에서 담당자가 인식되지 않을 수 있습니다.", name), broken)
+}
+
+func guildRoleUpdate(s *discordgo.Session, e *discordgo.GuildRoleUpdate) {
+	roleNamesMu.Lock()
+	previous, known := roleNames[e.Role.ID]
+	roleNames[e.Role.ID] = e.Role.Name
+	roleNamesMu.Unlock()
+	if !known || previous == e.Role.Name {
+		return
+	}
+	affected := settingsForRole(e.Role.ID)
+	if len(affected) == 0 {
+		return
+	}
+	log.Printf("Configured role '%s' was renamed to '%s'.", previous, e.Role.Name)
+	alertBrokenRoles(s, "설정된 역할 이름 변경", fmt.Sprintf("`@%s` 역할의 이름이 `@%s`(으)로 바뀌었습니다. 의도한 변경이 아니라면 아래에서 올바른 역할을 다시 지정해주세요.", previous, e.Role.Name), affected)
+}
+
+func alertBrokenRoles(s *discordgo.Session, title, description string, settings []roleSetting) {
+	var lines []string
+	var rows []discordgo.MessageComponent
+	for _, setting := range settings {
+		lines = append(lines, fmt.Sprintf("• %s (<@&%s>)", setting.Label, setting.RoleID))
+		if len(rows) < 5 {
+			rows = append(rows, discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.SelectMenu{MenuType: discordgo.RoleSelectMenu, CustomID: roleFixPrefix + setting.Key, Placeholder: setting.Label + "을(를) 다시 지정"},
+			}})
+		}
+	}
+	_, err := s.ChannelMessageSendComplex(getConfig().LogChannelID, &discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{{
+			Title:       "⚠️ " + title,
+			Description: description + "\n\n" + strings.Join(lines, "\n") + "\n\n관리자는 아래 메뉴에서 새 역할을 선택해 바로 고칠 수 있습니다.",
+			Color:       colorRed,
+		}},
+		Components: rows,
+	})
+	if err != nil {
+		log.Printf("Could not send role configuration alert: %v", err)
+	}
+}
+
+func handleRoleFix(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.MessageComponentData()
+	key := strings.TrimPrefix(data.CustomID, roleFixPrefix)
+	roleID := data.Values[0]
+	label := key
+	err := updateConfig(func(cfg *guildConfig) {
+		switch {
+		case key == "default":
+			cfg.DefaultSupportRoleID = roleID
+			label = "기본 지원 역할"
+		case strings.HasPrefix(key, "topic:"):
+			topic := strings.TrimPrefix(key, "topic:")
+			cfg.CategorySupportRoles[topic] = roleID
+			label = topic + " 지원 역할"
+		case key == "escalation":
+			cfg.SLAEscalation.RoleID = roleID
+			label = "SLA 초과 알림 역할"
+		case key == "verify_required":
+			cfg.Verification.RequiredRoleID = roleID
+			label = "접수 필요 역할"
+		case key == "verify_bypass":
+			cfg.Verification.BypassRoleID = roleID
+			label = "확인 면제 역할"
+		}
+	})
+	if err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	log.Printf("%s repaired role setting '%s' to %s.", i.Member.User.Username, key, roleID)
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "설정 변경", Description: fmt.Sprintf("<@%s> 님이 %s을(를) <@&%s>(으)로 다시 지정했습니다.", i.Member.User.ID, label, roleID), Color: colorGreen}}}})
+}
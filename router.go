@@ -0,0 +1,182 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+type interactionHandler func(s *discordgo.Session, i *discordgo.InteractionCreate)
+
+type interactionMiddleware func(next interactionHandler) interactionHandler
+
+type interactionRoute struct {
+	handler    interactionHandler
+	middleware []interactionMiddleware
+}
+
+type prefixRoute struct {
+	prefix string
+	route  interactionRoute
+}
+
+type interactionRouter struct {
+	middleware        []interactionMiddleware
+	commands          map[string]interactionRoute
+	components        map[string]interactionRoute
+	ticketActions     map[string]interactionRoute
+	componentPrefixes []prefixRoute
+	modalPrefixes     []prefixRoute
+}
+
+var router = &interactionRouter{
+	commands:      make(map[string]interactionRoute),
+	components:    make(map[string]interactionRoute),
+	ticketActions: make(map[string]interactionRoute),
+}
+
+func (r *interactionRouter) Use(mw ...interactionMiddleware) {
+	r.middleware = append(r.middleware, mw...)
+}
+
+func (r *interactionRouter) Command(name string, h interactionHandler, mw ...interactionMiddleware) {
+	r.commands[name] = interactionRoute{handler: h, middleware: mw}
+}
+
+func (r *interactionRouter) Component(customID string, h interactionHandler, mw ...interactionMiddleware) {
+	r.components[customID] = interactionRoute{handler: h, middleware: append(mw, recordComponent(customID))}
+}
+
+func (r *interactionRouter) TicketAction(action string, h interactionHandler, mw ...interactionMiddleware) {
+	r.ticketActions[action] = interactionRoute{handler: h, middleware: append([]interactionMiddleware{validateTicketAction, recordComponent(action)}, mw...)}
+}
+
+func (r *interactionRouter) ComponentPrefix(prefix string, h interactionHandler, mw ...interactionMiddleware) {
+	r.componentPrefixes = append(r.componentPrefixes, prefixRoute{prefix: prefix, route: interactionRoute{handler: h, middleware: mw}})
+}
+
+func (r *interactionRouter) ModalPrefix(prefix string, h interactionHandler, mw ...interactionMiddleware) {
+	r.modalPrefixes = append(r.modalPrefixes, prefixRoute{prefix: prefix, route: interactionRoute{handler: h, middleware: mw}})
+}
+
+func (r *interactionRouter) resolve(i *discordgo.InteractionCreate) (interactionRoute, bool) {
+	switch i.Type {
+	case discordgo.InteractionApplicationCommand:
+		route, ok := r.commands[i.ApplicationCommandData().Name]
+		return route, ok
+	case discordgo.InteractionMessageComponent:
+		customID := i.MessageComponentData().CustomID
+		if isTicketAction(customID) {
+			action, _ := parseTicketComponentID(customID)
+			route, ok := r.ticketActions[action]
+			return route, ok
+		}
+		if route, ok := r.components[customID]; ok {
+			return route, true
+		}
+		return matchPrefix(r.componentPrefixes, customID)
+	case discordgo.InteractionModalSubmit:
+		return matchPrefix(r.modalPrefixes, i.ModalSubmitData().CustomID)
+	}
+	return interactionRoute{}, false
+}
+
+func matchPrefix(routes []prefixRoute, customID string) (interactionRoute, bool) {
+	for _, p := range routes {
+		if strings.HasPrefix(customID, p.prefix) {
+			return p.route, true
+		}
+	}
+	return interactionRoute{}, false
+}
+
+func chainHandler(h interactionHandler, mw []interactionMiddleware) interactionHandler {
+	for n := len(mw) - 1; n >= 0; n-- {
+		h = mw[n](h)
+	}
+	return h
+}
+
+func (r *interactionRouter) Dispatch(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	h := func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if route, ok := r.resolve(i); ok {
+			chainHandler(route.handler, route.middleware)(s, i)
+		}
+	}
+	chainHandler(h, r.middleware)(s, i)
+}
+
+func withInFlightTracking(next interactionHandler) interactionHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		inFlightInteractions.Add(1)
+		defer inFlightInteractions.Add(-1)
+		defer trackInteraction(i)()
+		next(s, i)
+	}
+}
+
+func withRecovery(next interactionHandler) interactionHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		defer recoverInteraction(s, i)
+		next(s, i)
+	}
+}
+
+func rejectWhileDraining(next interactionHandler) interactionHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if draining.Load() {
+			respondDraining(s, i)
+			return
+		}
+		next(s, i)
+	}
+}
+
+func supportOnly(next interactionHandler) interactionHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if i.Member == nil || !hasSupportRole(i.Member) {
+			respondError(s, i, errNoSupportRole, nil)
+			return
+		}
+		next(s, i)
+	}
+}
+
+func adminOnly(next interactionHandler) interactionHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if !isAdministrator(i) {
+			respondError(s, i, errAdminOnly, nil)
+			return
+		}
+		next(s, i)
+	}
+}
+
+func ownerOnly(next interactionHandler) interactionHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		if !isGuildOwner(s, i) {
+			respondError(s, i, errOwnerOnly, nil)
+			return
+		}
+		next(s, i)
+	}
+}
+
+func validateTicketAction(next interactionHandler) interactionHandler {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+		action, ref := parseTicketComponentID(i.MessageComponentData().CustomID)
+		if validateTicketComponent(s, i, action, ref) == nil {
+			return
+		}
+		next(s, i)
+	}
+}
+
+func recordComponent(action string) interactionMiddleware {
+	return func(next interactionHandler) interactionHandler {
+		return func(s *discordgo.Session, i *discordgo.InteractionCreate) {
+			recordComponentInteraction(s, i, action)
+			next(s, i)
+		}
+	}
+}
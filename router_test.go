@@ -0,0 +1,33 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func componentInteraction(customID string) *discordgo.InteractionCreate {
+	return &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{CustomID: customID},
+	}}
+}
+
+func TestEveryTicketActionResolves(t *testing.T) {
+	previous := router
+	router = &interactionRouter{
+		commands:      make(map[string]interactionRoute),
+		components:    make(map[string]interactionRoute),
+		ticketActions: make(map[string]interactionRoute),
+	}
+	t.Cleanup(func() { router = previous })
+	registerInteractionRoutes()
+	for action := range ticketActionStatus {
+		for _, customID := range []string{action, action + ":일반민원-0007"} {
+			route, ok := router.resolve(componentInteraction(customID))
+			if !ok || route.handler == nil {
+				t.Errorf("custom ID %q does not resolve to a handler", customID)
+			}
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ruleEventAny      = "any"
+	ruleEventCreated  = "created"
+	ruleEventClaimed  = "claimed"
+	ruleEventClosed   = "closed"
+	ruleEventReopened = "reopened"
+	ruleEventUpdated  = "updated"
+
+	ruleActionAddRole  = "add_role"
+	ruleActionLog      = "log"
+	ruleActionPingRole = "ping_role"
+)
+
+var ruleEventChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "모든 이벤트", Value: ruleEventAny},
+	{Name: "티켓 생성", Value: ruleEventCreated},
+	{Name: "담당자 배정", Value: ruleEventClaimed},
+	{Name: "티켓 닫기", Value: ruleEventClosed},
+	{Name: "티켓 재오픈", Value: ruleEventReopened},
+	{Name: "태그/우선순위 변경", Value: ruleEventUpdated},
+}
+
+var ruleFieldChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "창구", Value: "category"},
+	{Name: "태그", Value: "tag"},
+	{Name: "우선순위", Value: "priority"},
+}
+
+var ruleActionChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "역할 추가", Value: ruleActionAddRole},
+	{Name: "채널에 기록", Value: ruleActionLog},
+	{Name: "역할 호출", Value: ruleActionPingRole},
+}
+
+var ticketPriorityChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "낮음", Value: "낮음"},
+	{Name: "보통", Value: "보통"},
+	{Name: "높음", Value: "높음"},
+	{Name: "긴급", Value: "긴급"},
+}
+
+type ticketRule struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty"`
+	GuildID   string             `bson:"guild_id"`
+	Event     string             `bson:"event"`
+	Field     string             `bson:"field"`
+	Value     string             `bson:"value"`
+	Action    string             `bson:"action"`
+	TargetID  string             `bson:"target_id"`
+	CreatedBy string             `bson:"created_by"`
+	CreatedAt time.Time          `bson:"created_at"`
+}
+
+func (r ticketRule) matches(t *ticket, event string) bool {
+	if r.Event != ruleEventAny && r.Event != event {
+		return false
+	}
+	switch r.Field {
+	case "category":
+		return t.Category == r.Value
+	case "tag":
+		return containsID(t.Tags, r.Value)
+	case "priority":
+		return t.Priority == r.Value
+	}
+	return false
+}
+
+func (r ticketRule) describe() string {
+	target := fmt.Sprintf("<@&%s>", r.TargetID)
+	if r.Action == ruleActionLog {
+		target = fmt.Sprintf("<#%s>", r.TargetID)
+	}
+	return fmt.Sprintf("[%s] %s=%s → %s %s", choiceName(ruleEventChoices, r.Event), choiceName(ruleFieldChoices, r.Field), r.Value, choiceName(ruleActionChoices, r.Action), target)
+}
+
+func choiceName(choices []*discordgo.ApplicationCommandOptionChoice, value string) string {
+	for _, choice := range choices {
+		if choice.Value == value {
+			return choice.Name
+		}
+	}
+	return value
+}
+
+func listRules() ([]ticketRule, error) {
+	cursor, err := app().Rules.Find(context.TODO(), bson.M{"guild_id": app().GuildID}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return nil, err
+	}
+	var rules []ticketRule
+	if err := cursor.All(context.TODO(), &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func evaluateRules(s *discordgo.Session, t *ticket, event string) {
+	publishTicketEvent(t, event)
+	rules, err := listRules()
+	if err != nil {
+		log.Printf("Could not load ticket rules for '%s': %v", t.Name(), err)
+		return
+	}
+	for _, rule := range rules {
+		if !rule.matches(t, event) {
+			continue
+		}
+		if err := applyRule(s, t, rule, event); err != nil {
+			log.Printf("Could not apply rule %s to ticket '%s': %v", rule.ID.Hex(), t.Name(), err)
+		}
+	}
+}
+
+func applyRule(s *discordgo.Session, t *ticket, rule ticketRule, event string) error {
+	switch rule.Action {
+	case ruleActionAddRole:
+		if t.Forum {
+			return fmt.Errorf("role overwrites are not supported on forum tickets")
+		}
+		if containsID(t.ParticipantRoles, rule.TargetID) {
+			return nil
+		}
+		if err := s.ChannelPermissionSet(t.ChannelID, rule.TargetID, discordgo.PermissionOverwriteTypeRole, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0); err != nil {
+			return err
+		}
+		t.ParticipantRoles = append(t.ParticipantRoles, rule.TargetID)
+		return updateTicket(t.ChannelID, bson.M{"$addToSet": bson.M{"participant_roles": rule.TargetID}})
+	case ruleActionLog:
+		notifyChannel(s, rule.TargetID, &discordgo.MessageEmbed{
+			Title:       "규칙 알림",
+			Description: fmt.Sprintf("<#%s> 티켓에서 규칙이 실행되었습니다.", t.ChannelID),
+			Color:       colorYellow,
+			Fields: []*discordgo.MessageEmbedField{
+				{Name: "티켓", Value: t.Name(), Inline: true},
+				{Name: "이벤트", Value: choiceName(ruleEventChoices, event), Inline: true},
+				{Name: "조건", Value: fmt.Sprintf("%s=%s", choiceName(ruleFieldChoices, rule.Field), rule.Value), Inline: true},
+			},
+			Timestamp: time.Now().In(kstLocation).Format(time.RFC3339),
+		})
+		return nil
+	case ruleActionPingRole:
+		_, err := s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+			Content: fmt.Sprintf("<@&%s> %s=%s 조건에 해당하는 티켓입니다. 확인 부탁드립니다.", rule.TargetID, choiceName(ruleFieldChoices, rule.Field), rule.Value),
+		})
+		return err
+	}
+	return fmt.Errorf("unknown rule action '%s'", rule.Action)
+}
+
+func rulesCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "규칙",
+		Description:              "티켓 이벤트에 따라 자동으로 실행되는 규칙을 관리합니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "목록", Description: "등록된 규칙을 확인합니다."},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "추가", Description: "새 규칙을 추가합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "field", Description: "조건 항목", Required: true, Choices: ruleFieldChoices},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "value", Description: "조건 값 (예: 환불, 긴급, 부패신고)", Required: true},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "action", Description: "실행할 동작", Required: true, Choices: ruleActionChoices},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "event", Description: "규칙을 확인할 이벤트 (기본: 모든 이벤트)", Required: false, Choices: ruleEventChoices},
+				{Type: discordgo.ApplicationCommandOptionRole, Name: "role", Description: "추가하거나 호출할 역할", Required: false},
+				{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "기록할 채널", Required: false, ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText}},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "삭제", Description: "규칙을 삭제합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "number", Description: "/규칙 목록에 표시된 번호", Required: true},
+			}},
+		},
+	}
+}
+
+func handleRules(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range sub.Options {
+		options[opt.Name] = opt
+	}
+	rules, err := listRules()
+	if err != nil {
+		respondError(s, i, errRuleSaveFailed, err)
+		return
+	}
+	var summary string
+	switch sub.Name {
+	case "목록":
+		var lines []string
+		for n, rule := range rules {
+			lines = append(lines, fmt.Sprintf("**%d.** %s", n+1, rule.describe()))
+		}
+		if len(lines) == 0 {
+			lines = append(lines, "등록된 규칙이 없습니다. /규칙 추가 명령어로 규칙을 만들 수 있습니다.")
+		}
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "티켓 규칙", Description: strings.Join(lines, "\n"), Color: colorBlue}}}})
+		return
+	case "추가":
+		rule := ticketRule{
+			GuildID:   app().GuildID,
+			Event:     ruleEventAny,
+			Field:     options["field"].StringValue(),
+			Value:     strings.TrimSpace(options["value"].StringValue()),
+			Action:    options["action"].StringValue(),
+			CreatedBy: i.Member.User.ID,
+			CreatedAt: time.Now(),
+		}
+		if event, ok := options["event"]; ok {
+			rule.Event = event.StringValue()
+		}
+		if rule.Action == ruleActionLog {
+			if channel, ok := options["channel"]; ok {
+				rule.TargetID = channel.ChannelValue(nil).ID
+			}
+		} else if role, ok := options["role"]; ok {
+			rule.TargetID = role.RoleValue(nil, "").ID
+		}
+		if rule.TargetID == "" {
+			respondError(s, i, errRuleTargetMissing, nil)
+			return
+		}
+		if _, err := app().Rules.InsertOne(context.TODO(), rule); err != nil {
+			respondError(s, i, errRuleSaveFailed, err)
+			return
+		}
+		summary = "규칙을 추가했습니다.\n" + rule.describe()
+	case "삭제":
+		n := int(options["number"].IntValue())
+		if n < 1 || n > len(rules) {
+			respondError(s, i, errRuleNotFound, nil, n)
+			return
+		}
+		rule := rules[n-1]
+		if _, err := app().Rules.DeleteOne(context.TODO(), bson.M{"_id": rule.ID}); err != nil {
+			respondError(s, i, errRuleSaveFailed, err)
+			return
+		}
+		summary = "규칙을 삭제했습니다.\n" + rule.describe()
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "규칙 변경", Description: summary, Color: colorGreen}}}})
+}
+
+func handleTicketTag(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	tag := strings.TrimSpace(i.ApplicationCommandData().Options[0].StringValue())
+	update := bson.M{"$addToSet": bson.M{"tags": tag}}
+	description := fmt.Sprintf("태그 '%s'을(를) 추가했습니다.", tag)
+	if containsID(t.Tags, tag) {
+		update = bson.M{"$pull": bson.M{"tags": tag}}
+		description = fmt.Sprintf("태그 '%s'을(를) 제거했습니다.", tag)
+		var remaining []string
+		for _, v := range t.Tags {
+			if v != tag {
+				remaining = append(remaining, v)
+			}
+		}
+		t.Tags = remaining
+	} else {
+		t.Tags = append(t.Tags, tag)
+	}
+	if err := updateTicket(t.ChannelID, update); err != nil {
+		respondError(s, i, errTicketSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "태그 변경", Description: description, Color: colorBlue}}}})
+	refreshSpecialistRecommendation(s, t)
+	evaluateRules(s, t, ruleEventUpdated)
+}
+
+func handleTicketPriority(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	t.Priority = i.ApplicationCommandData().Options[0].StringValue()
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"priority": t.Priority}}); err != nil {
+		respondError(s, i, errTicketSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "우선순위 변경", Description: fmt.Sprintf("<@%s> 님이 우선순위를 '%s'(으)로 변경했습니다.", i.Member.User.ID, t.Priority), Color: colorBlue}}}})
+	refreshUrgencyField(s, t)
+	if t.Priority == urgentPriority {
+		alertUrgentTicket(t, fmt.Sprintf("%s 티켓의 우선순위가 긴급으로 변경되었습니다.", t.Name()))
+	}
+	evaluateRules(s, t, ruleEventUpdated)
+}
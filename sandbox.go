@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const sandboxTopicTag = " | Sandbox"
+
+type sandboxMode struct {
+	Enabled    bool      `bson:"enabled"`
+	CategoryID string    `bson:"category_id,omitempty"`
+	EnabledBy  string    `bson:"enabled_by,omitempty"`
+	EnabledAt  time.Time `bson:"enabled_at,omitempty"`
+}
+
+func sandboxActive() bool {
+	return getConfig().Sandbox.Enabled
+}
+
+func opensSandboxTicket(i *discordgo.InteractionCreate, ownerID string) bool {
+	if isLoadTestInteraction(i) {
+		return true
+	}
+	return sandboxActive() && i.Member != nil && ownerID == i.Member.User.ID && hasSupportRole(i.Member)
+}
+
+func isSandboxTicket(ch *discordgo.Channel) bool {
+	if ch == nil {
+		return false
+	}
+	t := ticketForChannel(ch)
+	return t != nil && t.Sandbox
+}
+
+func allowDestructive(ch *discordgo.Channel) bool {
+	return !sandboxActive() || isSandboxTicket(ch)
+}
+
+func sandboxCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "연습모드",
+		Description:              "담당자 교육용 연습 모드를 켜거나 끕니다. 연습 중 담당자가 직접 연 티켓은 연습용으로 만들어집니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "연습 모드 여부", Required: true},
+			{Type: discordgo.ApplicationCommandOptionChannel, Name: "category", Description: "연습용 티켓을 만들 카테고리", Required: false, ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildCategory}},
+		},
+	}
+}
+
+func handleSandboxCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	enabled := options["enabled"].BoolValue()
+	categoryID := getConfig().Sandbox.CategoryID
+	if opt, ok := options["category"]; ok {
+		categoryID = opt.ChannelValue(nil).ID
+	}
+	if enabled && categoryID == "" {
+		respondError(s, i, errSandboxCategoryUnset, nil)
+		return
+	}
+	err := updateConfig(func(cfg *guildConfig) {
+		cfg.Sandbox = sandboxMode{Enabled: enabled, CategoryID: categoryID}
+		if enabled {
+			cfg.Sandbox.EnabledBy, cfg.Sandbox.EnabledAt = i.Member.User.ID, time.Now()
+		}
+	})
+	if err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	embed := &discordgo.MessageEmbed{Title: "연습 모드 종료", Description: "연습 모드를 껐습니다. 담당자가 직접 연 티켓도 다시 실제 민원으로 접수됩니다.", Color: colorGreen}
+	if enabled {
+		embed = &discordgo.MessageEmbed{
+			Title:       "연습 모드 시작",
+			Description: fmt.Sprintf("담당자가 자신의 이름으로 연 티켓은 <#%s> 카테고리에 연습용으로 만들어집니다. 민원인이 연 티켓은 평소처럼 접수됩니다.\n연습용 티켓의 외부 알림, 이벤트와 오류 보고는 전송하지 않고 로그에만 남기며, 연습 중에는 연습용이 아닌 티켓 채널이 삭제되지 않습니다.", categoryID),
+			Color:       colorYellow,
+		}
+	}
+	log.Printf("%s turned sandbox mode %t (category %s).", i.Member.User.Username, enabled, categoryID)
+	notifyChannel(s, getConfig().LogChannelID, &discordgo.MessageEmbed{Title: embed.Title, Description: fmt.Sprintf("<@%s> 님이 변경했습니다.", i.Member.User.ID), Color: embed.Color, Timestamp: time.Now().In(kstLocation).Format(time.RFC3339)})
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const schedulerTick = time.Minute
+
+var (
+	scheduledJobs []*scheduledJob
+	jobsMu        sync.Mutex
+	schedulerOnce sync.Once
+)
+
+type scheduledJob struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+	running  atomic.Bool
+}
+
+type jobState struct {
+	Name         string        `bson:"_id"`
+	NextRun      time.Time     `bson:"next_run"`
+	LastRun      time.Time     `bson:"last_run,omitempty"`
+	LastDuration time.Duration `bson:"last_duration,omitempty"`
+	LastError    string        `bson:"last_error,omitempty"`
+}
+
+func registerJob(name string, interval time.Duration, run func() error) {
+	jobsMu.Lock()
+	defer jobsMu.Unlock()
+	scheduledJobs = append(scheduledJobs, &scheduledJob{Name: name, Interval: interval, Run: run})
+	log.Printf("Registered scheduled job '%s' every %s.", name, interval)
+}
+
+func registerBuiltinJobs(s *discordgo.Session) {
+	registerJob("status_board", statusBoardInterval, func() error { return refreshStatusBoard(s) })
+	registerJob("inactivity_check", inactivityCheckInterval, func() error { return checkInactiveTickets(s) })
+	registerJob("intake_lockdown", lockdownCheckInterval, func() error { return liftExpiredLockdown(s) })
+	registerJob("sla_escalation", slaEscalationCheckInterval, func() error { return escalateBreachedTickets(s) })
+	registerJob("idle_assignees", assigneeIdleCheckInterval, func() error { return releaseIdleAssignments(s) })
+	registerJob("closed_cleanup_report", closedCleanupInterval, func() error { return postClosedCleanupReport(s) })
+	registerJob("weekly_leaderboard", leaderboardCheckInterval, func() error { return postWeeklyLeaderboard(s) })
+	registerJob("component_rate_limit_prune", componentPruneInterval, pruneComponentBuckets)
+	if transcriptArchiveAge() > 0 {
+		registerJob("transcript_archive", transcriptArchiveInterval, archiveTranscriptsJob)
+	} else {
+		log.Println("Transcript archival is disabled.")
+	}
+}
+
+func runScheduler() {
+	ticker := time.NewTicker(schedulerTick)
+	defer ticker.Stop()
+	for {
+		if draining.Load() {
+			return
+		}
+		jobsMu.Lock()
+		jobs := append([]*scheduledJob(nil), scheduledJobs...)
+		jobsMu.Unlock()
+		for _, job := range jobs {
+			if job.running.Load() {
+				continue
+			}
+			due, err := claimJob(job, time.Now())
+			if err != nil {
+				log.Printf("Could not check schedule for job '%s': %v", job.Name, err)
+				continue
+			}
+			if due {
+				go runJob(job)
+			}
+		}
+		<-ticker.C
+	}
+}
+
+func claimJob(job *scheduledJob, now time.Time) (bool, error) {
+	filter := bson.M{"_id": job.Name, "$or": []bson.M{{"next_run": bson.M{"$lte": now}}, {"next_run": bson.M{"$exists": false}}}}
+	update := bson.M{"$set": bson.M{"next_run": now.Add(job.Interval)}}
+	result, err := app().Jobs.UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0 || result.UpsertedCount > 0, nil
+}
+
+func runJob(job *scheduledJob) {
+	if !job.running.CompareAndSwap(false, true) {
+		return
+	}
+	defer job.running.Store(false)
+	started := time.Now()
+	err := job.Run()
+	state := bson.M{"last_run": started, "last_duration": time.Since(started)}
+	update := bson.M{"$set": state, "$unset": bson.M{"last_error": ""}}
+	if err != nil {
+		log.Printf("Scheduled job '%s' failed: %v", job.Name, err)
+		state["last_error"] = err.Error()
+		update = bson.M{"$set": state}
+	}
+	if _, err := app().Jobs.UpdateOne(context.TODO(), bson.M{"_id": job.Name}, update); err != nil {
+		log.Printf("Could not record run of job '%s': %v", job.Name, err)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const sealedPrefix = "sealed:v1:"
+
+var errTranscriptSealed = errors.New("transcript is sealed")
+
+type sealedIntake struct {
+	Nickname string         `json:"nickname,omitempty"`
+	Subject  string         `json:"subject,omitempty"`
+	Content  string         `json:"content,omitempty"`
+	Intake   []intakeAnswer `json:"intake,omitempty"`
+}
+
+func sealingKey() ([]byte, error) {
+	encoded := os.Getenv("SENSITIVE_DATA_KEY")
+	if path := os.Getenv("SENSITIVE_DATA_KEY_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read SENSITIVE_DATA_KEY_FILE: %w", err)
+		}
+		encoded = string(data)
+	}
+	if encoded == "" {
+		return nil, errors.New("SENSITIVE_DATA_KEY is not set")
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("sensitive data key is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("sensitive data key must be 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+func sealingCipher() (cipher.AEAD, error) {
+	key, err := sealingKey()
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(plaintext []byte) (string, error) {
+	aead, err := sealingCipher()
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return sealedPrefix + base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func unseal(sealed string) ([]byte, error) {
+	if !strings.HasPrefix(sealed, sealedPrefix) {
+		return nil, errors.New("value is not sealed")
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sealed, sealedPrefix))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := sealingCipher()
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("sealed value is truncated")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+func sealTicketIntake(t *ticket) error {
+	data, err := json.Marshal(sealedIntake{Nickname: t.Nickname, Subject: t.Subject, Content: t.Content, Intake: t.Intake})
+	if err != nil {
+		return err
+	}
+	sealed, err := seal(data)
+	if err != nil {
+		return err
+	}
+	t.SealedIntake = sealed
+	t.Nickname, t.Subject, t.Content, t.Intake = "", "", "", nil
+	return nil
+}
+
+func unsealTicketIntake(t *ticket) (*sealedIntake, error) {
+	data, err := unseal(t.SealedIntake)
+	if err != nil {
+		return nil, err
+	}
+	var intake sealedIntake
+	if err := json.Unmarshal(data, &intake); err != nil {
+		return nil, err
+	}
+	return &intake, nil
+}
+
+func sealedAccessCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "열람",
+		Description:              "암호화 보관된 티켓의 접수 내용과 대화록을 복호화해 확인합니다. 열람 기록이 남습니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "code", Description: "접수번호 (예: GW-2026-00123)", Required: true},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "reason", Description: "열람 사유", Required: true},
+		},
+	}
+}
+
+func handleSealedAccess(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	code := options["code"].StringValue()
+	reason := options["reason"].StringValue()
+	var t *ticket
+	if id, ok := parseTicketCode(code); ok {
+		t, _ = findTicketByID(id)
+	}
+	if t == nil {
+		respondError(s, i, errSealedTicketMissing, nil, code)
+		return
+	}
+	raw, err := loadStoredTranscript(t.ChannelID)
+	hasTranscript := err == nil && strings.HasPrefix(raw, sealedPrefix)
+	if t.SealedIntake == "" && !hasTranscript {
+		respondError(s, i, errSealedTicketMissing, nil, code)
+		return
+	}
+	embed := &discordgo.MessageEmbed{Title: fmt.Sprintf("%s 암호화 기록", t.Name()), Description: fmt.Sprintf("접수번호 %s · 열람 사유: %s", t.Code, reason), Color: colorBlue}
+	if t.SealedIntake != "" {
+		intake, err := unsealTicketIntake(t)
+		if err != nil {
+			respondError(s, i, errUnsealFailed, err)
+			return
+		}
+		embed.Fields = intakeFields(intake.Intake, false)
+	}
+	var files []*discordgo.File
+	if hasTranscript {
+		data, err := unseal(raw)
+		if err != nil {
+			respondError(s, i, errUnsealFailed, err)
+			return
+		}
+		files = append(files, &discordgo.File{Name: fmt.Sprintf("transcript-%s.html", t.Name()), ContentType: "text/html", Reader: bytes.NewReader(data)})
+	}
+	log.Printf("%s unsealed ticket '%s' (%s): %s", i.Member.User.ID, t.Name(), t.Code, reason)
+	event := ticketEvent{At: time.Now(), UserID: i.Member.User.ID, UserName: memberDisplayName(i.Member), Kind: ticketEventUnseal, Action: reason}
+	if err := updateTicket(t.ChannelID, bson.M{"$push": bson.M{"events": event}}); err != nil {
+		log.Printf("Could not record unseal event for '%s': %v", t.Name(), err)
+	}
+	s.ChannelMessageSendEmbed(getConfig().LogChannelID, &discordgo.MessageEmbed{
+		Title:       "암호화 기록 열람",
+		Description: fmt.Sprintf("<@%s> 님이 %s (%s)의 암호화 기록을 열람했습니다.", i.Member.User.ID, t.Name(), t.Code),
+		Color:       colorYellow,
+		Fields:      []*discordgo.MessageEmbedField{{Name: "사유", Value: reason, Inline: false}},
+		Timestamp:   time.Now().In(kstLocation).Format(time.RFC3339),
+	})
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}, Files: files}})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxSearchResults = 10
+	searchExcerptLen = 80
+)
+
+func searchCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "검색",
+		Description: "제목, 접수 내용, 종료 사유에서 키워드로 지난 티켓을 찾습니다.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "query", Description: "검색어 (띄어쓰기로 여러 단어, \"따옴표\"로 정확한 구절)", Required: true},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: false, Choices: ticketTopicChoices()},
+		},
+	}
+}
+
+func searchExcerpt(t ticket) string {
+	text := t.Subject
+	if text == "" {
+		text = t.Content
+	}
+	if text == "" {
+		for _, a := range t.Intake {
+			if a.Value != "" && a.ID != urgencyQuestionID && a.ID != "nickname" {
+				text = a.Value
+				break
+			}
+		}
+	}
+	if text == "" {
+		text = t.CloseReason
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > searchExcerptLen {
+		text = string(runes[:searchExcerptLen]) + "…"
+	}
+	return text
+}
+
+func handleSearch(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	filter := bson.M{}
+	query := ""
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "query":
+			query = strings.TrimSpace(opt.StringValue())
+		case "topic":
+			filter["category"] = opt.StringValue()
+		}
+	}
+	filter["$text"] = bson.M{"$search": query}
+	filter["status"] = bson.M{"$ne": ticketStatusDeleted}
+	opts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "created_at", Value: -1}}).
+		SetLimit(maxSearchResults)
+	cursor, err := app().Tickets.Find(context.TODO(), filter, opts)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	embed := &discordgo.MessageEmbed{Title: fmt.Sprintf("'%s' 검색 결과", query), Color: colorBlue}
+	if len(tickets) == 0 {
+		embed.Description = "일치하는 티켓이 없습니다. 다른 단어로 다시 검색해보세요."
+	}
+	for _, t := range tickets {
+		value := fmt.Sprintf("%s · %s · <t:%d:D>", t.Code, ticketStatusLabel(t), t.CreatedAt.Unix())
+		if excerpt := searchExcerpt(t); excerpt != "" {
+			value = excerpt + "\n" + value
+		}
+		if t.CloseCode != "" {
+			value += " · " + t.CloseCode
+		}
+		switch {
+		case t.Status == ticketStatusOpen:
+			value += fmt.Sprintf(" · <#%s>", t.ChannelID)
+		case t.TranscriptLink != "":
+			value += fmt.Sprintf(" · [대화록](%s)", t.TranscriptLink)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: t.Name(), Value: value, Inline: false})
+	}
+	if len(tickets) == maxSearchResults {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("관련도가 높은 %d건만 표시합니다.", maxSearchResults)}
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
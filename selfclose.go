@@ -0,0 +1,50 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const defaultSelfCloseCooldown = 10 * time.Minute
+
+func selfCloseCooldown() time.Duration {
+	v := os.Getenv("SELF_CLOSE_COOLDOWN")
+	if v == "" {
+		return defaultSelfCloseCooldown
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Invalid SELF_CLOSE_COOLDOWN '%s': %v", v, err)
+		return defaultSelfCloseCooldown
+	}
+	return d
+}
+
+func handleSelfCloseRequest(s *discordgo.Session, i *discordgo.InteractionCreate, t *ticket) {
+	if remaining := selfCloseCooldown() - time.Since(t.CreatedAt); remaining > 0 {
+		respondError(s, i, errSelfCloseCooldown, nil, remaining.Round(time.Second).String())
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "정말 해결되었나요?", Description: "문의하신 내용이 모두 해결되었다면 아래 버튼을 눌러 티켓을 닫아주세요.\n닫힌 티켓은 관리자만 다시 열 수 있습니다.", Color: colorYellow}}, Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{discordgo.Button{Label: "네, 해결되었습니다", Style: discordgo.SuccessButton, CustomID: ticketComponentID(actionConfirmSelf, t)}, discordgo.Button{Label: "아직이요", Style: discordgo.SecondaryButton, CustomID: "cancel_close_ticket"}}}}}})
+}
+
+func handleConfirmSelfClose(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	if t.OwnerID != i.Member.User.ID {
+		respondError(s, i, errNotTicketOwner, nil)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "처리 중...", Description: "티켓을 해결됨으로 닫고 있습니다. 만족도 조사가 DM으로 전송됩니다.", Color: colorGray}}, Components: []discordgo.MessageComponent{}}})
+	t.CloseCode = closeCodeResolved
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"close_code": t.CloseCode}}); err != nil {
+		log.Printf("Error recording self-close code: %v", err)
+	}
+	closeTicketChannel(s, t, i.Member.User.ID, true)
+}
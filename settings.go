@@ -0,0 +1,696 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+var settingsFeatureChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "대화록", Value: "transcripts"},
+	{Name: "만족도 조사", Value: "csat"},
+	{Name: "자동 배정", Value: "auto_assign"},
+	{Name: "익명 모드", Value: "anonymous"},
+	{Name: "암호화 보관", Value: "encrypted"},
+}
+
+func ticketTopicChoices() []*discordgo.ApplicationCommandOptionChoice {
+	var choices []*discordgo.ApplicationCommandOptionChoice
+	for _, option := range ticketOptions() {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: option.Label, Value: option.Value})
+	}
+	return choices
+}
+
+func settingsCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	zeroValue := 0.0
+	oneValue := 1.0
+	twoLength := 2
+	return &discordgo.ApplicationCommand{
+		Name:                     "설정",
+		Description:              "봇의 채널, 카테고리, 역할 설정을 변경합니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "보기", Description: "현재 설정을 확인합니다."},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "기관", Description: "기관명, 접수 인사말, 닫힌 티켓 보관 기간을 지정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "name", Description: "기관명 (패널 제목과 로그 하단에 표시)", Required: false, MaxLength: 50},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "greeting", Description: "접수 인사말, {user}는 민원인 멘션으로 바뀝니다 (\\n으로 줄바꿈)", Required: false, MaxLength: 1000},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "code_prefix", Description: "접수번호 앞자리 영문 대문자 2~6자 (예: GW → GW-2026-00001)", Required: false, MinLength: &twoLength, MaxLength: 6},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "retention_days", Description: "닫힌 티켓 채널 보관 일수", Required: false, MinValue: &oneValue},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "로그채널", Description: "대화록을 보낼 로그 채널을 지정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "로그 채널", Required: true, ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText}},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "열림카테고리", Description: "열린 티켓이 생성될 카테고리를 지정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionChannel, Name: "category", Description: "열린 티켓 카테고리", Required: true, ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildCategory}},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "닫힘카테고리", Description: "닫힌 티켓이 이동할 카테고리를 지정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionChannel, Name: "category", Description: "닫힌 티켓 카테고리", Required: true, ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildCategory}},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "지원역할", Description: "창구별 지원 역할을 지정합니다. 창구를 비우면 기본 지원 역할을 변경합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionRole, Name: "role", Description: "지원 역할", Required: true},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: false, Choices: ticketTopicChoices()},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "호출", Description: "담당자 개별 호출 기준과 재배정 추천 기준을 지정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "threshold", Description: "개별 호출로 전환할 창구별 미배정 티켓 수", Required: false},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "agents", Description: "호출할 담당자 수", Required: false},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "idle_hours", Description: "담당자가 이 시간(시간) 동안 응답하지 않으면 배정 해제, 0이면 끔", Required: false, MinValue: &zeroValue},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "max_claims", Description: "1인당 담당 티켓 한도, 넘으면 재배정 추천, 0이면 끔", Required: false, MinValue: &zeroValue},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "기능", Description: "창구별 기능을 켜거나 끕니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: true, Choices: ticketTopicChoices()},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "feature", Description: "기능", Required: true, Choices: settingsFeatureChoices},
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "사용 여부", Required: true},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "티켓방식", Description: "티켓을 개별 채널 또는 포럼 게시글로 만듭니다. 포럼 게시글은 포럼을 볼 수 있는 모든 사람에게 보입니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "mode", Description: "티켓 방식", Required: true, Choices: ticketModeChoices},
+				{Type: discordgo.ApplicationCommandOptionChannel, Name: "forum", Description: "포럼 게시글 방식에서 사용할 포럼 채널", Required: false, ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildForum}},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "인증", Description: "민원 접수 전 본인 확인 절차를 설정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "사용 여부", Required: true},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "min_account_days", Description: "최소 계정 생성 일수 (0이면 확인 안 함)", Required: false},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "min_member_days", Description: "서버 참여 후 최소 일수 (0이면 확인 안 함)", Required: false, MinValue: &zeroValue},
+				{Type: discordgo.ApplicationCommandOptionRole, Name: "required_role", Description: "접수에 필요한 역할", Required: false},
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "challenge", Description: "버튼 확인 사용 여부", Required: false},
+				{Type: discordgo.ApplicationCommandOptionRole, Name: "bypass_role", Description: "확인 절차를 면제할 역할", Required: false},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "버튼기록", Description: "버튼 조작 기록을 티켓 채널에도 표시할지 정합니다. 대화록에는 항상 남습니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "채널 표시 여부", Required: true},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "대화록", Description: "대화록 HTML의 기본 테마와 간결 모드를 지정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "theme", Description: "테마", Required: true, Choices: transcriptThemeChoices},
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "compact", Description: "같은 작성자의 연속 메시지를 묶어서 표시", Required: false},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "내보내기서식", Description: "보고서 CSV/Excel 파일의 날짜 형식과 숫자 쉼표 사용 여부를 지정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "date_style", Description: "날짜 형식", Required: true, Choices: exportDateChoices},
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "thousand_separator", Description: "숫자에 천 단위 쉼표 표시", Required: false},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "접수제한", Description: "사용자별 티켓 생성 간격과 동시에 열어둘 수 있는 티켓 수를 지정합니다. 지원팀은 제외됩니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "cooldown_minutes", Description: "새 티켓 생성 간격(분), 0이면 사용 안 함", Required: false, MinValue: &zeroValue},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "max_open", Description: "최대 열린 티켓 수, 0이면 제한 없음", Required: false, MinValue: &zeroValue},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "guild_per_minute", Description: "서버 전체 분당 최대 티켓 생성 수, 넘으면 접수를 자동으로 잠급니다 (0이면 사용 안 함)", Required: false, MinValue: &zeroValue},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "lockdown_minutes", Description: "자동 잠금 시간(분), 기본 15분", Required: false, MinValue: &oneValue},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "스팸검사", Description: "새 계정, 중복 내용, 링크 수로 접수를 점수화해 기준 이상이면 검토 역할에게 먼저 배정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "사용 여부", Required: true},
+				{Type: discordgo.ApplicationCommandOptionRole, Name: "moderator_role", Description: "격리된 티켓을 검토할 역할", Required: false},
+				{Type: discordgo.ApplicationCommandOptionChannel, Name: "review_category", Description: "격리된 티켓을 만들 카테고리 (기본: 열린 티켓 카테고리)", Required: false, ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildCategory}},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "threshold", Description: "격리할 최소 점수 (기본 3)", Required: false, MinValue: &oneValue},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "번호재사용", Description: "티켓 생성에 실패해 쓰이지 않은 번호를 다음 티켓에 다시 발급할지 정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "재사용 여부", Required: true},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "음성기록", Description: "티켓 음성 상담 채널의 입장/퇴장 시각을 기록해 대화록에 포함할지 정합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "enabled", Description: "기록 여부", Required: true},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "현황판", Description: "공개 민원 처리 현황판을 게시할 채널을 지정합니다. 채널을 비우면 현황판을 끕니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "현황판 채널", Required: false, ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText}},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "자동종료", Description: "창구별 무응답 경고 및 자동 종료 기준을 지정합니다. 0을 입력하면 끕니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: true, Choices: ticketTopicChoices()},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "warn_hours", Description: "마지막 메시지 후 경고까지의 시간", Required: true, MinValue: &zeroValue},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "close_hours", Description: "경고 후 자동 종료까지의 시간", Required: true, MinValue: &zeroValue},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "언어", Description: "업무 언어를 지정합니다. 다른 언어로 접수된 민원은 번역 도우미가 켜집니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "language", Description: "업무 언어", Required: true, Choices: languageChoices()},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "sla", Description: "창구별 첫 응답 및 해결 기한을 지정합니다. 0을 입력하면 끕니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: true, Choices: ticketTopicChoices()},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "first_response", Description: "첫 응답 기한 (예: 30m, 4h)", Required: true},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "resolution", Description: "해결 기한 (예: 4h, 2d)", Required: true},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "에스컬레이션", Description: "첫 응답 기한을 넘긴 미배정 티켓에 호출할 역할을 지정합니다. 역할을 비우면 끕니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionRole, Name: "role", Description: "호출할 역할", Required: false},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "interval", Description: "배정 전까지 다시 알릴 간격 (예: 30m, 0이면 1회만, 기본 30m)", Required: false},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "첨부서류", Description: "창구별로 접수 직후 서류 첨부를 요청합니다. 0개를 입력하면 끕니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: true, Choices: ticketTopicChoices()},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "count", Description: "받아야 할 최소 파일 수", Required: true, MinValue: &zeroValue},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "types", Description: "허용할 확장자 (예: pdf,jpg,png, 비우면 모든 형식)", Required: false},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "max_mb", Description: "파일당 최대 크기 MB (기본 8)", Required: false, MinValue: &oneValue},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "prompt", Description: "요청 안내문 (기본: 증빙서류를 첨부해주세요.)", Required: false, MaxLength: 1000},
+			}},
+			intakeQuestionSettingsGroup(),
+			ticketPinSettingsGroup(),
+		},
+	}
+}
+
+func isAdministrator(i *discordgo.InteractionCreate) bool {
+	return i.Member != nil && i.Member.Permissions&discordgo.PermissionAdministrator == discordgo.PermissionAdministrator
+}
+
+func isGuildOwner(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	if i.Member == nil || i.GuildID == "" {
+		return false
+	}
+	guild, err := s.State.Guild(i.GuildID)
+	if err != nil {
+		if guild, err = s.Guild(i.GuildID); err != nil {
+			return false
+		}
+	}
+	return guild.OwnerID == i.Member.User.ID
+}
+
+func handleSettings(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	if sub.Type == discordgo.ApplicationCommandOptionSubCommandGroup && sub.Name == "질문" {
+		handleIntakeQuestionSettings(s, i, sub.Options[0])
+		return
+	}
+	if sub.Type == discordgo.ApplicationCommandOptionSubCommandGroup && sub.Name == "안내" {
+		handleTicketPinSettings(s, i, sub.Options[0])
+		return
+	}
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range sub.Options {
+		options[opt.Name] = opt
+	}
+	var summary string
+	var apply func(cfg *guildConfig)
+	switch sub.Name {
+	case "보기":
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{settingsEmbed(getConfig())}}})
+		return
+	case "기관":
+		cfg := getConfig()
+		name, greeting, retention, prefix := institutionName(), cfg.Greeting, cfg.ClosedRetentionDays, ticketCodePrefix()
+		if opt, ok := options["name"]; ok {
+			name = strings.TrimSpace(opt.StringValue())
+		}
+		if opt, ok := options["greeting"]; ok {
+			greeting = strings.ReplaceAll(opt.StringValue(), "\\n", "\n")
+		}
+		if opt, ok := options["retention_days"]; ok {
+			retention = int(opt.IntValue())
+		}
+		if opt, ok := options["code_prefix"]; ok {
+			prefix = strings.ToUpper(strings.TrimSpace(opt.StringValue()))
+			if !ticketCodePrefixPattern.MatchString(prefix) {
+				respondError(s, i, errInvalidCodePrefix, nil, prefix)
+				return
+			}
+		}
+		summary = fmt.Sprintf("기관 정보를 변경했습니다: %s · 접수번호 %s · 닫힌 티켓 %s 보관", name, prefix, retentionLabel(retention))
+		apply = func(cfg *guildConfig) {
+			cfg.TicketCodePrefix = prefix
+			cfg.InstitutionName = name
+			cfg.Greeting = greeting
+			cfg.ClosedRetentionDays = retention
+		}
+	case "로그채널":
+		channelID := options["channel"].ChannelValue(nil).ID
+		summary = fmt.Sprintf("로그 채널을 <#%s>(으)로 변경했습니다.", channelID)
+		apply = func(cfg *guildConfig) { cfg.LogChannelID = channelID }
+	case "열림카테고리":
+		categoryID := options["category"].ChannelValue(nil).ID
+		summary = fmt.Sprintf("열린 티켓 카테고리를 <#%s>(으)로 변경했습니다.", categoryID)
+		apply = func(cfg *guildConfig) { cfg.OpenCategoryID = categoryID }
+	case "닫힘카테고리":
+		categoryID := options["category"].ChannelValue(nil).ID
+		summary = fmt.Sprintf("닫힌 티켓 카테고리를 <#%s>(으)로 변경했습니다.", categoryID)
+		apply = func(cfg *guildConfig) { cfg.ClosedCategoryID = categoryID }
+	case "지원역할":
+		roleID := options["role"].RoleValue(nil, "").ID
+		if topic, ok := options["topic"]; ok {
+			summary = fmt.Sprintf("%s 창구의 지원 역할을 <@&%s>(으)로 변경했습니다.", topic.StringValue(), roleID)
+			apply = func(cfg *guildConfig) { cfg.CategorySupportRoles[topic.StringValue()] = roleID }
+		} else {
+			summary = fmt.Sprintf("기본 지원 역할을 <@&%s>(으)로 변경했습니다.", roleID)
+			apply = func(cfg *guildConfig) { cfg.DefaultSupportRoleID = roleID }
+		}
+	case "호출":
+		cfg := getConfig()
+		threshold, agents, reassign := cfg.PingThreshold, cfg.PingAgentCount, cfg.Reassignment
+		if opt, ok := options["threshold"]; ok {
+			threshold = int(opt.IntValue())
+		}
+		if opt, ok := options["agents"]; ok {
+			agents = int(opt.IntValue())
+		}
+		if opt, ok := options["idle_hours"]; ok {
+			reassign.IdleAfter = time.Duration(opt.IntValue()) * time.Hour
+		}
+		if opt, ok := options["max_claims"]; ok {
+			reassign.MaxClaims = int(opt.IntValue())
+		}
+		summary = fmt.Sprintf("미배정 티켓이 %d개 이상이면 업무량이 적은 근무 중 담당자 %d명을 호출합니다.\n%s", threshold, agents, reassign.label())
+		apply = func(cfg *guildConfig) {
+			cfg.PingThreshold = threshold
+			cfg.PingAgentCount = agents
+			cfg.Reassignment = reassign
+		}
+	case "인증":
+		v := getConfig().Verification
+		v.Enabled = options["enabled"].BoolValue()
+		if opt, ok := options["min_account_days"]; ok {
+			v.MinAccountAgeDays = int(opt.IntValue())
+		}
+		if opt, ok := options["min_member_days"]; ok {
+			v.MinMemberDays = int(opt.IntValue())
+		}
+		if opt, ok := options["required_role"]; ok {
+			v.RequiredRoleID = opt.RoleValue(nil, "").ID
+		}
+		if opt, ok := options["challenge"]; ok {
+			v.ButtonChallenge = opt.BoolValue()
+		}
+		if opt, ok := options["bypass_role"]; ok {
+			v.BypassRoleID = opt.RoleValue(nil, "").ID
+		}
+		summary = "접수 전 확인 절차를 변경했습니다: " + verificationSummary(v)
+		apply = func(cfg *guildConfig) { cfg.Verification = v }
+	case "버튼기록":
+		enabled := options["enabled"].BoolValue()
+		summary = fmt.Sprintf("티켓 채널의 버튼 조작 기록 표시를 '%s'(으)로 변경했습니다.", onOffLabel(enabled))
+		apply = func(cfg *guildConfig) { cfg.PostInteractionEvents = enabled }
+	case "대화록":
+		style := transcriptStyle{Theme: options["theme"].StringValue()}
+		if opt, ok := options["compact"]; ok {
+			style.Compact = opt.BoolValue()
+		}
+		summary = fmt.Sprintf("대화록 기본 스타일을 '%s'(으)로 변경했습니다.", style.label())
+		apply = func(cfg *guildConfig) { cfg.TranscriptStyle = style }
+	case "내보내기서식":
+		locale := exportLocale{DateStyle: options["date_style"].StringValue()}
+		if opt, ok := options["thousand_separator"]; ok {
+			locale.ThousandSeparator = opt.BoolValue()
+		}
+		summary = fmt.Sprintf("보고서 서식을 '%s'(으)로 변경했습니다.", locale.label())
+		apply = func(cfg *guildConfig) { cfg.ExportLocale = locale }
+	case "접수제한":
+		cfg := getConfig()
+		cooldown, limit, throttle := cfg.TicketCooldown, cfg.MaxOpenTickets, cfg.RaidThrottle
+		if opt, ok := options["cooldown_minutes"]; ok {
+			cooldown = time.Duration(opt.IntValue()) * time.Minute
+		}
+		if opt, ok := options["max_open"]; ok {
+			limit = int(opt.IntValue())
+		}
+		if opt, ok := options["guild_per_minute"]; ok {
+			throttle.PerMinute = int(opt.IntValue())
+		}
+		if opt, ok := options["lockdown_minutes"]; ok {
+			throttle.LockdownFor = time.Duration(opt.IntValue()) * time.Minute
+		}
+		summary = fmt.Sprintf("접수 제한을 변경했습니다: 간격 %s · 열린 티켓 %s · 폭주 방지 %s", cooldownLabel(cooldown), openTicketLimitLabel(limit), throttle.label())
+		apply = func(cfg *guildConfig) {
+			cfg.TicketCooldown = cooldown
+			cfg.MaxOpenTickets = limit
+			cfg.RaidThrottle = throttle
+		}
+	case "스팸검사":
+		p := getConfig().SpamPolicy
+		p.Enabled = options["enabled"].BoolValue()
+		if opt, ok := options["moderator_role"]; ok {
+			p.ModeratorRoleID = opt.RoleValue(nil, "").ID
+		}
+		if opt, ok := options["review_category"]; ok {
+			p.ReviewCategoryID = opt.ChannelValue(nil).ID
+		}
+		if opt, ok := options["threshold"]; ok {
+			p.Threshold = int(opt.IntValue())
+		}
+		if p.Enabled && p.ModeratorRoleID == "" {
+			respondError(s, i, errSpamModeratorUnset, nil)
+			return
+		}
+		summary = "스팸 검사를 변경했습니다: " + spamPolicySummary(p)
+		apply = func(cfg *guildConfig) { cfg.SpamPolicy = p }
+	case "번호재사용":
+		enabled := options["enabled"].BoolValue()
+		summary = fmt.Sprintf("쓰이지 않은 티켓 번호 재사용을 '%s'(으)로 변경했습니다.", onOffLabel(enabled))
+		apply = func(cfg *guildConfig) { cfg.RecycleTicketNumbers = enabled }
+	case "음성기록":
+		enabled := options["enabled"].BoolValue()
+		summary = fmt.Sprintf("음성 상담 입장/퇴장 기록을 '%s'(으)로 변경했습니다.", onOffLabel(enabled))
+		apply = func(cfg *guildConfig) { cfg.RecordVoiceSessions = enabled }
+	case "티켓방식":
+		mode := options["mode"].StringValue()
+		forumID := getConfig().ForumChannelID
+		if forum, ok := options["forum"]; ok {
+			forumID = forum.ChannelValue(nil).ID
+		}
+		if mode == ticketModeForum && forumID == "" {
+			respondError(s, i, errForumChannelUnset, nil)
+			return
+		}
+		summary = "새 티켓을 개별 채널로 생성합니다."
+		if mode == ticketModeForum {
+			summary = fmt.Sprintf("새 티켓을 <#%s> 포럼의 게시글로 생성합니다. 게시글은 포럼을 볼 수 있는 모든 사람에게 보이므로 포럼 채널의 열람 권한을 확인해주세요.\n격리된 티켓과 익명·암호화 창구의 티켓은 계속 개별 채널로 생성됩니다.", forumID)
+		}
+		apply = func(cfg *guildConfig) {
+			cfg.TicketMode = mode
+			cfg.ForumChannelID = forumID
+		}
+	case "현황판":
+		channelID := ""
+		summary = "공개 현황판을 껐습니다. 기존 현황판 메시지는 직접 삭제해주세요."
+		if channel, ok := options["channel"]; ok {
+			channelID = channel.ChannelValue(nil).ID
+			summary = fmt.Sprintf("공개 현황판을 <#%s> 채널에 게시합니다.", channelID)
+		}
+		apply = func(cfg *guildConfig) {
+			cfg.StatusBoardChannelID = channelID
+			cfg.StatusBoardMessageID = ""
+		}
+	case "자동종료":
+		topic := options["topic"].StringValue()
+		policy := inactivityPolicy{WarnAfter: time.Duration(options["warn_hours"].IntValue()) * time.Hour, CloseAfter: time.Duration(options["close_hours"].IntValue()) * time.Hour}
+		summary = fmt.Sprintf("%s 창구의 자동 종료를 껐습니다.", topic)
+		if policy.enabled() {
+			summary = fmt.Sprintf("%s 창구에서 %s 동안 메시지가 없으면 경고하고, 이후 %s 뒤 자동으로 닫습니다.", topic, formatSLADuration(policy.WarnAfter), formatSLADuration(policy.CloseAfter))
+		}
+		apply = func(cfg *guildConfig) {
+			if policy.enabled() {
+				cfg.CategoryInactivity[topic] = policy
+			} else {
+				delete(cfg.CategoryInactivity, topic)
+			}
+		}
+	case "첨부서류":
+		topic := options["topic"].StringValue()
+		r := attachmentRequirement{Prompt: defaultAttachmentPrompt, MinFiles: int(options["count"].IntValue()), MaxSizeMB: defaultAttachmentMaxSizeMB}
+		if opt, ok := options["types"]; ok {
+			r.Types = parseAttachmentTypes(opt.StringValue())
+		}
+		if opt, ok := options["max_mb"]; ok {
+			r.MaxSizeMB = int(opt.IntValue())
+		}
+		if opt, ok := options["prompt"]; ok {
+			r.Prompt = strings.ReplaceAll(opt.StringValue(), `\n`, "\n")
+		}
+		summary = fmt.Sprintf("%s 창구의 서류 첨부 요청을 껐습니다.", topic)
+		if r.MinFiles > 0 {
+			summary = fmt.Sprintf("%s 창구는 접수 후 서류를 받은 다음 담당자를 호출합니다. (%s)", topic, r.describe())
+		}
+		apply = func(cfg *guildConfig) {
+			if r.MinFiles > 0 {
+				cfg.CategoryAttachments[topic] = r
+			} else {
+				delete(cfg.CategoryAttachments, topic)
+			}
+		}
+	case "언어":
+		lang := options["language"].StringValue()
+		summary = fmt.Sprintf("업무 언어를 %s(으)로 변경했습니다.", languageName(lang))
+		apply = func(cfg *guildConfig) { cfg.WorkingLanguage = lang }
+	case "sla":
+		topic := options["topic"].StringValue()
+		var sla categorySLA
+		for name, target := range map[string]*time.Duration{"first_response": &sla.FirstResponse, "resolution": &sla.Resolution} {
+			raw := options[name].StringValue()
+			if raw == "0" {
+				continue
+			}
+			d, err := parseSLADuration(raw)
+			if err != nil || d <= 0 {
+				respondError(s, i, errInvalidSLADuration, nil, raw)
+				return
+			}
+			*target = d
+		}
+		summary = fmt.Sprintf("%s 창구의 SLA를 첫 응답 %s · 해결 %s(으)로 변경했습니다. 새로 접수되는 티켓부터 적용됩니다.", topic, slaLabel(sla.FirstResponse), slaLabel(sla.Resolution))
+		apply = func(cfg *guildConfig) {
+			if sla.FirstResponse > 0 || sla.Resolution > 0 {
+				cfg.CategorySLAs[topic] = sla
+			} else {
+				delete(cfg.CategorySLAs, topic)
+			}
+		}
+	case "에스컬레이션":
+		e := slaEscalation{Interval: defaultEscalationInterval}
+		if role, ok := options["role"]; ok {
+			e.RoleID = role.RoleValue(nil, "").ID
+		}
+		if opt, ok := options["interval"]; ok {
+			raw := opt.StringValue()
+			e.Interval = 0
+			if raw != "0" {
+				d, err := parseSLADuration(raw)
+				if err != nil || d <= 0 {
+					respondError(s, i, errInvalidSLADuration, nil, raw)
+					return
+				}
+				e.Interval = d
+			}
+		}
+		summary = "SLA 초과 알림: " + escalationSummary(e)
+		apply = func(cfg *guildConfig) { cfg.SLAEscalation = e }
+	case "기능":
+		topic := options["topic"].StringValue()
+		feature := options["feature"].StringValue()
+		enabled := options["enabled"].BoolValue()
+		if feature == "encrypted" && enabled {
+			if _, err := sealingKey(); err != nil {
+				respondError(s, i, errSealingKeyUnset, err)
+				return
+			}
+		}
+		summary = fmt.Sprintf("%s 창구의 %s 기능을 '%s'(으)로 변경했습니다.", topic, featureLabel(feature), onOffLabel(enabled))
+		apply = func(cfg *guildConfig) {
+			features, ok := cfg.CategoryFeatures[topic]
+			if !ok {
+				features = defaultCategoryFeatures
+			}
+			switch feature {
+			case "transcripts":
+				features.Transcripts = enabled
+			case "csat":
+				features.CSAT = enabled
+			case "auto_assign":
+				features.AutoAssign = enabled
+			case "anonymous":
+				features.Anonymous = enabled
+			case "encrypted":
+				features.Encrypted = enabled
+			}
+			cfg.CategoryFeatures[topic] = features
+		}
+	}
+	if err := updateConfig(apply); err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	if sub.Name == "현황판" && getConfig().StatusBoardChannelID != "" {
+		go updateStatusBoard(s)
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "설정 변경", Description: summary + "\n변경 사항은 즉시 적용됩니다.", Color: colorGreen}}}})
+}
+
+func settingsEmbed(cfg guildConfig) *discordgo.MessageEmbed {
+	var roles strings.Builder
+	roles.WriteString(fmt.Sprintf("기본: <@&%s>\n", cfg.DefaultSupportRoleID))
+	topics := make([]string, 0, len(cfg.CategorySupportRoles))
+	for topic := range cfg.CategorySupportRoles {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	for _, topic := range topics {
+		roles.WriteString(fmt.Sprintf("%s: <@&%s>\n", topic, cfg.CategorySupportRoles[topic]))
+	}
+	var features strings.Builder
+	for _, option := range ticketOptions() {
+		f := featuresFor(option.Value)
+		features.WriteString(fmt.Sprintf("%s: 대화록 %s · 만족도 %s · 자동배정 %s · 익명 %s · 암호화 %s\n", option.Value, onOffLabel(f.Transcripts), onOffLabel(f.CSAT), onOffLabel(f.AutoAssign), onOffLabel(f.Anonymous), onOffLabel(f.Encrypted)))
+	}
+	return &discordgo.MessageEmbed{
+		Title: "현재 설정",
+		Color: colorBlue,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "기관", Value: institutionLabel(cfg), Inline: true},
+			{Name: "접수번호 형식", Value: fmt.Sprintf("%s-연도-번호", ticketCodePrefix()), Inline: true},
+			{Name: "닫힌 티켓 보관", Value: retentionLabel(cfg.ClosedRetentionDays), Inline: true},
+			{Name: "로그 채널", Value: fmt.Sprintf("<#%s>", cfg.LogChannelID), Inline: true},
+			{Name: "열린 티켓 카테고리", Value: fmt.Sprintf("<#%s>", cfg.OpenCategoryID), Inline: true},
+			{Name: "닫힌 티켓 카테고리", Value: fmt.Sprintf("<#%s>", cfg.ClosedCategoryID), Inline: true},
+			{Name: "지원 역할", Value: roles.String(), Inline: false},
+			{Name: "티켓 방식", Value: ticketModeLabel(cfg), Inline: true},
+			{Name: "업무 언어", Value: languageName(workingLanguage()), Inline: true},
+			{Name: "버튼 기록 채널 표시", Value: onOffLabel(cfg.PostInteractionEvents), Inline: true},
+			{Name: "음성 상담 기록", Value: onOffLabel(cfg.RecordVoiceSessions), Inline: true},
+			{Name: "대화록 스타일", Value: cfg.TranscriptStyle.resolved().label(), Inline: true},
+			{Name: "보고서 서식", Value: cfg.ExportLocale.label(), Inline: true},
+			{Name: "티켓 번호 재사용", Value: onOffLabel(cfg.RecycleTicketNumbers), Inline: true},
+			{Name: "접수 간격", Value: cooldownLabel(cfg.TicketCooldown), Inline: true},
+			{Name: "열린 티켓 제한", Value: openTicketLimitLabel(cfg.MaxOpenTickets), Inline: true},
+			{Name: "접수 폭주 방지", Value: cfg.RaidThrottle.label(), Inline: true},
+			{Name: "접수 상태", Value: lockdownLabel(cfg.Lockdown), Inline: true},
+			{Name: "접수 전 확인", Value: strings.TrimSpace(verificationSummary(cfg.Verification) + "\n" + topicRoleSummary(cfg.CategoryRequiredRoles)), Inline: false},
+			{Name: "스팸 검사", Value: spamPolicySummary(cfg.SpamPolicy), Inline: false},
+			{Name: "공개 현황판", Value: statusBoardLabel(cfg.StatusBoardChannelID), Inline: true},
+			{Name: "담당자 호출", Value: fmt.Sprintf("미배정 %d개 이상 시 %d명 개별 호출\n%s", cfg.PingThreshold, cfg.PingAgentCount, cfg.Reassignment.label()), Inline: false},
+			{Name: "창구별 기능", Value: features.String(), Inline: false},
+			{Name: "무응답 자동 종료", Value: inactivitySummary(cfg), Inline: false},
+			{Name: "첨부 서류", Value: attachmentSummary(cfg), Inline: false},
+			{Name: "SLA", Value: slaSummary(cfg), Inline: false},
+			{Name: "SLA 초과 알림", Value: escalationSummary(cfg.SLAEscalation), Inline: false},
+		},
+	}
+}
+
+func ticketModeLabel(cfg guildConfig) string {
+	if forumModeEnabled(cfg) {
+		return fmt.Sprintf("포럼 게시글 (<#%s>)", cfg.ForumChannelID)
+	}
+	return "개별 채널"
+}
+
+func statusBoardLabel(channelID string) string {
+	if channelID == "" {
+		return "사용 안 함"
+	}
+	return fmt.Sprintf("<#%s>", channelID)
+}
+
+func featureLabel(feature string) string {
+	for _, choice := range settingsFeatureChoices {
+		if choice.Value == feature {
+			return choice.Name
+		}
+	}
+	return feature
+}
+
+func onOffLabel(enabled bool) string {
+	if enabled {
+		return "사용"
+	}
+	return "사용 안 함"
+}
+
+var intakeStyleChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "한 줄", Value: int(discordgo.TextInputShort)},
+	{Name: "여러 줄", Value: int(discordgo.TextInputParagraph)},
+}
+
+func intakeQuestionSettingsGroup() *discordgo.ApplicationCommandOption {
+	minLength := 1.0
+	topicOption := &discordgo.ApplicationCommandOption{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: true, Choices: ticketTopicChoices()}
+	return &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionSubCommandGroup,
+		Name:        "질문",
+		Description: "창구별 접수 양식 질문을 관리합니다.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "보기", Description: "창구의 접수 양식 질문을 확인합니다.", Options: []*discordgo.ApplicationCommandOption{topicOption}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "추가", Description: "접수 양식에 질문을 추가합니다.", Options: []*discordgo.ApplicationCommandOption{
+				topicOption,
+				{Type: discordgo.ApplicationCommandOptionString, Name: "label", Description: "질문 제목", Required: true, MaxLength: 45},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "placeholder", Description: "입력란 안내 문구", Required: false, MaxLength: 100},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "style", Description: "입력란 형태 (기본: 한 줄)", Required: false, Choices: intakeStyleChoices},
+				{Type: discordgo.ApplicationCommandOptionBoolean, Name: "required", Description: "필수 여부 (기본: 필수)", Required: false},
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "max_length", Description: "최대 글자 수", Required: false, MinValue: &minLength, MaxValue: maxIntakeQuestionSize},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "삭제", Description: "접수 양식에서 질문을 삭제합니다.", Options: []*discordgo.ApplicationCommandOption{
+				topicOption,
+				{Type: discordgo.ApplicationCommandOptionInteger, Name: "number", Description: "/설정 질문 보기에 표시된 번호", Required: true},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "초기화", Description: "창구의 접수 양식을 기본 질문으로 되돌립니다.", Options: []*discordgo.ApplicationCommandOption{topicOption}},
+		},
+	}
+}
+
+func handleIntakeQuestionSettings(s *discordgo.Session, i *discordgo.InteractionCreate, sub *discordgo.ApplicationCommandInteractionDataOption) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range sub.Options {
+		options[opt.Name] = opt
+	}
+	topic := options["topic"].StringValue()
+	questions := intakeQuestions(topic)
+	var summary string
+	var apply func(cfg *guildConfig)
+	switch sub.Name {
+	case "보기":
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{intakeQuestionsEmbed(topic, questions)}}})
+		return
+	case "추가":
+		if len(questions) >= maxIntakeQuestions {
+			respondError(s, i, errIntakeQuestionLimit, nil, maxIntakeQuestions)
+			return
+		}
+		q := intakeQuestion{
+			ID:        nextIntakeQuestionID(questions),
+			Label:     options["label"].StringValue(),
+			Style:     discordgo.TextInputShort,
+			Required:  true,
+			MaxLength: maxIntakeQuestionSize,
+		}
+		if opt, ok := options["placeholder"]; ok {
+			q.Placeholder = opt.StringValue()
+		}
+		if opt, ok := options["style"]; ok {
+			q.Style = discordgo.TextInputStyle(opt.IntValue())
+		}
+		if opt, ok := options["required"]; ok {
+			q.Required = opt.BoolValue()
+		}
+		if opt, ok := options["max_length"]; ok {
+			q.MaxLength = int(opt.IntValue())
+		}
+		updated := append(append([]intakeQuestion(nil), questions...), q)
+		summary = fmt.Sprintf("%s 창구 접수 양식에 '%s' 질문을 추가했습니다.", topic, q.Label)
+		apply = func(cfg *guildConfig) { cfg.IntakeQuestions[topic] = updated }
+	case "삭제":
+		n := int(options["number"].IntValue())
+		if n < 1 || n > len(questions) {
+			respondError(s, i, errIntakeQuestionNotFound, nil, n)
+			return
+		}
+		removed := questions[n-1]
+		var updated []intakeQuestion
+		updated = append(updated, questions[:n-1]...)
+		updated = append(updated, questions[n:]...)
+		summary = fmt.Sprintf("%s 창구 접수 양식에서 '%s' 질문을 삭제했습니다.", topic, removed.Label)
+		apply = func(cfg *guildConfig) { cfg.IntakeQuestions[topic] = updated }
+	case "초기화":
+		summary = fmt.Sprintf("%s 창구 접수 양식을 기본 질문으로 되돌렸습니다.", topic)
+		apply = func(cfg *guildConfig) { delete(cfg.IntakeQuestions, topic) }
+	}
+	if err := updateConfig(apply); err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "설정 변경", Description: summary + "\n변경 사항은 다음 접수부터 적용됩니다.", Color: colorGreen}, intakeQuestionsEmbed(topic, intakeQuestions(topic))}}})
+}
+
+func nextIntakeQuestionID(questions []intakeQuestion) string {
+	n := len(questions) + 1
+	for {
+		id := fmt.Sprintf("custom_%d", n)
+		taken := false
+		for _, q := range questions {
+			if q.ID == id {
+				taken = true
+				break
+			}
+		}
+		if !taken {
+			return id
+		}
+		n++
+	}
+}
+
+func intakeQuestionsEmbed(topic string, questions []intakeQuestion) *discordgo.MessageEmbed {
+	var lines []string
+	for n, q := range questions {
+		required := "선택"
+		if q.Required {
+			required = "필수"
+		}
+		lines = append(lines, fmt.Sprintf("**%d.** %s (%s · %s · 최대 %d자)", n+1, q.Label, intakeStyleLabel(q.Style), required, q.MaxLength))
+	}
+	return &discordgo.MessageEmbed{Title: topic + " 접수 양식", Description: strings.Join(lines, "\n"), Color: colorBlue}
+}
+
+func intakeStyleLabel(style discordgo.TextInputStyle) string {
+	if style == discordgo.TextInputParagraph {
+		return "여러 줄"
+	}
+	return "한 줄"
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const specialistFieldName = "추천 담당자"
+
+type specialistMatch struct {
+	AgentID string
+	Skills  []string
+	OnDuty  bool
+}
+
+func skillsCommand() *discordgo.ApplicationCommand {
+	adminPermission := int64(discordgo.PermissionAdministrator)
+	return &discordgo.ApplicationCommand{
+		Name:                     "스킬",
+		Description:              "담당자별 전문 분야를 관리합니다. 자동 배정과 추천 담당자에 사용됩니다.",
+		DefaultMemberPermissions: &adminPermission,
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "목록", Description: "담당자별 스킬을 확인합니다."},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "추가", Description: "담당자에게 스킬을 추가합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "담당자", Required: true},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "skill", Description: "스킬 (예: 파산, 세무, 영어)", Required: true},
+			}},
+			{Type: discordgo.ApplicationCommandOptionSubCommand, Name: "삭제", Description: "담당자의 스킬을 삭제합니다.", Options: []*discordgo.ApplicationCommandOption{
+				{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "담당자", Required: true},
+				{Type: discordgo.ApplicationCommandOptionString, Name: "skill", Description: "삭제할 스킬", Required: true},
+			}},
+		},
+	}
+}
+
+func handleSkills(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	sub := i.ApplicationCommandData().Options[0]
+	if sub.Name == "목록" {
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "담당자 스킬", Description: agentSkillList(getConfig().AgentSkills), Color: colorBlue}}}})
+		return
+	}
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range sub.Options {
+		options[opt.Name] = opt
+	}
+	userID := options["user"].UserValue(nil).ID
+	skill := strings.TrimSpace(options["skill"].StringValue())
+	current := getConfig().AgentSkills[userID]
+	var skills []string
+	var summary string
+	switch sub.Name {
+	case "추가":
+		skills = current
+		if !containsSkill(skills, skill) {
+			skills = append(skills, skill)
+		}
+		summary = fmt.Sprintf("<@%s> 님에게 '%s' 스킬을 추가했습니다.", userID, skill)
+	case "삭제":
+		if !containsSkill(current, skill) {
+			respondError(s, i, errSkillNotFound, nil, userID, skill)
+			return
+		}
+		for _, v := range current {
+			if !strings.EqualFold(v, skill) {
+				skills = append(skills, v)
+			}
+		}
+		summary = fmt.Sprintf("<@%s> 님의 '%s' 스킬을 삭제했습니다.", userID, skill)
+	}
+	err := updateConfig(func(cfg *guildConfig) {
+		if len(skills) == 0 {
+			delete(cfg.AgentSkills, userID)
+		} else {
+			cfg.AgentSkills[userID] = skills
+		}
+	})
+	if err != nil {
+		respondError(s, i, errConfigSaveFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "스킬 변경", Description: summary, Color: colorGreen}}}})
+}
+
+func agentSkillList(agentSkills map[string][]string) string {
+	if len(agentSkills) == 0 {
+		return "등록된 스킬이 없습니다. /스킬 추가 명령어로 담당자의 전문 분야를 등록할 수 있습니다."
+	}
+	agents := make([]string, 0, len(agentSkills))
+	for id := range agentSkills {
+		agents = append(agents, id)
+	}
+	sort.Strings(agents)
+	var lines []string
+	for _, id := range agents {
+		lines = append(lines, fmt.Sprintf("<@%s>: %s", id, strings.Join(agentSkills[id], ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func containsSkill(skills []string, skill string) bool {
+	for _, v := range skills {
+		if strings.EqualFold(v, skill) {
+			return true
+		}
+	}
+	return false
+}
+
+func ticketSkillText(t *ticket) string {
+	parts := append([]string{t.Category}, t.Tags...)
+	for _, a := range t.Intake {
+		parts = append(parts, a.Value)
+	}
+	return strings.ToLower(strings.Join(parts, "\n"))
+}
+
+func matchingSkills(t *ticket, skills []string) []string {
+	text := ticketSkillText(t)
+	var matched []string
+	for _, skill := range skills {
+		if skill != "" && strings.Contains(text, strings.ToLower(skill)) {
+			matched = append(matched, skill)
+		}
+	}
+	return matched
+}
+
+func findSpecialists(s *discordgo.Session, t *ticket, supportRoleID string) []specialistMatch {
+	if len(getConfig().AgentSkills) == 0 {
+		return nil
+	}
+	onDuty := make(map[string]bool)
+	for _, id := range onDutyAgents(s, supportRoleID) {
+		onDuty[id] = true
+	}
+	var matches []specialistMatch
+	for id, skills := range getConfig().AgentSkills {
+		if id == t.OwnerID {
+			continue
+		}
+		if matched := matchingSkills(t, skills); len(matched) > 0 {
+			matches = append(matches, specialistMatch{AgentID: id, Skills: matched, OnDuty: onDuty[id]})
+		}
+	}
+	loads, _, err := activeClaimLoads(t.Category)
+	if err != nil {
+		log.Printf("Could not load claim data for specialist routing: %v", err)
+	}
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].OnDuty != matches[b].OnDuty {
+			return matches[a].OnDuty
+		}
+		if len(matches[a].Skills) != len(matches[b].Skills) {
+			return len(matches[a].Skills) > len(matches[b].Skills)
+		}
+		if loads[matches[a].AgentID] != loads[matches[b].AgentID] {
+			return loads[matches[a].AgentID] < loads[matches[b].AgentID]
+		}
+		return matches[a].AgentID < matches[b].AgentID
+	})
+	return matches
+}
+
+func autoAssignAgent(s *discordgo.Session, t *ticket, supportRoleID string, specialists []specialistMatch) string {
+	if len(specialists) > 0 && specialists[0].OnDuty {
+		return specialists[0].AgentID
+	}
+	var agents []string
+	for _, id := range onDutyAgents(s, supportRoleID) {
+		if id != t.OwnerID {
+			agents = append(agents, id)
+		}
+	}
+	if len(agents) == 0 {
+		return ""
+	}
+	loads, _, err := activeClaimLoads(t.Category)
+	if err != nil {
+		log.Printf("Could not load claim data for auto-assignment: %v", err)
+	}
+	sort.SliceStable(agents, func(a, b int) bool { return loads[agents[a]] < loads[agents[b]] })
+	return agents[0]
+}
+
+func specialistField(specialists []specialistMatch) *discordgo.MessageEmbedField {
+	if len(specialists) == 0 {
+		return nil
+	}
+	top := specialists[0]
+	value := fmt.Sprintf("<@%s> (%s)", top.AgentID, strings.Join(top.Skills, ", "))
+	if !top.OnDuty {
+		value += " · 현재 자리 비움"
+	}
+	return &discordgo.MessageEmbedField{Name: specialistFieldName, Value: value, Inline: false}
+}
+
+func refreshSpecialistRecommendation(s *discordgo.Session, t *ticket) {
+	if t.AssigneeID != "" {
+		return
+	}
+	msg, err := findTicketMessage(s, t.ChannelID)
+	if err != nil || msg == nil {
+		return
+	}
+	supportRoleID, ok := getConfig().CategorySupportRoles[t.Category]
+	if !ok {
+		supportRoleID = getConfig().DefaultSupportRoleID
+	}
+	embed := msg.Embeds[0]
+	var fields []*discordgo.MessageEmbedField
+	for _, field := range embed.Fields {
+		if field.Name != specialistFieldName {
+			fields = append(fields, field)
+		}
+	}
+	if field := specialistField(findSpecialists(s, t, supportRoleID)); field != nil {
+		fields = append(fields, field)
+	}
+	embed.Fields = fields
+	if _, err := s.ChannelMessageEditEmbed(msg.ChannelID, msg.ID, embed); err != nil {
+		log.Printf("Could not update specialist recommendation for '%s': %v", t.Name(), err)
+	}
+}
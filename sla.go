@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func parseSLADuration(v string) (time.Duration, error) {
+	v = strings.TrimSpace(strings.ToLower(v))
+	if strings.HasSuffix(v, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(v, "d"))
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(v)
+}
+
+func formatSLADuration(d time.Duration) string {
+	if d >= 24*time.Hour && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d일", int(d.Hours()/24))
+	}
+	if d >= time.Hour {
+		if d%time.Hour == 0 {
+			return fmt.Sprintf("%d시간", int(d.Hours()))
+		}
+		return fmt.Sprintf("%d시간 %d분", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%d분", int(d.Minutes()))
+}
+
+const slaFieldName = "SLA"
+
+type categorySLA struct {
+	FirstResponse time.Duration `bson:"first_response"`
+	Resolution    time.Duration `bson:"resolution"`
+}
+
+func categorySLAFor(category string) categorySLA {
+	return getConfig().CategorySLAs[category]
+}
+
+func ticketFirstResponseSLA(t *ticket) time.Duration {
+	return categorySLAFor(t.Category).FirstResponse
+}
+
+func ticketResolutionSLA(t *ticket) time.Duration {
+	if t.SLAOverride > 0 {
+		return t.SLAOverride
+	}
+	return categorySLAFor(t.Category).Resolution
+}
+
+func (t *ticket) startSLATimers() {
+	if d := ticketFirstResponseSLA(t); d > 0 {
+		t.FirstResponseDue = t.CreatedAt.Add(d)
+	}
+	if d := ticketResolutionSLA(t); d > 0 {
+		t.ResolutionDue = t.CreatedAt.Add(d)
+	}
+}
+
+func slaSummary(cfg guildConfig) string {
+	var lines []string
+	for _, option := range ticketOptions() {
+		sla := cfg.CategorySLAs[option.Value]
+		lines = append(lines, fmt.Sprintf("%s: 첫 응답 %s · 해결 %s", option.Value, slaLabel(sla.FirstResponse), slaLabel(sla.Resolution)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func slaLabel(d time.Duration) string {
+	if d <= 0 {
+		return "없음"
+	}
+	return formatSLADuration(d)
+}
+
+func slaField(t *ticket) *discordgo.MessageEmbedField {
+	var lines []string
+	now := time.Now()
+	switch {
+	case t.FirstResponseDue.IsZero():
+	case !t.FirstResponseAt.IsZero():
+		lines = append(lines, fmt.Sprintf("첫 응답: 완료 (%s)", formatWait(t.FirstResponseAt.Sub(t.CreatedAt))))
+	case now.After(t.FirstResponseDue):
+		lines = append(lines, fmt.Sprintf("첫 응답: ⚠️ 기한 초과 (<t:%d:R>)", t.FirstResponseDue.Unix()))
+	default:
+		lines = append(lines, fmt.Sprintf("첫 응답: <t:%d:R> 까지", t.FirstResponseDue.Unix()))
+	}
+	if !t.ResolutionDue.IsZero() {
+		if now.After(t.ResolutionDue) {
+			lines = append(lines, fmt.Sprintf("해결: ⚠️ 기한 초과 (<t:%d:R>)", t.ResolutionDue.Unix()))
+		} else {
+			lines = append(lines, fmt.Sprintf("해결: <t:%d:R> 까지", t.ResolutionDue.Unix()))
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return &discordgo.MessageEmbedField{Name: slaFieldName, Value: strings.Join(lines, "\n"), Inline: false}
+}
+
+func refreshSLAField(s *discordgo.Session, t *ticket) {
+	msg, err := findTicketMessage(s, t.ChannelID)
+	if err != nil || msg == nil {
+		return
+	}
+	embed := msg.Embeds[0]
+	var fields []*discordgo.MessageEmbedField
+	for _, field := range embed.Fields {
+		if field.Name != slaFieldName {
+			fields = append(fields, field)
+		}
+	}
+	if field := slaField(t); field != nil {
+		fields = append(fields, field)
+	}
+	embed.Fields = fields
+	if _, err := s.ChannelMessageEditEmbed(msg.ChannelID, msg.ID, embed); err != nil {
+		log.Printf("Could not update SLA field for '%s': %v", t.Name(), err)
+	}
+}
+
+func ticketSLABreached(t *ticket, now time.Time) bool {
+	if t.ResolutionDue.IsZero() || t.Status != ticketStatusOpen {
+		return false
+	}
+	return now.After(t.ResolutionDue)
+}
+
+func handleSLAOverride(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	raw := i.ApplicationCommandData().Options[0].StringValue()
+	var override time.Duration
+	if raw != "해제" && raw != "0" {
+		d, err := parseSLADuration(raw)
+		if err != nil || d <= 0 {
+			respondError(s, i, errInvalidSLADuration, nil, raw)
+			return
+		}
+		override = d
+	}
+	event := ticketEvent{At: time.Now(), UserID: i.Member.User.ID, UserName: memberDisplayName(i.Member), Kind: ticketEventSLA}
+	var update bson.M
+	var description string
+	if override > 0 {
+		event.Action = formatSLADuration(override)
+		update = bson.M{"$set": bson.M{"sla_override": override, "resolution_due": t.CreatedAt.Add(override)}, "$push": bson.M{"events": event}}
+		description = fmt.Sprintf("이 티켓의 처리 기한을 **%s**(으)로 지정했습니다.\n기한: <t:%d:F>", event.Action, t.CreatedAt.Add(override).Unix())
+	} else {
+		event.Action = "해제"
+		description = "이 티켓의 개별 처리 기한을 해제했습니다. 창구 기본 기한이 적용됩니다."
+		update = bson.M{"$unset": bson.M{"sla_override": "", "resolution_due": ""}, "$push": bson.M{"events": event}}
+		if d := categorySLAFor(t.Category).Resolution; d > 0 {
+			update = bson.M{"$set": bson.M{"resolution_due": t.CreatedAt.Add(d)}, "$unset": bson.M{"sla_override": ""}, "$push": bson.M{"events": event}}
+		}
+	}
+	if err := updateTicket(t.ChannelID, update); err != nil {
+		respondError(s, i, errTicketSaveFailed, err)
+		return
+	}
+	t.SLAOverride = override
+	t.ResolutionDue = time.Time{}
+	if d := ticketResolutionSLA(t); d > 0 {
+		t.ResolutionDue = t.CreatedAt.Add(d)
+	}
+	color := colorBlue
+	if ticketSLABreached(t, time.Now()) {
+		color = colorRed
+		description += "\n⚠️ 이미 기한이 지났습니다."
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "처리 기한 변경", Description: description, Color: color}}}})
+	refreshSLAField(s, t)
+}
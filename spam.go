@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	actionReleaseQuarantine = "release_quarantine"
+	defaultSpamThreshold    = 3
+	spamDuplicateWindow     = 24 * time.Hour
+	spamDuplicateTickets    = 2
+	spamLinkLimit           = 3
+)
+
+var spamLinkPattern = regexp.MustCompile(`(?i)(https?://|discord\.gg/|www\.)\S+`)
+
+type spamPolicy struct {
+	Enabled          bool   `bson:"enabled"`
+	Threshold        int    `bson:"threshold,omitempty"`
+	ReviewCategoryID string `bson:"review_category_id,omitempty"`
+	ModeratorRoleID  string `bson:"moderator_role_id,omitempty"`
+}
+
+type spamAssessment struct {
+	Score   int
+	Signals []string
+}
+
+func (p spamPolicy) threshold() int {
+	if p.Threshold <= 0 {
+		return defaultSpamThreshold
+	}
+	return p.Threshold
+}
+
+func (a *spamAssessment) add(points int, signal string) {
+	a.Score += points
+	a.Signals = append(a.Signals, fmt.Sprintf("%s (+%d)", signal, points))
+}
+
+func assessIntake(userID string, answers []intakeAnswer) spamAssessment {
+	var a spamAssessment
+	if created, err := discordgo.SnowflakeTimestamp(userID); err == nil {
+		switch age := time.Since(created); {
+		case age < 24*time.Hour:
+			a.add(2, "생성 1일 미만 계정")
+		case age < 7*24*time.Hour:
+			a.add(1, "생성 7일 미만 계정")
+		}
+	}
+	if content := intakeValue(answers, "content"); content != "" {
+		count, err := app().Tickets.CountDocuments(context.TODO(), bson.M{"content": content, "created_at": bson.M{"$gte": time.Now().Add(-spamDuplicateWindow)}})
+		if err != nil {
+			log.Printf("Could not check duplicate intake text: %v", err)
+		} else if count >= spamDuplicateTickets {
+			a.add(2, fmt.Sprintf("최근 24시간 동안 같은 내용의 티켓 %d개", count))
+		} else if count > 0 {
+			a.add(1, "최근 24시간 안에 같은 내용의 티켓 있음")
+		}
+	}
+	if links := len(spamLinkPattern.FindAllString(intakeText(answers), -1)); links >= spamLinkLimit {
+		a.add(2, fmt.Sprintf("링크 %d개", links))
+	} else if links > 0 {
+		a.add(1, fmt.Sprintf("링크 %d개", links))
+	}
+	return a
+}
+
+func shouldQuarantine(i *discordgo.InteractionCreate, answers []intakeAnswer) (spamAssessment, bool) {
+	policy := getConfig().SpamPolicy
+	if !policy.Enabled || policy.ModeratorRoleID == "" || hasSupportRole(i.Member) {
+		return spamAssessment{}, false
+	}
+	a := assessIntake(i.Member.User.ID, answers)
+	return a, a.Score >= policy.threshold()
+}
+
+func quarantineField(a spamAssessment) *discordgo.MessageEmbedField {
+	return &discordgo.MessageEmbedField{Name: fmt.Sprintf("⚠️ 스팸 의심 (점수 %d)", a.Score), Value: strings.Join(a.Signals, "\n"), Inline: false}
+}
+
+func spamPolicySummary(p spamPolicy) string {
+	if !p.Enabled {
+		return "사용 안 함"
+	}
+	review := "열린 티켓 카테고리"
+	if p.ReviewCategoryID != "" {
+		review = fmt.Sprintf("<#%s>", p.ReviewCategoryID)
+	}
+	return fmt.Sprintf("점수 %d 이상 격리 · 검토 위치 %s · 검토 역할 <@&%s>", p.threshold(), review, p.ModeratorRoleID)
+}
+
+func handleReleaseQuarantine(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	cfg := getConfig()
+	if !isAdministrator(i) && !memberHasRole(i.Member, cfg.SpamPolicy.ModeratorRoleID) {
+		respondError(s, i, errNotSpamModerator, nil)
+		return
+	}
+	if !t.Quarantined {
+		respondError(s, i, errNotQuarantined, nil)
+		return
+	}
+	supportRoleID, ok := cfg.CategorySupportRoles[t.Category]
+	if !ok {
+		supportRoleID = cfg.DefaultSupportRoleID
+	}
+	if !t.Forum {
+		if err := retryDiscord("permission set", func() error {
+			return s.ChannelPermissionSet(t.ChannelID, supportRoleID, discordgo.PermissionOverwriteTypeRole, discordgo.PermissionViewChannel|discordgo.PermissionSendMessages, 0)
+		}); err != nil {
+			respondError(s, i, errAddRoleFailed, err)
+			return
+		}
+		if _, err := withRetry("channel edit", func() (*discordgo.Channel, error) {
+			return s.ChannelEdit(t.ChannelID, &discordgo.ChannelEdit{ParentID: cfg.OpenCategoryID})
+		}); err != nil {
+			log.Printf("Could not move released ticket '%s' to the open category: %v", t.Name(), err)
+		}
+	}
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"quarantined": false}}); err != nil {
+		log.Printf("Could not record quarantine release for '%s': %v", t.Name(), err)
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{Embeds: i.Message.Embeds, Components: []discordgo.MessageComponent{}}})
+	s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+		Content: supportPingContent(s, t.Category, supportRoleID),
+		Embeds:  []*discordgo.MessageEmbed{{Title: "검토 완료", Description: fmt.Sprintf("<@%s> 님이 스팸이 아닌 것으로 확인해 일반 접수로 전환했습니다.", i.Member.User.ID), Color: colorGreen}},
+	})
+	log.Printf("Ticket '%s' released from quarantine by %s.", t.Name(), i.Member.User.ID)
+}
+
+func postQuarantineReview(s *discordgo.Session, t *ticket, a spamAssessment) {
+	incCounter("potatobot_tickets_quarantined_total", metricLabel("category", t.Category))
+	log.Printf("Ticket '%s' quarantined for spam review with score %d.", t.Name(), a.Score)
+	_, err := withRetry("message send", func() (*discordgo.Message, error) {
+		return s.ChannelMessageSendComplex(t.ChannelID, &discordgo.MessageSend{
+			Content: fmt.Sprintf("<@&%s>", getConfig().SpamPolicy.ModeratorRoleID),
+			Embeds: []*discordgo.MessageEmbed{{
+				Title:       "스팸 검토 필요",
+				Description: "자동 점검에서 스팸으로 의심되어 지원팀 대신 검토 역할에게 먼저 배정되었습니다. 정상 민원이면 아래 버튼으로 일반 접수로 전환하고, 스팸이면 티켓을 닫아주세요.",
+				Color:       colorYellow,
+				Fields:      []*discordgo.MessageEmbedField{quarantineField(a)},
+			}},
+			Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+				discordgo.Button{Label: "검토 통과", Style: discordgo.SuccessButton, CustomID: ticketComponentID(actionReleaseQuarantine, t)},
+			}}},
+		})
+	})
+	if err != nil {
+		log.Printf("Could not post spam review prompt for '%s': %v", t.Name(), err)
+	}
+}
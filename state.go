@@ -0,0 +1,62 @@
+package main
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const defaultGuildID = "1274752368063414292" // 길드 ID 적용
+
+var kstLocation = mustLoadLocation("Asia/Seoul")
+
+type bot struct {
+	GuildID            string
+	Session            *discordgo.Session
+	Store              store
+	Counters           collection
+	Reservations       collection
+	Cooldowns          collection
+	Blocks             collection
+	Tickets            collection
+	Links              collection
+	Configs            collection
+	Rules              collection
+	Jobs               collection
+	StaffActivity      collection
+	Transcripts        collection
+	TranscriptArchive  collection
+	TranscriptMessages collection
+	Messages           collection
+}
+
+var (
+	botMu      sync.Mutex
+	currentBot atomic.Pointer[bot]
+)
+
+func app() *bot {
+	if b := currentBot.Load(); b != nil {
+		return b
+	}
+	return &bot{GuildID: defaultGuildID}
+}
+
+func updateBot(change func(b *bot)) {
+	botMu.Lock()
+	defer botMu.Unlock()
+	next := *app()
+	change(&next)
+	currentBot.Store(&next)
+}
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		log.Fatalf("Could not load %s location: %v", name, err)
+	}
+	return loc
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestUpdateBotConcurrent(t *testing.T) {
+	currentBot.Store(nil)
+	t.Cleanup(func() { currentBot.Store(nil) })
+	if got := app().GuildID; got != defaultGuildID {
+		t.Fatalf("app().GuildID = %q before any update, want %q", got, defaultGuildID)
+	}
+	updateBot(func(b *bot) { b.GuildID = "" })
+
+	const writers = 50
+	var wg sync.WaitGroup
+	for n := 0; n < writers; n++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			updateBot(func(b *bot) { b.GuildID += "x" })
+		}()
+		go func() {
+			defer wg.Done()
+			if id := app().GuildID; strings.Trim(id, "x") != "" {
+				t.Errorf("app().GuildID = %q, want only x", id)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := len(app().GuildID); got != writers {
+		t.Fatalf("len(app().GuildID) = %d after %d updates, want %d", got, writers, writers)
+	}
+}
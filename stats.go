@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func statsCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "통계",
+		Description: "기간별 티켓 접수량, 처리 현황, 응답 및 해결 시간을 확인합니다.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionInteger, Name: "period", Description: "조회 기간 (기본: 최근 30일)", Required: false, Choices: reportPeriodChoices},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "from", Description: "직접 지정할 시작일 (예: 2026-01-01)", Required: false},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "to", Description: "직접 지정할 종료일, 당일 포함 (기본: 오늘)", Required: false},
+		},
+	}
+}
+
+func statsWindow(options map[string]*discordgo.ApplicationCommandInteractionDataOption) (time.Time, time.Time, string, error) {
+	from, hasFrom := options["from"]
+	if !hasFrom {
+		days, since := reportPeriod(options)
+		return since, time.Now(), fmt.Sprintf("최근 %d일", days), nil
+	}
+	start, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(from.StringValue()), kstLocation)
+	if err != nil {
+		return time.Time{}, time.Time{}, from.StringValue(), err
+	}
+	end := startOfDayKST(time.Now())
+	if to, ok := options["to"]; ok {
+		if end, err = time.ParseInLocation("2006-01-02", strings.TrimSpace(to.StringValue()), kstLocation); err != nil {
+			return time.Time{}, time.Time{}, to.StringValue(), err
+		}
+	}
+	end = end.AddDate(0, 0, 1)
+	return start, end, fmt.Sprintf("%s ~ %s", start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02")), nil
+}
+
+func averageOrDash(samples []time.Duration) string {
+	if len(samples) == 0 {
+		return "-"
+	}
+	return formatWait(averageDuration(samples))
+}
+
+func selfResolvedSummary(selfResolved, closed int) string {
+	if closed == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%d건 (종료의 %.0f%%)", selfResolved, float64(selfResolved)*100/float64(closed))
+}
+
+func handleStats(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	options := make(map[string]*discordgo.ApplicationCommandInteractionDataOption)
+	for _, opt := range i.ApplicationCommandData().Options {
+		options[opt.Name] = opt
+	}
+	start, end, label, err := statsWindow(options)
+	if err != nil || !end.After(start) {
+		respondError(s, i, errInvalidStatsRange, nil, label)
+		return
+	}
+	cursor, err := app().Tickets.Find(context.TODO(), bson.M{"created_at": bson.M{"$gte": start, "$lt": end}, "status": bson.M{"$ne": ticketStatusDeleted}})
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	open, closed, selfResolved := 0, 0, 0
+	var firstResponses, resolutions []time.Duration
+	byCategory := make(map[string]int)
+	for _, t := range tickets {
+		byCategory[t.Category]++
+		if t.Status == ticketStatusOpen {
+			open++
+		} else {
+			closed++
+			if t.SelfResolved {
+				selfResolved++
+			}
+		}
+		if !t.FirstResponseAt.IsZero() {
+			firstResponses = append(firstResponses, t.FirstResponseAt.Sub(t.CreatedAt))
+		}
+		if t.ResolutionTime > 0 {
+			resolutions = append(resolutions, t.ResolutionTime)
+		} else if !t.ClosedAt.IsZero() {
+			resolutions = append(resolutions, t.ClosedAt.Sub(t.CreatedAt))
+		}
+	}
+	embed := &discordgo.MessageEmbed{Title: fmt.Sprintf("티켓 통계 (%s)", label), Color: colorBlue, Timestamp: time.Now().In(kstLocation).Format(time.RFC3339)}
+	embeds := []*discordgo.MessageEmbed{embed}
+	if aging, err := openTicketAgingEmbed(time.Now()); err != nil {
+		log.Printf("Could not compute open ticket aging: %v", err)
+	} else {
+		embeds = append(embeds, aging)
+	}
+	if len(tickets) == 0 {
+		embed.Description = "해당 기간에 접수된 티켓이 없습니다."
+		s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: embeds}})
+		return
+	}
+	categories := make([]string, 0, len(byCategory))
+	for category := range byCategory {
+		categories = append(categories, category)
+	}
+	sort.Slice(categories, func(a, b int) bool { return byCategory[categories[a]] > byCategory[categories[b]] })
+	var categoryLines []string
+	for n, category := range categories {
+		categoryLines = append(categoryLines, fmt.Sprintf("%d. **%s** %d건 (%.0f%%)", n+1, category, byCategory[category], float64(byCategory[category])*100/float64(len(tickets))))
+	}
+	embed.Fields = []*discordgo.MessageEmbedField{
+		{Name: "접수", Value: fmt.Sprintf("%d건", len(tickets)), Inline: true},
+		{Name: "진행 중", Value: fmt.Sprintf("%d건", open), Inline: true},
+		{Name: "종료", Value: fmt.Sprintf("%d건", closed), Inline: true},
+		{Name: "평균 첫 응답", Value: fmt.Sprintf("%s (%d건 기준)", averageOrDash(firstResponses), len(firstResponses)), Inline: true},
+		{Name: "평균 해결 시간", Value: fmt.Sprintf("%s (%d건 기준)", averageOrDash(resolutions), len(resolutions)), Inline: true},
+		{Name: "민원인 직접 해결", Value: selfResolvedSummary(selfResolved, closed), Inline: true},
+		{Name: "창구별 접수", Value: strings.Join(categoryLines, "\n"), Inline: false},
+		{Name: "긴급도 보정", Value: urgencyCalibration(tickets), Inline: false},
+		{Name: "담당자별 처리 시간", Value: handlingTimeSummary(tickets), Inline: false},
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: embeds}})
+}
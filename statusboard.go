@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const statusBoardInterval = 5 * time.Minute
+
+type categoryStatus struct {
+	Received   int
+	InProgress int
+	Completed  int
+	Queue      int
+	waitTotal  time.Duration
+	waitCount  int
+}
+
+func refreshStatusBoard(s *discordgo.Session) error {
+	if getConfig().StatusBoardChannelID == "" {
+		return nil
+	}
+	return updateStatusBoard(s)
+}
+
+func startOfDayKST(now time.Time) time.Time {
+	now = now.In(kstLocation)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, kstLocation)
+}
+
+func collectCategoryStatus() (map[string]*categoryStatus, error) {
+	since := startOfDayKST(time.Now())
+	filter := bson.M{"$or": []bson.M{
+		{"status": ticketStatusOpen},
+		{"created_at": bson.M{"$gte": since}},
+		{"closed_at": bson.M{"$gte": since}},
+	}}
+	cursor, err := app().Tickets.Find(context.TODO(), filter)
+	if err != nil {
+		return nil, err
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		return nil, err
+	}
+	stats := make(map[string]*categoryStatus)
+	for _, option := range ticketOptions() {
+		stats[option.Value] = &categoryStatus{}
+	}
+	for _, t := range tickets {
+		st, ok := stats[t.Category]
+		if !ok {
+			continue
+		}
+		if !t.CreatedAt.Before(since) {
+			st.Received++
+		}
+		if t.Status == ticketStatusOpen {
+			if t.AssigneeID == "" {
+				st.Queue++
+			} else {
+				st.InProgress++
+			}
+		}
+		if !t.ClosedAt.IsZero() && !t.ClosedAt.Before(since) && t.Status != ticketStatusOpen {
+			st.Completed++
+		}
+		if !t.ClaimedAt.IsZero() && !t.ClaimedAt.Before(since) {
+			st.waitTotal += t.ClaimedAt.Sub(t.CreatedAt)
+			st.waitCount++
+		}
+	}
+	return stats, nil
+}
+
+func (st *categoryStatus) averageWait() string {
+	if st.waitCount == 0 {
+		return "-"
+	}
+	return formatWait(st.waitTotal / time.Duration(st.waitCount))
+}
+
+func formatWait(d time.Duration) string {
+	if d < time.Minute {
+		return "1분 미만"
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%d분", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%d시간 %d분", int(d.Hours()), int(d.Minutes())%60)
+}
+
+func statusBoardEmbed(stats map[string]*categoryStatus) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:       "민원 처리 현황",
+		Description: "오늘(KST) 민원창구 처리 현황입니다. 개별 민원 내용은 공개되지 않습니다.",
+		Color:       colorBlue,
+		Footer:      &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("%d분마다 갱신됩니다", int(statusBoardInterval.Minutes()))},
+		Timestamp:   time.Now().In(kstLocation).Format(time.RFC3339),
+	}
+	for _, option := range ticketOptions() {
+		st := stats[option.Value]
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:   option.Label,
+			Value:  fmt.Sprintf("접수 %d · 처리중 %d · 완료 %d\n대기열 %d건 · 평균 대기 %s", st.Received, st.InProgress, st.Completed, st.Queue, st.averageWait()),
+			Inline: false,
+		})
+	}
+	return embed
+}
+
+func updateStatusBoard(s *discordgo.Session) error {
+	cfg := getConfig()
+	stats, err := collectCategoryStatus()
+	if err != nil {
+		return err
+	}
+	embed := statusBoardEmbed(stats)
+	if cfg.StatusBoardMessageID != "" {
+		_, err := s.ChannelMessageEditEmbed(cfg.StatusBoardChannelID, cfg.StatusBoardMessageID, embed)
+		if err == nil {
+			return nil
+		}
+		log.Printf("Could not edit status board message %s, sending a new one: %v", cfg.StatusBoardMessageID, err)
+	}
+	msg, err := s.ChannelMessageSendEmbed(cfg.StatusBoardChannelID, embed)
+	if err != nil {
+		return err
+	}
+	return updateConfig(func(cfg *guildConfig) { cfg.StatusBoardMessageID = msg.ID })
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	storageMongo = "mongo"
+	storageBolt  = "bolt"
+
+	defaultBoltPath      = "potatobot.db"
+	defaultCounterBucket = "counters"
+	defaultTranscriptDir = "transcripts"
+)
+
+type collection interface {
+	Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (*mongo.Cursor, error)
+	FindOne(ctx context.Context, filter interface{}, opts ...*options.FindOneOptions) *mongo.SingleResult
+	FindOneAndUpdate(ctx context.Context, filter interface{}, update interface{}, opts ...*options.FindOneAndUpdateOptions) *mongo.SingleResult
+	InsertOne(ctx context.Context, document interface{}, opts ...*options.InsertOneOptions) (*mongo.InsertOneResult, error)
+	InsertMany(ctx context.Context, documents []interface{}, opts ...*options.InsertManyOptions) (*mongo.InsertManyResult, error)
+	UpdateOne(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	UpdateMany(ctx context.Context, filter interface{}, update interface{}, opts ...*options.UpdateOptions) (*mongo.UpdateResult, error)
+	ReplaceOne(ctx context.Context, filter interface{}, replacement interface{}, opts ...*options.ReplaceOptions) (*mongo.UpdateResult, error)
+	DeleteOne(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	DeleteMany(ctx context.Context, filter interface{}, opts ...*options.DeleteOptions) (*mongo.DeleteResult, error)
+	CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error)
+	Distinct(ctx context.Context, fieldName string, filter interface{}, opts ...*options.DistinctOptions) ([]interface{}, error)
+}
+
+type store interface {
+	Name() string
+	Collection(name string) collection
+	Ping(ctx context.Context) error
+	Disconnect(ctx context.Context) error
+}
+
+type mongoStore struct {
+	client   *mongo.Client
+	database *mongo.Database
+}
+
+func (m mongoStore) Name() string { return "MongoDB" }
+
+func (m mongoStore) Collection(name string) collection { return m.database.Collection(name) }
+
+func (m mongoStore) Ping(ctx context.Context) error { return m.client.Ping(ctx, nil) }
+
+func (m mongoStore) Disconnect(ctx context.Context) error { return m.client.Disconnect(ctx) }
+
+func storageBackend() string {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv("STORAGE_BACKEND")))
+	if backend == "" {
+		return storageMongo
+	}
+	return backend
+}
+
+func connectStore(ctx context.Context) error {
+	switch backend := storageBackend(); backend {
+	case storageMongo:
+		return connectMongo(ctx)
+	case storageBolt:
+		return connectBolt()
+	default:
+		return fmt.Errorf("unknown STORAGE_BACKEND '%s' (expected %s or %s)", backend, storageMongo, storageBolt)
+	}
+}
+
+func connectBolt() error {
+	path := os.Getenv("BOLT_PATH")
+	if path == "" {
+		path = defaultBoltPath
+	}
+	st, err := openBoltStore(path)
+	if err != nil {
+		return fmt.Errorf("failed to open embedded database '%s': %w", path, err)
+	}
+	log.Printf("Using the embedded database at %s.", path)
+	counters := os.Getenv("MONGO_COLLECTION")
+	if counters == "" {
+		counters = defaultCounterBucket
+	}
+	updateBot(func(b *bot) { attachStore(b, st, counters) })
+	return nil
+}
+
+func attachStore(b *bot, st store, counters string) {
+	b.Store = st
+	b.Counters = st.Collection(counters)
+	b.Reservations = st.Collection("ticket_number_reservations")
+	b.Cooldowns = st.Collection("ticket_cooldowns")
+	b.Blocks = st.Collection("ticket_blacklist")
+	b.Tickets = st.Collection("tickets")
+	b.Links = st.Collection("ticket_links")
+	b.Configs = st.Collection("guild_config")
+	b.Rules = st.Collection("ticket_rules")
+	b.Jobs = st.Collection("scheduled_jobs")
+	b.StaffActivity = st.Collection("staff_activity")
+	b.Messages = st.Collection("messages")
+	connectTranscriptStore(b)
+}
+
+func disconnectStore(ctx context.Context) {
+	if st := app().Store; st != nil {
+		if err := st.Disconnect(ctx); err != nil {
+			log.Printf("Could not close %s: %v", st.Name(), err)
+		}
+	}
+}
+
+func createIndexes(ctx context.Context, c collection, models ...mongo.IndexModel) error {
+	mc, ok := c.(*mongo.Collection)
+	if !ok {
+		return nil
+	}
+	_, err := mc.Indexes().CreateMany(ctx, models)
+	return err
+}
+
+func transcriptDir() string {
+	if dir := os.Getenv("TRANSCRIPT_DIR"); dir != "" {
+		return dir
+	}
+	if storageBackend() == storageBolt {
+		return defaultTranscriptDir
+	}
+	return ""
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+)
+
+const transcriptTempPattern = "transcript-*.html"
+
+func tempDir() string {
+	if dir := os.Getenv("TEMP_DIR"); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
+func writeTempTranscript(content string) (file *os.File, err error) {
+	dir := tempDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	file, err = os.CreateTemp(dir, transcriptTempPattern)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			removeTempFile(file)
+			file = nil
+		}
+	}()
+	if _, err = file.WriteString(content); err != nil {
+		return nil, err
+	}
+	if _, err = file.Seek(0, 0); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func removeTempFile(file *os.File) {
+	file.Close()
+	if err := os.Remove(file.Name()); err != nil && !os.IsNotExist(err) {
+		log.Printf("Could not remove temp file %s: %v", file.Name(), err)
+	}
+}
+
+func sweepOrphanedTranscripts() {
+	removed := 0
+	for _, dir := range []string{tempDir(), "."} {
+		matches, err := filepath.Glob(filepath.Join(dir, transcriptTempPattern))
+		if err != nil {
+			log.Printf("Could not scan %s for orphaned transcripts: %v", dir, err)
+			continue
+		}
+		for _, path := range matches {
+			if err := os.Remove(path); err != nil {
+				log.Printf("Could not remove orphaned transcript %s: %v", path, err)
+				continue
+			}
+			removed++
+		}
+	}
+	if removed > 0 {
+		log.Printf("Removed %d orphaned transcript temp files.", removed)
+	}
+}
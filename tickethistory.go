@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ticketHistoryCommandName = "티켓 기록"
+	ticketHistoryPrefix      = "ticket_history:"
+	ticketHistoryPageSize    = 5
+)
+
+func handleTicketHistoryCommand(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	data := i.ApplicationCommandData()
+	embed, components, err := ticketHistoryPage(data.TargetID, 0)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}, Components: components}})
+}
+
+func handleTicketHistoryPage(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	userID, pageValue, _ := strings.Cut(strings.TrimPrefix(i.MessageComponentData().CustomID, ticketHistoryPrefix), ":")
+	page, _ := strconv.Atoi(pageValue)
+	embed, components, err := ticketHistoryPage(userID, page)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}, Components: components}})
+}
+
+func ticketHistoryPage(userID string, page int) (*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	filter := bson.M{"owner_id": userID, "guild_id": app().GuildID}
+	total, err := app().Tickets.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	pages := int((total + ticketHistoryPageSize - 1) / ticketHistoryPageSize)
+	if page >= pages {
+		page = pages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetSkip(int64(page * ticketHistoryPageSize)).SetLimit(ticketHistoryPageSize)
+	cursor, err := app().Tickets.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		return nil, nil, err
+	}
+	embed := &discordgo.MessageEmbed{Title: "티켓 기록", Description: fmt.Sprintf("<@%s> 님이 접수한 티켓 %d건", userID, total), Color: colorBlue}
+	if total == 0 {
+		embed.Description = fmt.Sprintf("<@%s> 님이 접수한 티켓이 없습니다.", userID)
+		return embed, nil, nil
+	}
+	for _, t := range tickets {
+		value := fmt.Sprintf("%s · %s · 접수 <t:%d:f>", t.Code, ticketStatusLabel(t), t.CreatedAt.Unix())
+		if t.CloseCode != "" {
+			value += " · 종료 코드: " + t.CloseCode
+		}
+		switch {
+		case t.Status == ticketStatusOpen:
+			value += fmt.Sprintf("\n<#%s>", t.ChannelID)
+		case t.TranscriptLink != "":
+			value += fmt.Sprintf("\n[대화록 보기](%s)", t.TranscriptLink)
+		default:
+			value += "\n대화록 없음"
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: t.Name(), Value: value, Inline: false})
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("%d / %d 페이지", page+1, pages)}
+	if pages <= 1 {
+		return embed, nil, nil
+	}
+	components := []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "이전", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("%s%s:%d", ticketHistoryPrefix, userID, page-1), Disabled: page == 0},
+		discordgo.Button{Label: "다음", Style: discordgo.SecondaryButton, CustomID: fmt.Sprintf("%s%s:%d", ticketHistoryPrefix, userID, page+1), Disabled: page >= pages-1},
+	}}}
+	return embed, components, nil
+}
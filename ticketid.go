@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	globalTicketSequence    = "ticket_global"
+	defaultTicketCodePrefix = "GW"
+)
+
+var (
+	ticketCodePattern       = regexp.MustCompile(`\b([A-Z]{2,6})-(\d{4})-(\d{5,})\b`)
+	ticketCodePrefixPattern = regexp.MustCompile(`^[A-Z]{2,6}$`)
+)
+
+func ticketCodePrefix() string {
+	if prefix := getConfig().TicketCodePrefix; prefix != "" {
+		return prefix
+	}
+	return defaultTicketCodePrefix
+}
+
+func ticketCode(id uint64, createdAt time.Time) string {
+	return fmt.Sprintf("%s-%d-%05d", ticketCodePrefix(), createdAt.In(kstLocation).Year(), id)
+}
+
+func parseTicketCode(code string) (uint64, bool) {
+	match := ticketCodePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(code)))
+	if match == nil {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(match[3], 10, 64)
+	return id, err == nil
+}
+
+func assignTicketID(t *ticket) error {
+	if t.ID != 0 {
+		return nil
+	}
+	id, err := getNextSequenceValue(globalTicketSequence)
+	if err != nil {
+		return err
+	}
+	t.ID = id
+	t.Code = ticketCode(id, t.CreatedAt)
+	return nil
+}
+
+func findTicketByID(id uint64) (*ticket, error) {
+	var t ticket
+	if err := app().Tickets.FindOne(context.TODO(), bson.M{"_id": id}).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func ensureTicketIndexes(ctx context.Context) error {
+	err := createIndexes(ctx, app().Tickets, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "channel_id", Value: 1}}, Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"channel_id": bson.M{"$exists": true}})},
+		{Keys: bson.D{{Key: "code", Value: 1}}, Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"code": bson.M{"$exists": true}})},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "category", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "assignee_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "owner_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "subject", Value: "text"}, {Key: "content", Value: "text"}, {Key: "intake.value", Value: "text"}, {Key: "close_reason", Value: "text"}, {Key: "resolution", Value: "text"}}, Options: options.Index().SetName("ticket_search").SetDefaultLanguage("none").SetWeights(bson.M{"subject": 5, "close_reason": 2, "resolution": 2})},
+	}...)
+	if err != nil {
+		return fmt.Errorf("could not create ticket indexes: %w", err)
+	}
+	return nil
+}
+
+func migrateLegacyTicketIDs(ctx context.Context) error {
+	cursor, err := app().Tickets.Find(ctx, bson.M{"_id": bson.M{"$type": "string"}}, options.Find().SetSort(bson.M{"created_at": 1}))
+	if err != nil {
+		return fmt.Errorf("could not list legacy tickets: %w", err)
+	}
+	var docs []bson.M
+	if err := cursor.All(ctx, &docs); err != nil {
+		return fmt.Errorf("could not decode legacy tickets: %w", err)
+	}
+	migrated := 0
+	for _, doc := range docs {
+		channelID, _ := doc["_id"].(string)
+		id, err := getNextSequenceValue(globalTicketSequence)
+		if err != nil {
+			return err
+		}
+		createdAt := time.Now()
+		if created, ok := doc["created_at"].(primitive.DateTime); ok {
+			createdAt = created.Time()
+		}
+		doc["_id"] = int64(id)
+		doc["channel_id"] = channelID
+		doc["code"] = ticketCode(id, createdAt)
+		if _, err := app().Tickets.InsertOne(ctx, doc); err != nil && !mongo.IsDuplicateKeyError(err) {
+			return fmt.Errorf("could not migrate ticket for channel %s: %w", channelID, err)
+		}
+		if _, err := app().Tickets.DeleteOne(ctx, bson.M{"_id": channelID}); err != nil {
+			return fmt.Errorf("could not remove legacy ticket for channel %s: %w", channelID, err)
+		}
+		migrated++
+	}
+	if migrated > 0 {
+		log.Printf("Assigned global IDs to %d existing tickets.", migrated)
+	}
+	return nil
+}
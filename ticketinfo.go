@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+func handleTicketInfo(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	owner := fmt.Sprintf("<@%s>", t.OwnerID)
+	if featuresFor(t.Category).Anonymous {
+		owner = anonymousDisplayName
+	}
+	assignee := "미배정"
+	if t.AssigneeID != "" {
+		assignee = fmt.Sprintf("<@%s>", t.AssigneeID)
+	}
+	var participants []string
+	for _, id := range t.Participants {
+		participants = append(participants, fmt.Sprintf("<@%s>", id))
+	}
+	for _, id := range t.ParticipantRoles {
+		participants = append(participants, fmt.Sprintf("<@&%s>", id))
+	}
+	if len(participants) == 0 {
+		participants = append(participants, "없음")
+	}
+	timeline := []string{fmt.Sprintf("접수: <t:%d:f>", t.CreatedAt.Unix())}
+	if !t.ClaimedAt.IsZero() {
+		timeline = append(timeline, fmt.Sprintf("배정: <t:%d:f>", t.ClaimedAt.Unix()))
+	}
+	if !t.FirstResponseAt.IsZero() {
+		timeline = append(timeline, fmt.Sprintf("첫 응답: <t:%d:f>", t.FirstResponseAt.Unix()))
+	}
+	if !t.ReopenedAt.IsZero() {
+		timeline = append(timeline, fmt.Sprintf("재오픈: <t:%d:f> (%d회)", t.ReopenedAt.Unix(), t.ReopenCount))
+	}
+	if !t.ClosedAt.IsZero() {
+		timeline = append(timeline, fmt.Sprintf("종료: <t:%d:f> · <@%s>", t.ClosedAt.Unix(), t.ClosedBy))
+	}
+	embed := &discordgo.MessageEmbed{
+		Title: "티켓 정보",
+		Color: colorBlue,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "번호", Value: fmt.Sprintf("%s (%s)", t.Name(), t.Code), Inline: true},
+			{Name: "창구", Value: t.Category, Inline: true},
+			{Name: "상태", Value: ticketStatusLabel(*t), Inline: true},
+			{Name: "민원인", Value: owner, Inline: true},
+			{Name: "담당자", Value: assignee, Inline: true},
+			{Name: "참여자", Value: strings.Join(participants, ", "), Inline: false},
+			urgencyField(t),
+			{Name: "시각", Value: strings.Join(timeline, "\n"), Inline: false},
+		},
+		Timestamp: time.Now().In(kstLocation).Format(time.RFC3339),
+	}
+	if t.Status == ticketStatusOpen {
+		embed.Fields = append(embed.Fields, ownerPresenceField(s, t))
+		if field := slaField(t); field != nil {
+			embed.Fields = append(embed.Fields, field)
+		}
+	}
+	if len(t.Tags) > 0 {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: "태그", Value: strings.Join(t.Tags, ", "), Inline: false})
+	}
+	if t.Status != ticketStatusOpen {
+		embed.Fields = append(embed.Fields, closeReasonFields(t)...)
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
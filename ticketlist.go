@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	ticketListPrefix   = "ticket_list:"
+	ticketListPageSize = 10
+)
+
+var ticketStatusChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "진행 중", Value: ticketStatusOpen},
+	{Name: "종료", Value: ticketStatusClosed},
+	{Name: "삭제됨", Value: ticketStatusDeleted},
+}
+
+type ticketListFilter struct {
+	Status   string
+	Category string
+	Assignee string
+	Owner    string
+}
+
+func (f ticketListFilter) query() bson.M {
+	query := bson.M{"status": bson.M{"$ne": ticketStatusDeleted}}
+	if f.Status != "" {
+		query["status"] = f.Status
+	}
+	if f.Category != "" {
+		query["category"] = f.Category
+	}
+	if f.Assignee != "" {
+		query["assignee_id"] = f.Assignee
+	}
+	if f.Owner != "" {
+		query["owner_id"] = f.Owner
+	}
+	return query
+}
+
+func (f ticketListFilter) summary() string {
+	var parts []string
+	if f.Status != "" {
+		for _, choice := range ticketStatusChoices {
+			if choice.Value == f.Status {
+				parts = append(parts, "상태: "+choice.Name)
+			}
+		}
+	}
+	if f.Category != "" {
+		parts = append(parts, "창구: "+f.Category)
+	}
+	if f.Assignee != "" {
+		parts = append(parts, fmt.Sprintf("담당자: <@%s>", f.Assignee))
+	}
+	if f.Owner != "" {
+		parts = append(parts, fmt.Sprintf("민원인: <@%s>", f.Owner))
+	}
+	if len(parts) == 0 {
+		return "삭제되지 않은 모든 티켓"
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (f ticketListFilter) componentID(page int) string {
+	return ticketListPrefix + strings.Join([]string{f.Status, f.Category, f.Assignee, f.Owner, strconv.Itoa(page)}, ":")
+}
+
+func parseTicketListComponentID(customID string) (ticketListFilter, int) {
+	parts := strings.Split(strings.TrimPrefix(customID, ticketListPrefix), ":")
+	if len(parts) != 5 {
+		return ticketListFilter{}, 0
+	}
+	page, _ := strconv.Atoi(parts[4])
+	return ticketListFilter{Status: parts[0], Category: parts[1], Assignee: parts[2], Owner: parts[3]}, page
+}
+
+func ticketListCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "티켓목록",
+		Description: "조건에 맞는 티켓을 최근 접수 순으로 확인합니다.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "status", Description: "상태 (기본: 삭제되지 않은 전체)", Required: false, Choices: ticketStatusChoices},
+			{Type: discordgo.ApplicationCommandOptionString, Name: "topic", Description: "민원 창구", Required: false, Choices: ticketTopicChoices()},
+			{Type: discordgo.ApplicationCommandOptionUser, Name: "assignee", Description: "담당자", Required: false},
+			{Type: discordgo.ApplicationCommandOptionUser, Name: "owner", Description: "민원인", Required: false},
+		},
+	}
+}
+
+func handleTicketList(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var f ticketListFilter
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "status":
+			f.Status = opt.StringValue()
+		case "topic":
+			f.Category = opt.StringValue()
+		case "assignee":
+			f.Assignee = opt.UserValue(nil).ID
+		case "owner":
+			f.Owner = opt.UserValue(nil).ID
+		}
+	}
+	embed, components, err := ticketListPage(f, 0)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}, Components: components}})
+}
+
+func handleTicketListPage(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	f, page := parseTicketListComponentID(i.MessageComponentData().CustomID)
+	embed, components, err := ticketListPage(f, page)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseUpdateMessage, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{embed}, Components: components}})
+}
+
+func ticketListPage(f ticketListFilter, page int) (*discordgo.MessageEmbed, []discordgo.MessageComponent, error) {
+	query := f.query()
+	total, err := app().Tickets.CountDocuments(context.TODO(), query)
+	if err != nil {
+		return nil, nil, err
+	}
+	pages := int((total + ticketListPageSize - 1) / ticketListPageSize)
+	if page >= pages {
+		page = pages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetSkip(int64(page * ticketListPageSize)).SetLimit(ticketListPageSize)
+	cursor, err := app().Tickets.Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		return nil, nil, err
+	}
+	embed := &discordgo.MessageEmbed{Title: "티켓 목록", Color: colorBlue, Fields: []*discordgo.MessageEmbedField{{Name: "조건", Value: f.summary(), Inline: false}}}
+	if total == 0 {
+		embed.Description = "조건에 맞는 티켓이 없습니다."
+		return embed, nil, nil
+	}
+	var lines []string
+	for _, t := range tickets {
+		line := fmt.Sprintf("`%s` %s · %s · <@%s> · <t:%d:R>", t.Code, t.Name(), ticketStatusLabel(t), t.OwnerID, t.CreatedAt.Unix())
+		if t.Status == ticketStatusOpen {
+			line = fmt.Sprintf("`%s` <#%s> · %s · <@%s> · <t:%d:R>", t.Code, t.ChannelID, ticketStatusLabel(t), t.OwnerID, t.CreatedAt.Unix())
+		}
+		if t.AssigneeID != "" {
+			line += fmt.Sprintf(" · 담당 <@%s>", t.AssigneeID)
+		}
+		lines = append(lines, line)
+	}
+	embed.Description = strings.Join(lines, "\n")
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("총 %d건 · %d / %d 페이지", total, page+1, pages)}
+	if pages <= 1 {
+		return embed, nil, nil
+	}
+	components := []discordgo.MessageComponent{discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{Label: "이전", Style: discordgo.SecondaryButton, CustomID: f.componentID(page - 1), Disabled: page == 0},
+		discordgo.Button{Label: "다음", Style: discordgo.SecondaryButton, CustomID: f.componentID(page + 1), Disabled: page >= pages-1},
+	}}}
+	return embed, components, nil
+}
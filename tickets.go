@@ -0,0 +1,267 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	ticketStatusOpen    = "open"
+	ticketStatusClosed  = "closed"
+	ticketStatusDeleted = "deleted"
+)
+
+type ticket struct {
+	ID                  uint64                   `bson:"_id"`
+	Code                string                   `bson:"code"`
+	ChannelID           string                   `bson:"channel_id"`
+	GuildID             string                   `bson:"guild_id"`
+	Category            string                   `bson:"category"`
+	Number              uint64                   `bson:"number"`
+	OwnerID             string                   `bson:"owner_id"`
+	OpenedByID          string                   `bson:"opened_by_id,omitempty"`
+	Nickname            string                   `bson:"nickname,omitempty"`
+	Subject             string                   `bson:"subject,omitempty"`
+	Content             string                   `bson:"content,omitempty"`
+	Intake              []intakeAnswer           `bson:"intake,omitempty"`
+	SealedIntake        string                   `bson:"sealed_intake,omitempty"`
+	AttachmentsPending  bool                     `bson:"attachments_pending,omitempty"`
+	Attachments         []ticketAttachment       `bson:"attachments,omitempty"`
+	IntakeCompletedAt   time.Time                `bson:"intake_completed_at,omitempty"`
+	Forum               bool                     `bson:"forum,omitempty"`
+	Quarantined         bool                     `bson:"quarantined,omitempty"`
+	Sandbox             bool                     `bson:"sandbox,omitempty"`
+	LiveArchive         bool                     `bson:"live_archive,omitempty"`
+	SpamScore           int                      `bson:"spam_score,omitempty"`
+	SpamSignals         []string                 `bson:"spam_signals,omitempty"`
+	Status              string                   `bson:"status"`
+	AssigneeID          string                   `bson:"assignee_id,omitempty"`
+	Participants        []string                 `bson:"participants"`
+	ParticipantRoles    []string                 `bson:"participant_roles"`
+	CreatedAt           time.Time                `bson:"created_at"`
+	ClaimedAt           time.Time                `bson:"claimed_at,omitempty"`
+	AssigneeActiveAt    time.Time                `bson:"assignee_active_at,omitempty"`
+	OwnerActiveAt       time.Time                `bson:"owner_active_at,omitempty"`
+	HandlingSince       time.Time                `bson:"handling_since,omitempty"`
+	HandlingTime        map[string]time.Duration `bson:"handling_time,omitempty"`
+	ClosedAt            time.Time                `bson:"closed_at,omitempty"`
+	ClosedBy            string                   `bson:"closed_by,omitempty"`
+	ReopenedAt          time.Time                `bson:"reopened_at,omitempty"`
+	ReopenCount         int                      `bson:"reopen_count,omitempty"`
+	ResolutionTime      time.Duration            `bson:"resolution_time,omitempty"`
+	OpenDuration        time.Duration            `bson:"open_duration,omitempty"`
+	CloseCode           string                   `bson:"close_code,omitempty"`
+	CloseReason         string                   `bson:"close_reason,omitempty"`
+	Resolution          string                   `bson:"resolution,omitempty"`
+	SelfResolved        bool                     `bson:"self_resolved"`
+	Tags                []string                 `bson:"tags,omitempty"`
+	Overwrites          []permissionOverwrite    `bson:"overwrites,omitempty"`
+	Priority            string                   `bson:"priority,omitempty"`
+	RequesterUrgency    string                   `bson:"requester_urgency,omitempty"`
+	SLAOverride         time.Duration            `bson:"sla_override,omitempty"`
+	FirstResponseDue    time.Time                `bson:"first_response_due,omitempty"`
+	FirstResponseAt     time.Time                `bson:"first_response_at,omitempty"`
+	FirstResponderID    string                   `bson:"first_responder_id,omitempty"`
+	AwaitingReplySince  time.Time                `bson:"awaiting_reply_since,omitempty"`
+	AwaitingMessageID   string                   `bson:"awaiting_message_id,omitempty"`
+	ResolutionDue       time.Time                `bson:"resolution_due,omitempty"`
+	LastEscalatedAt     time.Time                `bson:"last_escalated_at,omitempty"`
+	EscalationCount     int                      `bson:"escalation_count,omitempty"`
+	Events              []ticketEvent            `bson:"events,omitempty"`
+	VoiceChannelID      string                   `bson:"voice_channel_id,omitempty"`
+	VoiceEvents         []voiceEvent             `bson:"voice_events,omitempty"`
+	Language            string                   `bson:"language,omitempty"`
+	Translation         bool                     `bson:"translation,omitempty"`
+	InactivityWarningID string                   `bson:"inactivity_warning_id,omitempty"`
+	InactivityWarnedAt  time.Time                `bson:"inactivity_warned_at,omitempty"`
+	CSATSentAt          time.Time                `bson:"csat_sent_at,omitempty"`
+	Rating              int                      `bson:"rating,omitempty"`
+	Comment             string                   `bson:"comment,omitempty"`
+	RatedAt             time.Time                `bson:"rated_at,omitempty"`
+	TranscriptLink      string                   `bson:"transcript_link,omitempty"`
+}
+
+type permissionOverwrite struct {
+	ID    string                            `bson:"id"`
+	Type  discordgo.PermissionOverwriteType `bson:"type"`
+	Allow int64                             `bson:"allow"`
+	Deny  int64                             `bson:"deny"`
+}
+
+func snapshotOverwrites(overwrites []*discordgo.PermissionOverwrite) []permissionOverwrite {
+	snapshot := make([]permissionOverwrite, 0, len(overwrites))
+	for _, o := range overwrites {
+		snapshot = append(snapshot, permissionOverwrite{ID: o.ID, Type: o.Type, Allow: o.Allow, Deny: o.Deny})
+	}
+	return snapshot
+}
+
+func (t *ticket) restoreOverwrites() []*discordgo.PermissionOverwrite {
+	overwrites := make([]*discordgo.PermissionOverwrite, 0, len(t.Overwrites))
+	for _, o := range t.Overwrites {
+		overwrites = append(overwrites, &discordgo.PermissionOverwrite{ID: o.ID, Type: o.Type, Allow: o.Allow, Deny: o.Deny})
+	}
+	return overwrites
+}
+
+func (t *ticket) Name() string {
+	return fmt.Sprintf("%s-%04d", t.Category, t.Number)
+}
+
+func insertTicket(t *ticket) error {
+	if t.Participants == nil {
+		t.Participants = []string{}
+	}
+	if t.ParticipantRoles == nil {
+		t.ParticipantRoles = []string{}
+	}
+	if err := assignTicketID(t); err != nil {
+		return fmt.Errorf("could not assign an ID to ticket '%s': %w", t.Name(), err)
+	}
+	doc := t
+	if featuresFor(t.Category).Encrypted {
+		sealed := *t
+		if err := sealTicketIntake(&sealed); err != nil {
+			return fmt.Errorf("could not seal intake of ticket '%s': %w", t.Name(), err)
+		}
+		doc = &sealed
+	}
+	_, err := app().Tickets.InsertOne(context.TODO(), doc)
+	if err != nil {
+		return fmt.Errorf("could not insert ticket '%s': %w", t.Name(), err)
+	}
+	incCounter("potatobot_tickets_opened_total", metricLabel("category", t.Category))
+	return nil
+}
+
+func discardTicket(s *discordgo.Session, t *ticket) {
+	log.Printf("Rolling back ticket '%s' after its greeting message could not be sent.", t.Name())
+	incCounter("potatobot_ticket_rollbacks_total", metricLabel("category", t.Category))
+	if err := retryDiscord("channel delete", func() error {
+		_, err := s.ChannelDelete(t.ChannelID)
+		return err
+	}); err != nil {
+		log.Printf("Could not delete channel %s of rolled back ticket '%s': %v", t.ChannelID, t.Name(), err)
+	}
+	if _, err := app().Tickets.DeleteOne(context.TODO(), bson.M{"_id": t.ID}); err != nil {
+		log.Printf("Could not delete record of rolled back ticket '%s': %v", t.Name(), err)
+	}
+}
+
+func findTicket(channelID string) (*ticket, error) {
+	var t ticket
+	if err := app().Tickets.FindOne(context.TODO(), bson.M{"channel_id": channelID}).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func findTicketByNumber(category string, number uint64) (*ticket, error) {
+	var t ticket
+	filter := bson.M{"category": category, "number": number, "status": bson.M{"$ne": ticketStatusDeleted}}
+	if err := app().Tickets.FindOne(context.TODO(), filter).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func updateTicket(channelID string, update bson.M) error {
+	_, err := app().Tickets.UpdateOne(context.TODO(), bson.M{"channel_id": channelID}, update)
+	if err != nil {
+		return fmt.Errorf("could not update ticket for channel %s: %w", channelID, err)
+	}
+	return nil
+}
+
+func requireTicket(s *discordgo.Session, i *discordgo.InteractionCreate) *ticket {
+	t, err := findTicket(i.ChannelID)
+	if err == mongo.ErrNoDocuments {
+		respondError(s, i, errNotTicketChannel, nil)
+		return nil
+	}
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return nil
+	}
+	return t
+}
+
+func ticketForChannel(ch *discordgo.Channel) *ticket {
+	if app().Tickets == nil {
+		return nil
+	}
+	t, err := findTicket(ch.ID)
+	if err != nil {
+		return nil
+	}
+	return t
+}
+
+func ticketOwnerID(ch *discordgo.Channel) string {
+	if t := ticketForChannel(ch); t != nil {
+		return t.OwnerID
+	}
+	return getUserIDFromTopic(ch.Topic)
+}
+
+func containsID(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func findTicketMessage(s *discordgo.Session, channelID string) (*discordgo.Message, error) {
+	messages, err := s.ChannelMessages(channelID, 100, "", "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, msg := range messages {
+		if msg.Author.ID == s.State.User.ID && len(msg.Embeds) > 0 && len(msg.Components) > 0 && msg.Embeds[0].Title != "관리자 패널" {
+			return msg, nil
+		}
+	}
+	return nil, nil
+}
+
+func setTicketMessageAssignee(s *discordgo.Session, ticketMessage *discordgo.Message, mention string) error {
+	originalEmbed := ticketMessage.Embeds[0]
+	assigneeFieldExists := false
+	for _, field := range originalEmbed.Fields {
+		if field.Name == "담당자" {
+			field.Value = mention
+			assigneeFieldExists = true
+			break
+		}
+	}
+	if !assigneeFieldExists {
+		originalEmbed.Fields = append(originalEmbed.Fields, &discordgo.MessageEmbedField{Name: "담당자", Value: mention, Inline: false})
+	}
+	for _, row := range ticketMessage.Components {
+		if actionsRow, ok := row.(*discordgo.ActionsRow); ok {
+			for j, comp := range actionsRow.Components {
+				if button, ok := comp.(*discordgo.Button); ok && strings.HasPrefix(button.CustomID, actionClaim) && !button.Disabled {
+					button.Disabled = true
+					actionsRow.Components[j] = button
+				}
+			}
+		}
+	}
+	editedEmbeds := []*discordgo.MessageEmbed{originalEmbed}
+	_, err := s.ChannelMessageEditComplex(&discordgo.MessageEdit{
+		Channel:    ticketMessage.ChannelID,
+		ID:         ticketMessage.ID,
+		Embeds:     &editedEmbeds,
+		Components: &ticketMessage.Components,
+	})
+	return err
+}
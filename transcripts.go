@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/klauspost/compress/zstd"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultTranscriptArchiveMonths = 6
+	maxStoredTranscriptSize        = 15 << 20
+	transcriptArchiveInterval      = 24 * time.Hour
+)
+
+var inlineImagePattern = regexp.MustCompile(`src="data:[^"]*" data-src="([^"]*)"`)
+
+type storedTranscript struct {
+	ChannelID    string    `bson:"_id"`
+	Name         string    `bson:"name"`
+	Category     string    `bson:"category"`
+	OwnerID      string    `bson:"owner_id"`
+	Participants []string  `bson:"participants"`
+	MessageCount int       `bson:"message_count"`
+	Size         int       `bson:"size"`
+	CreatedAt    time.Time `bson:"created_at"`
+	HTML         string    `bson:"html,omitempty"`
+	File         string    `bson:"file,omitempty"`
+	ArchivedAt   time.Time `bson:"archived_at,omitempty"`
+	ArchivedSize int       `bson:"archived_size,omitempty"`
+}
+
+type archivedTranscript struct {
+	ChannelID   string    `bson:"_id"`
+	Compression string    `bson:"compression"`
+	Data        []byte    `bson:"data"`
+	ArchivedAt  time.Time `bson:"archived_at"`
+}
+
+func connectTranscriptStore(b *bot) {
+	b.Transcripts = b.Store.Collection("transcripts")
+	b.TranscriptArchive = b.Store.Collection("transcripts_archive")
+	if ms, ok := b.Store.(mongoStore); ok {
+		if name := os.Getenv("TRANSCRIPT_ARCHIVE_DATABASE"); name != "" {
+			b.TranscriptArchive = ms.client.Database(name).Collection("transcripts_archive")
+		}
+	}
+	b.TranscriptMessages = b.Store.Collection("transcript_messages")
+	if dir := transcriptDir(); dir != "" {
+		log.Printf("Storing transcript files under %s.", dir)
+	}
+}
+
+func saveTranscript(channel *discordgo.Channel, messages []*discordgo.Message, htmlContent string) {
+	if app().Transcripts == nil {
+		return
+	}
+	indexTranscriptMessages(channel, messages)
+	if len(htmlContent) > maxStoredTranscriptSize && transcriptDir() == "" {
+		log.Printf("Transcript for '%s' is %d bytes; too large to store in MongoDB.", channel.Name, len(htmlContent))
+		return
+	}
+	if featuresFor(ticketCategory(channel)).Encrypted {
+		sealed, err := seal([]byte(htmlContent))
+		if err != nil {
+			log.Printf("Not storing transcript for '%s' because it could not be sealed: %v", channel.Name, err)
+			return
+		}
+		htmlContent = sealed
+	}
+	var participants []string
+	seen := make(map[string]bool)
+	for _, msg := range messages {
+		if !seen[msg.Author.ID] {
+			seen[msg.Author.ID] = true
+			participants = append(participants, msg.Author.ID)
+		}
+	}
+	doc := storedTranscript{
+		ChannelID:    channel.ID,
+		Name:         channel.Name,
+		Category:     ticketCategory(channel),
+		OwnerID:      ticketOwnerID(channel),
+		Participants: participants,
+		MessageCount: len(messages),
+		Size:         len(htmlContent),
+		CreatedAt:    time.Now(),
+		HTML:         htmlContent,
+	}
+	if transcriptDir() != "" {
+		file, err := writeTranscriptFile(doc.CreatedAt, channel.ID, htmlContent)
+		if err != nil {
+			log.Printf("Could not write transcript file for '%s': %v", channel.Name, err)
+			return
+		}
+		doc.HTML, doc.File = "", file
+	}
+	_, err := app().Transcripts.ReplaceOne(context.TODO(), bson.M{"_id": channel.ID}, doc, options.Replace().SetUpsert(true))
+	if err != nil {
+		log.Printf("Could not store transcript for '%s': %v", channel.Name, err)
+	}
+}
+
+func loadTranscriptHTML(channelID string) (string, error) {
+	content, err := loadStoredTranscript(channelID)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(content, sealedPrefix) {
+		return "", errTranscriptSealed
+	}
+	return content, nil
+}
+
+func loadStoredTranscript(channelID string) (string, error) {
+	var doc storedTranscript
+	if err := app().Transcripts.FindOne(context.TODO(), bson.M{"_id": channelID}).Decode(&doc); err != nil {
+		return "", err
+	}
+	if doc.File != "" {
+		return readTranscriptFile(doc.File)
+	}
+	if doc.ArchivedAt.IsZero() {
+		return doc.HTML, nil
+	}
+	var archived archivedTranscript
+	if err := app().TranscriptArchive.FindOne(context.TODO(), bson.M{"_id": channelID}).Decode(&archived); err != nil {
+		return "", fmt.Errorf("could not load archived transcript for %s: %w", channelID, err)
+	}
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		return "", err
+	}
+	defer decoder.Close()
+	data, err := decoder.DecodeAll(archived.Data, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not decompress archived transcript for %s: %w", channelID, err)
+	}
+	return string(data), nil
+}
+
+func writeTranscriptFile(createdAt time.Time, channelID, content string) (string, error) {
+	file := filepath.Join(createdAt.In(kstLocation).Format("2006-01"), channelID+".html")
+	path := filepath.Join(transcriptDir(), file)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+	return file, nil
+}
+
+func readTranscriptFile(file string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(transcriptDir(), file))
+	if err != nil {
+		return "", fmt.Errorf("could not read transcript file %s: %w", file, err)
+	}
+	return string(data), nil
+}
+
+func transcriptArchiveAge() int {
+	v := os.Getenv("TRANSCRIPT_ARCHIVE_MONTHS")
+	if v == "" {
+		return defaultTranscriptArchiveMonths
+	}
+	months, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Invalid TRANSCRIPT_ARCHIVE_MONTHS '%s': %v", v, err)
+		return defaultTranscriptArchiveMonths
+	}
+	return months
+}
+
+func archiveTranscriptsJob() error {
+	archived, saved, err := archiveOldTranscripts()
+	if err != nil {
+		return err
+	}
+	if archived > 0 {
+		log.Printf("Archived %d transcripts to cold storage, saving %d bytes.", archived, saved)
+	}
+	return nil
+}
+
+func archiveOldTranscripts() (int, int, error) {
+	cutoff := time.Now().AddDate(0, -transcriptArchiveAge(), 0)
+	filter := bson.M{"created_at": bson.M{"$lt": cutoff}, "archived_at": bson.M{"$exists": false}, "file": bson.M{"$exists": false}}
+	cursor, err := app().Transcripts.Find(context.TODO(), filter)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer cursor.Close(context.TODO())
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer encoder.Close()
+	archived, saved := 0, 0
+	for cursor.Next(context.TODO()) {
+		var doc storedTranscript
+		if err := cursor.Decode(&doc); err != nil {
+			return archived, saved, err
+		}
+		stripped := inlineImagePattern.ReplaceAllString(doc.HTML, `src="$1" data-src="$1"`)
+		data := encoder.EncodeAll([]byte(stripped), nil)
+		now := time.Now()
+		_, err := app().TranscriptArchive.ReplaceOne(context.TODO(), bson.M{"_id": doc.ChannelID}, archivedTranscript{ChannelID: doc.ChannelID, Compression: "zstd", Data: data, ArchivedAt: now}, options.Replace().SetUpsert(true))
+		if err != nil {
+			log.Printf("Could not archive transcript '%s': %v", doc.Name, err)
+			continue
+		}
+		_, err = app().Transcripts.UpdateOne(context.TODO(), bson.M{"_id": doc.ChannelID}, bson.M{"$set": bson.M{"archived_at": now, "archived_size": len(data)}, "$unset": bson.M{"html": ""}})
+		if err != nil {
+			log.Printf("Could not mark transcript '%s' as archived: %v", doc.Name, err)
+			continue
+		}
+		archived++
+		saved += doc.Size - len(data)
+	}
+	return archived, saved, cursor.Err()
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxTranscriptSearchResults = 10
+	transcriptExcerptRadius    = 40
+)
+
+type transcriptMessage struct {
+	MessageID  string    `bson:"_id"`
+	ChannelID  string    `bson:"channel_id"`
+	TicketName string    `bson:"ticket_name"`
+	Category   string    `bson:"category"`
+	OwnerID    string    `bson:"owner_id"`
+	AuthorID   string    `bson:"author_id"`
+	AuthorName string    `bson:"author_name"`
+	Content    string    `bson:"content"`
+	SentAt     time.Time `bson:"sent_at"`
+}
+
+func ensureTranscriptIndexes(ctx context.Context) error {
+	if app().TranscriptMessages == nil {
+		return nil
+	}
+	err := createIndexes(ctx, app().TranscriptMessages, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "channel_id", Value: 1}, {Key: "sent_at", Value: 1}}},
+		{Keys: bson.D{{Key: "content", Value: "text"}}, Options: options.Index().SetName("transcript_search").SetDefaultLanguage("none")},
+	}...)
+	if err != nil {
+		return fmt.Errorf("could not create transcript message indexes: %w", err)
+	}
+	return nil
+}
+
+func indexTranscriptMessages(channel *discordgo.Channel, messages []*discordgo.Message) {
+	if app().TranscriptMessages == nil || featuresFor(ticketCategory(channel)).Encrypted {
+		return
+	}
+	if _, err := app().TranscriptMessages.DeleteMany(context.TODO(), bson.M{"channel_id": channel.ID}); err != nil {
+		log.Printf("Could not clear indexed messages of '%s': %v", channel.Name, err)
+		return
+	}
+	ownerID := ticketOwnerID(channel)
+	var docs []interface{}
+	for _, msg := range messages {
+		if msg.Author == nil || msg.Author.Bot || strings.TrimSpace(msg.Content) == "" {
+			continue
+		}
+		author := msg.Author.Username
+		if featuresFor(ticketCategory(channel)).Anonymous && msg.Author.ID == ownerID {
+			author = anonymousDisplayName
+		}
+		docs = append(docs, transcriptMessage{
+			MessageID:  msg.ID,
+			ChannelID:  channel.ID,
+			TicketName: channel.Name,
+			Category:   ticketCategory(channel),
+			OwnerID:    ownerID,
+			AuthorID:   msg.Author.ID,
+			AuthorName: author,
+			Content:    msg.Content,
+			SentAt:     msg.Timestamp,
+		})
+	}
+	if len(docs) == 0 {
+		return
+	}
+	if _, err := app().TranscriptMessages.InsertMany(context.TODO(), docs, options.InsertMany().SetOrdered(false)); err != nil {
+		log.Printf("Could not index messages of '%s' for search: %v", channel.Name, err)
+	}
+}
+
+func transcriptExcerpt(content, query string) string {
+	content = strings.Join(strings.Fields(content), " ")
+	runes := []rune(content)
+	start := 0
+	lower := strings.ToLower(content)
+	for _, term := range strings.Fields(strings.ToLower(strings.ReplaceAll(query, "\"", ""))) {
+		if idx := strings.Index(lower, term); idx >= 0 {
+			start = len([]rune(lower[:idx]))
+			break
+		}
+	}
+	from, to := start-transcriptExcerptRadius, start+transcriptExcerptRadius*2
+	if from < 0 {
+		from = 0
+	}
+	if to > len(runes) {
+		to = len(runes)
+	}
+	excerpt := string(runes[from:to])
+	if from > 0 {
+		excerpt = "…" + excerpt
+	}
+	if to < len(runes) {
+		excerpt += "…"
+	}
+	return excerpt
+}
+
+func transcriptSearchCommand() *discordgo.ApplicationCommand {
+	return &discordgo.ApplicationCommand{
+		Name:        "대화검색",
+		Description: "보관된 대화록에서 메시지를 검색하고 해당 메시지로 이동합니다.",
+		Options: []*discordgo.ApplicationCommandOption{
+			{Type: discordgo.ApplicationCommandOptionString, Name: "query", Description: "검색어 (\"따옴표\"로 정확한 구절)", Required: true},
+			{Type: discordgo.ApplicationCommandOptionChannel, Name: "channel", Description: "이 티켓 채널에서만 검색", Required: false, ChannelTypes: []discordgo.ChannelType{discordgo.ChannelTypeGuildText}},
+			{Type: discordgo.ApplicationCommandOptionUser, Name: "user", Description: "이 민원인의 티켓에서만 검색", Required: false},
+		},
+	}
+}
+
+func handleTranscriptSearch(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	filter := bson.M{}
+	query := ""
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "query":
+			query = strings.TrimSpace(opt.StringValue())
+		case "channel":
+			filter["channel_id"] = opt.ChannelValue(nil).ID
+		case "user":
+			filter["owner_id"] = opt.UserValue(nil).ID
+		}
+	}
+	filter["$text"] = bson.M{"$search": query}
+	opts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}, {Key: "sent_at", Value: -1}}).
+		SetLimit(maxTranscriptSearchResults)
+	cursor, err := app().TranscriptMessages.Find(context.TODO(), filter, opts)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	var matches []transcriptMessage
+	if err := cursor.All(context.TODO(), &matches); err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	embed := &discordgo.MessageEmbed{Title: fmt.Sprintf("'%s' 대화록 검색 결과", query), Color: colorBlue}
+	if len(matches) == 0 {
+		embed.Description = "일치하는 메시지가 없습니다. 대화록은 티켓이 닫힌 뒤 검색할 수 있습니다."
+	}
+	for _, m := range matches {
+		value := fmt.Sprintf("%s: %s\n<t:%d:f> · [메시지로 이동](https://discord.com/channels/%s/%s/%s)", m.AuthorName, transcriptExcerpt(m.Content, query), m.SentAt.Unix(), app().GuildID, m.ChannelID, m.MessageID)
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: m.TicketName, Value: value, Inline: false})
+	}
+	if len(matches) == maxTranscriptSearchResults {
+		embed.Footer = &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("관련도가 높은 %d건만 표시합니다.", maxTranscriptSearchResults)}
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
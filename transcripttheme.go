@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	transcriptThemeDark  = "dark"
+	transcriptThemeLight = "light"
+	transcriptThemePrint = "print"
+	compactGroupWindow   = 7 * time.Minute
+)
+
+type transcriptStyle struct {
+	Theme   string `bson:"theme,omitempty"`
+	Compact bool   `bson:"compact"`
+}
+
+var transcriptThemeChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "다크", Value: transcriptThemeDark},
+	{Name: "라이트", Value: transcriptThemeLight},
+	{Name: "인쇄용", Value: transcriptThemePrint},
+}
+
+var transcriptThemeCSS = map[string]string{
+	transcriptThemeLight: `body{background-color:#fff;color:#2e3338;}.username,.embed-title{color:#060607;}.embed,.attachment-file{background-color:#f2f3f5;border-color:#e3e5e8;}.timestamp,.embed-footer,.system-line,.attachment-file-size{color:#5c5e66;}`,
+	transcriptThemePrint: `body{background-color:#fff;color:#000;font-family:'Noto Sans KR',Arial,sans-serif;font-size:11pt;}.container{max-width:none;padding:0;}.avatar,.attachment-image,.embed-thumbnail,.embed-image,.embed-author-icon,.embed-footer-icon{display:none;}.username,.embed-title{color:#000;}.bot-tag{background:none;color:#000;border:1px solid #000;}.timestamp,.embed-footer,.system-line{color:#444;}.embed{background:none;border:1px solid #999;border-left-width:4px;}.attachment-file{background:none;border:1px solid #999;}.attachment-file-name a{color:#000;}.attachment-file-size{color:#444;}.message{margin-bottom:10px;page-break-inside:avoid;}.system-line{margin-left:0;}a{color:#000;text-decoration:none;}@page{margin:15mm;}`,
+}
+
+const compactTranscriptCSS = `.message.grouped{margin-top:-18px;}.avatar-spacer{width:40px;min-width:40px;margin-right:15px;}`
+
+func defaultTranscriptStyle() transcriptStyle {
+	return getConfig().TranscriptStyle.resolved()
+}
+
+func (st transcriptStyle) resolved() transcriptStyle {
+	if st.Theme == "" {
+		st.Theme = transcriptThemeDark
+	}
+	return st
+}
+
+func (st transcriptStyle) css() string {
+	css := transcriptThemeCSS[st.Theme]
+	if st.Compact {
+		css += compactTranscriptCSS
+	}
+	return css
+}
+
+func (st transcriptStyle) label() string {
+	theme := st.Theme
+	for _, choice := range transcriptThemeChoices {
+		if choice.Value == st.Theme {
+			theme = choice.Name
+		}
+	}
+	if st.Compact {
+		return theme + " · 간결 모드"
+	}
+	return theme
+}
+
+func groupsWith(prev, msg *discordgo.Message) bool {
+	return prev != nil && prev.Author.ID == msg.Author.ID && msg.Timestamp.Sub(prev.Timestamp) < compactGroupWindow
+}
+
+func handleTranscriptPreview(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	style := defaultTranscriptStyle()
+	for _, opt := range i.ApplicationCommandData().Options {
+		switch opt.Name {
+		case "theme":
+			style.Theme = opt.StringValue()
+		case "compact":
+			style.Compact = opt.BoolValue()
+		}
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseDeferredChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral}})
+	ch, err := s.Channel(i.ChannelID)
+	if err != nil {
+		respondError(s, i, errChannelLookupFailed, err)
+		return
+	}
+	messages, err := ticketMessages(s, ch)
+	if err != nil {
+		embeds := []*discordgo.MessageEmbed{{Title: "대화록", Description: fmt.Sprintf("메시지를 불러오지 못했습니다: %v", err), Color: colorRed}}
+		s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds})
+		return
+	}
+	content := renderTranscript(ch, messages, style)
+	embeds := []*discordgo.MessageEmbed{{Title: "대화록", Description: fmt.Sprintf("%s 대화록을 %s 스타일로 만들었습니다. (메시지 %d개)", t.Name(), style.label(), len(messages)), Color: colorGreen}}
+	files := []*discordgo.File{{Name: fmt.Sprintf("transcript-%s-%s.html", ch.Name, style.Theme), ContentType: "text/html", Reader: bytes.NewReader([]byte(content))}}
+	s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{Embeds: &embeds, Files: files})
+}
+
+func parseTranscriptTheme(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	for _, choice := range transcriptThemeChoices {
+		if choice.Value == value {
+			return value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	trendMonths          = 6
+	trendMaxSeries       = 7
+	trendMaxTopicCharts  = 8
+	maxTrendChartEmbeds  = 9
+	trendChartWidth      = 640
+	trendChartHeight     = 320
+	trendChartMargin     = 24
+	trendGridLines       = 4
+	trendOtherLabel      = "기타"
+	trendUnclassifiedTag = "미분류"
+)
+
+var trendPalette = []struct {
+	Swatch string
+	Color  color.RGBA
+}{
+	{"🟥", color.RGBA{0xdd, 0x2e, 0x44, 0xff}},
+	{"🟧", color.RGBA{0xf4, 0x90, 0x0c, 0xff}},
+	{"🟨", color.RGBA{0xfd, 0xcb, 0x58, 0xff}},
+	{"🟩", color.RGBA{0x78, 0xb1, 0x59, 0xff}},
+	{"🟦", color.RGBA{0x55, 0xac, 0xee, 0xff}},
+	{"🟪", color.RGBA{0xaa, 0x8e, 0xd6, 0xff}},
+	{"🟫", color.RGBA{0xc1, 0x69, 0x4f, 0xff}},
+}
+
+var trendOtherColor = color.RGBA{0x31, 0x37, 0x3d, 0xff}
+
+type trendChart struct {
+	Title  string
+	Months []time.Time
+	Series []string
+	Counts map[string][]int
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.In(kstLocation)
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, kstLocation)
+}
+
+func collectTrendCharts(last time.Time) ([]*trendChart, error) {
+	end := monthStart(last).AddDate(0, 1, 0)
+	start := end.AddDate(0, -trendMonths, 0)
+	window := bson.M{"$gte": start, "$lt": end}
+	cursor, err := app().Tickets.Find(context.TODO(), bson.M{"status": bson.M{"$ne": ticketStatusDeleted}, "$or": []bson.M{{"created_at": window}, {"closed_at": window}}})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch tickets: %w", err)
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		return nil, fmt.Errorf("could not decode tickets: %w", err)
+	}
+	months := make([]time.Time, trendMonths)
+	for idx := range months {
+		months[idx] = start.AddDate(0, idx, 0)
+	}
+	monthIndex := func(t time.Time) int {
+		if t.Before(start) || !t.Before(end) {
+			return -1
+		}
+		m := monthStart(t)
+		return (m.Year()-start.Year())*12 + int(m.Month()-start.Month())
+	}
+	volume := &trendChart{Title: "창구별 접수량", Months: months, Counts: make(map[string][]int)}
+	byTopic := make(map[string]*trendChart)
+	for _, t := range tickets {
+		if idx := monthIndex(t.CreatedAt); idx >= 0 {
+			volume.add(t.Category, idx)
+		}
+		idx := monthIndex(t.ClosedAt)
+		if t.Status != ticketStatusClosed || idx < 0 {
+			continue
+		}
+		chart := byTopic[t.Category]
+		if chart == nil {
+			chart = &trendChart{Title: t.Category + " 종료 코드", Months: months, Counts: make(map[string][]int)}
+			byTopic[t.Category] = chart
+		}
+		code := t.CloseCode
+		if code == "" {
+			code = trendUnclassifiedTag
+		}
+		chart.add(code, idx)
+	}
+	charts := []*trendChart{volume}
+	topics := make([]string, 0, len(byTopic))
+	for topic := range byTopic {
+		topics = append(topics, topic)
+	}
+	sort.Slice(topics, func(a, b int) bool { return byTopic[topics[a]].total() > byTopic[topics[b]].total() })
+	if len(topics) > trendMaxTopicCharts {
+		topics = topics[:trendMaxTopicCharts]
+	}
+	for _, topic := range topics {
+		charts = append(charts, byTopic[topic])
+	}
+	for _, chart := range charts {
+		chart.foldSeries()
+	}
+	return charts, nil
+}
+
+func (c *trendChart) add(series string, month int) {
+	if c.Counts[series] == nil {
+		c.Counts[series] = make([]int, len(c.Months))
+	}
+	c.Counts[series][month]++
+}
+
+func (c *trendChart) seriesTotal(series string) int {
+	total := 0
+	for _, n := range c.Counts[series] {
+		total += n
+	}
+	return total
+}
+
+func (c *trendChart) total() int {
+	total := 0
+	for series := range c.Counts {
+		total += c.seriesTotal(series)
+	}
+	return total
+}
+
+func (c *trendChart) monthTotal(month int) int {
+	total := 0
+	for _, counts := range c.Counts {
+		total += counts[month]
+	}
+	return total
+}
+
+func (c *trendChart) foldSeries() {
+	c.Series = make([]string, 0, len(c.Counts))
+	for series := range c.Counts {
+		c.Series = append(c.Series, series)
+	}
+	sort.Slice(c.Series, func(a, b int) bool {
+		ta, tb := c.seriesTotal(c.Series[a]), c.seriesTotal(c.Series[b])
+		if ta != tb {
+			return ta > tb
+		}
+		return c.Series[a] < c.Series[b]
+	})
+	if len(c.Series) <= trendMaxSeries {
+		return
+	}
+	other := make([]int, len(c.Months))
+	for _, series := range c.Series[trendMaxSeries:] {
+		for idx, n := range c.Counts[series] {
+			other[idx] += n
+		}
+		delete(c.Counts, series)
+	}
+	c.Series = append(c.Series[:trendMaxSeries], trendOtherLabel)
+	c.Counts[trendOtherLabel] = other
+}
+
+func (c *trendChart) seriesColor(idx int) color.RGBA {
+	if c.Series[idx] == trendOtherLabel {
+		return trendOtherColor
+	}
+	return trendPalette[idx%len(trendPalette)].Color
+}
+
+func (c *trendChart) seriesSwatch(idx int) string {
+	if c.Series[idx] == trendOtherLabel {
+		return "⬛"
+	}
+	return trendPalette[idx%len(trendPalette)].Swatch
+}
+
+func (c *trendChart) scale() int {
+	peak := 0
+	for idx := range c.Months {
+		if n := c.monthTotal(idx); n > peak {
+			peak = n
+		}
+	}
+	step := (peak + trendGridLines - 1) / trendGridLines
+	if step < 1 {
+		step = 1
+	}
+	return step * trendGridLines
+}
+
+func (c *trendChart) renderPNG() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, trendChartWidth, trendChartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	plot := image.Rect(trendChartMargin, trendChartMargin, trendChartWidth-trendChartMargin, trendChartHeight-trendChartMargin)
+	grid := color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+	for line := 0; line <= trendGridLines; line++ {
+		y := plot.Max.Y - plot.Dy()*line/trendGridLines
+		draw.Draw(img, image.Rect(plot.Min.X, y, plot.Max.X, y+1), &image.Uniform{grid}, image.Point{}, draw.Src)
+	}
+	scale := c.scale()
+	slot := plot.Dx() / len(c.Months)
+	barWidth := slot * 3 / 5
+	for month := range c.Months {
+		x := plot.Min.X + slot*month + (slot-barWidth)/2
+		y := plot.Max.Y
+		for idx, series := range c.Series {
+			height := plot.Dy() * c.Counts[series][month] / scale
+			if height == 0 {
+				continue
+			}
+			draw.Draw(img, image.Rect(x, y-height, x+barWidth, y), &image.Uniform{c.seriesColor(idx)}, image.Point{}, draw.Src)
+			y -= height
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *trendChart) embed(fileName string) *discordgo.MessageEmbed {
+	var totals []string
+	for idx, month := range c.Months {
+		totals = append(totals, fmt.Sprintf("%s %d건", month.Format("01월"), c.monthTotal(idx)))
+	}
+	var legend []string
+	for idx, series := range c.Series {
+		legend = append(legend, fmt.Sprintf("%s %s %d건", c.seriesSwatch(idx), series, c.seriesTotal(series)))
+	}
+	return &discordgo.MessageEmbed{
+		Title:       fmt.Sprintf("%s (%s ~ %s)", c.Title, c.Months[0].Format("2006-01"), c.Months[len(c.Months)-1].Format("2006-01")),
+		Description: fmt.Sprintf("왼쪽부터 월별 막대이며 세로축 최대는 %d건입니다.\n%s", c.scale(), strings.Join(totals, " · ")),
+		Color:       colorBlue,
+		Fields:      []*discordgo.MessageEmbedField{{Name: "범례", Value: strings.Join(legend, "\n"), Inline: false}},
+		Image:       &discordgo.MessageEmbedImage{URL: "attachment://" + fileName},
+	}
+}
+
+func trendChartAttachments(last time.Time, limit int) ([]*discordgo.MessageEmbed, []*discordgo.File, error) {
+	charts, err := collectTrendCharts(last)
+	if err != nil {
+		return nil, nil, err
+	}
+	var embeds []*discordgo.MessageEmbed
+	var files []*discordgo.File
+	for idx, chart := range charts {
+		if len(embeds) >= limit {
+			break
+		}
+		if chart.total() == 0 {
+			continue
+		}
+		data, err := chart.renderPNG()
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not render %s chart: %w", chart.Title, err)
+		}
+		fileName := fmt.Sprintf("trend-%d.png", idx)
+		embeds = append(embeds, chart.embed(fileName))
+		files = append(files, &discordgo.File{Name: fileName, ContentType: "image/png", Reader: bytes.NewReader(data)})
+	}
+	return embeds, files, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const maxUnansweredTickets = 15
+
+func trackAwaitingReply(m *discordgo.MessageCreate, t *ticket) {
+	var filter, update bson.M
+	ownerPosted := m.Author.ID == t.OwnerID
+	switch {
+	case ownerPosted:
+		filter = bson.M{"channel_id": t.ChannelID, "awaiting_reply_since": bson.M{"$exists": false}}
+		update = bson.M{"$set": bson.M{"awaiting_reply_since": m.Timestamp, "awaiting_message_id": m.ID}}
+	case m.Member != nil && hasSupportRole(m.Member):
+		filter = bson.M{"channel_id": t.ChannelID, "awaiting_reply_since": bson.M{"$exists": true}}
+		update = bson.M{"$unset": bson.M{"awaiting_reply_since": "", "awaiting_message_id": ""}}
+	default:
+		return
+	}
+	if _, err := app().Tickets.UpdateOne(context.TODO(), filter, update); err != nil {
+		log.Printf("Could not track reply state for '%s': %v", t.Name(), err)
+		return
+	}
+	if ownerPosted {
+		resumeHandling(t.ChannelID, m.Timestamp)
+	} else {
+		pauseHandling(t.ChannelID, m.Timestamp)
+	}
+}
+
+func (t *ticket) awaitingLink() string {
+	if t.AwaitingMessageID == "" {
+		return fmt.Sprintf("<#%s>", t.ChannelID)
+	}
+	return fmt.Sprintf("<#%s> · [메시지로 이동](https://discord.com/channels/%s/%s/%s)", t.ChannelID, t.GuildID, t.ChannelID, t.AwaitingMessageID)
+}
+
+func handleUnansweredTickets(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	filter := bson.M{"status": ticketStatusOpen, "awaiting_reply_since": bson.M{"$exists": true}}
+	if len(i.ApplicationCommandData().Options) > 0 {
+		filter["category"] = i.ApplicationCommandData().Options[0].StringValue()
+	}
+	cursor, err := app().Tickets.Find(context.TODO(), filter, options.Find().SetSort(bson.M{"awaiting_reply_since": 1}).SetLimit(maxUnansweredTickets))
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	total, _ := app().Tickets.CountDocuments(context.TODO(), filter)
+	now := time.Now()
+	var lines []string
+	for _, t := range tickets {
+		line := fmt.Sprintf("%s · %s 대기", t.awaitingLink(), formatWait(now.Sub(t.AwaitingReplySince)))
+		if t.AssigneeID != "" {
+			line += fmt.Sprintf(" · <@%s>", t.AssigneeID)
+		} else {
+			line += " · 미배정"
+		}
+		if t.FirstResponseAt.IsZero() {
+			line += " · 첫 응답 전"
+		}
+		lines = append(lines, line)
+	}
+	embed := &discordgo.MessageEmbed{Title: "미응답 티켓", Description: strings.Join(lines, "\n"), Color: colorYellow, Footer: &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("답변을 기다리는 티켓 %d개 중 오래 기다린 순 %d개", total, len(tickets))}}
+	if len(tickets) == 0 {
+		embed.Description = "답변을 기다리는 티켓이 없습니다."
+		embed.Color = colorGreen
+		embed.Footer = nil
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	urgencyQuestionID = "urgency"
+	urgencyFieldName  = "긴급도"
+)
+
+var urgencyAliases = map[string]string{
+	"1": "낮음", "low": "낮음", "낮음": "낮음",
+	"2": "보통", "normal": "보통", "medium": "보통", "보통": "보통",
+	"3": "높음", "high": "높음", "높음": "높음",
+	"4": "긴급", "urgent": "긴급", "긴급": "긴급",
+}
+
+var urgencyQuestion = intakeQuestion{ID: urgencyQuestionID, Label: "긴급도 (낮음/보통/높음/긴급, 선택)", Placeholder: "보통", Style: discordgo.TextInputShort, Required: false, MaxLength: 10}
+
+func normalizeUrgency(value string) string {
+	return urgencyAliases[strings.ToLower(strings.TrimSpace(value))]
+}
+
+func withUrgencyQuestion(questions []intakeQuestion) []intakeQuestion {
+	if len(questions) >= maxIntakeQuestions {
+		return questions
+	}
+	for _, q := range questions {
+		if q.ID == urgencyQuestionID {
+			return questions
+		}
+	}
+	return append(append([]intakeQuestion(nil), questions...), urgencyQuestion)
+}
+
+func urgencyField(t *ticket) *discordgo.MessageEmbedField {
+	requester, staff := t.RequesterUrgency, t.Priority
+	if requester == "" {
+		requester = "미응답"
+	}
+	if staff == "" {
+		staff = "미지정"
+	}
+	return &discordgo.MessageEmbedField{Name: urgencyFieldName, Value: fmt.Sprintf("민원인 응답: %s · 담당자 지정: %s", requester, staff), Inline: false}
+}
+
+func refreshUrgencyField(s *discordgo.Session, t *ticket) {
+	msg, err := findTicketMessage(s, t.ChannelID)
+	if err != nil || msg == nil {
+		return
+	}
+	embed := msg.Embeds[0]
+	var fields []*discordgo.MessageEmbedField
+	for _, field := range embed.Fields {
+		if field.Name != urgencyFieldName {
+			fields = append(fields, field)
+		}
+	}
+	embed.Fields = append(fields, urgencyField(t))
+	if _, err := s.ChannelMessageEditEmbed(msg.ChannelID, msg.ID, embed); err != nil {
+		log.Printf("Could not update urgency field for '%s': %v", t.Name(), err)
+	}
+}
+
+func urgencyCalibration(tickets []ticket) string {
+	byRequester := make(map[string]map[string]int)
+	for _, t := range tickets {
+		if t.RequesterUrgency == "" || t.Priority == "" {
+			continue
+		}
+		if byRequester[t.RequesterUrgency] == nil {
+			byRequester[t.RequesterUrgency] = make(map[string]int)
+		}
+		byRequester[t.RequesterUrgency][t.Priority]++
+	}
+	var lines []string
+	for _, level := range ticketPriorityChoices {
+		staff := byRequester[level.Value.(string)]
+		if len(staff) == 0 {
+			continue
+		}
+		total := 0
+		var parts []string
+		for _, choice := range ticketPriorityChoices {
+			if n := staff[choice.Value.(string)]; n > 0 {
+				total += n
+				parts = append(parts, fmt.Sprintf("%s %d", choice.Name, n))
+			}
+		}
+		lines = append(lines, fmt.Sprintf("민원인 **%s** %d건 → 담당자 %s (일치 %.0f%%)", level.Name, total, strings.Join(parts, " · "), float64(staff[level.Value.(string)])*100/float64(total)))
+	}
+	if len(lines) == 0 {
+		return "민원인 긴급도와 담당자 우선순위가 모두 기록된 티켓이 없습니다."
+	}
+	return strings.Join(lines, "\n")
+}
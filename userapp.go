@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	myTicketsLimit        = 10
+	myTicketsClosedWindow = 30 * 24 * time.Hour
+)
+
+func userAppCommands() []*discordgo.ApplicationCommand {
+	integrationTypes := []discordgo.ApplicationIntegrationType{discordgo.ApplicationIntegrationGuildInstall, discordgo.ApplicationIntegrationUserInstall}
+	contexts := []discordgo.InteractionContextType{discordgo.InteractionContextGuild, discordgo.InteractionContextBotDM, discordgo.InteractionContextPrivateChannel}
+	return []*discordgo.ApplicationCommand{
+		{Name: "내티켓", Description: "내가 접수한 민원의 처리 상태를 확인합니다.", IntegrationTypes: &integrationTypes, Contexts: &contexts},
+		{Name: "도움말", Description: "민원 접수 방법과 사용할 수 있는 명령어를 안내합니다.", IntegrationTypes: &integrationTypes, Contexts: &contexts},
+	}
+}
+
+func registerUserAppCommands(s *discordgo.Session) {
+	for _, v := range userAppCommands() {
+		if _, err := s.ApplicationCommandCreate(s.State.User.ID, "", v); err != nil {
+			log.Printf("Cannot create global '%v' command: %v", v.Name, err)
+		}
+	}
+}
+
+func interactionUser(i *discordgo.InteractionCreate) *discordgo.User {
+	if i.Member != nil {
+		return i.Member.User
+	}
+	return i.User
+}
+
+func ticketStatusLabel(t ticket) string {
+	switch t.Status {
+	case ticketStatusOpen:
+		if t.AssigneeID != "" {
+			return "처리 중"
+		}
+		return "담당자 배정 대기"
+	case ticketStatusClosed:
+		return "종료"
+	}
+	return "삭제됨"
+}
+
+func handleMyTickets(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	user := interactionUser(i)
+	opts := options.Find().SetSort(bson.M{"created_at": -1}).SetLimit(myTicketsLimit)
+	filter := bson.M{"owner_id": user.ID, "guild_id": app().GuildID, "$or": []bson.M{
+		{"status": ticketStatusOpen},
+		{"status": ticketStatusClosed, "closed_at": bson.M{"$gte": time.Now().Add(-myTicketsClosedWindow)}},
+	}}
+	cursor, err := app().Tickets.Find(context.TODO(), filter, opts)
+	if err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	var tickets []ticket
+	if err := cursor.All(context.TODO(), &tickets); err != nil {
+		respondError(s, i, errTicketFetchFailed, err)
+		return
+	}
+	embed := &discordgo.MessageEmbed{Title: "내 민원", Description: "진행 중인 민원과 최근 30일 안에 종료된 민원입니다.", Color: colorBlue}
+	if len(tickets) == 0 {
+		embed.Description = "진행 중이거나 최근 30일 안에 종료된 민원이 없습니다. 서버의 민원 접수 패널에서 새 민원을 접수할 수 있습니다."
+	}
+	for _, t := range tickets {
+		value := fmt.Sprintf("%s · %s · 접수 <t:%d:f>", t.Code, ticketStatusLabel(t), t.CreatedAt.Unix())
+		if t.Subject != "" {
+			value = t.Subject + "\n" + value
+		}
+		if t.AssigneeID != "" {
+			value += fmt.Sprintf("\n담당자: <@%s>", t.AssigneeID)
+		}
+		if t.Status == ticketStatusOpen {
+			value += fmt.Sprintf("\n[채널로 이동](https://discord.com/channels/%s/%s)", t.GuildID, t.ChannelID)
+		} else if !t.ClosedAt.IsZero() {
+			value += fmt.Sprintf(" · 종료 <t:%d:f>", t.ClosedAt.Unix())
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{Name: fmt.Sprintf("%s #%04d", t.Category, t.Number), Value: value, Inline: false})
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
+
+func handleHelp(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	var topics []string
+	for _, option := range ticketOptions() {
+		line := fmt.Sprintf("**%s**: %s", option.Value, option.Description)
+		if option.Emoji != nil {
+			line = option.Emoji.Name + " " + line
+		}
+		topics = append(topics, line)
+	}
+	embed := &discordgo.MessageEmbed{
+		Title:       "도움말",
+		Description: "서버의 민원 접수 패널에서 창구를 선택하고 양식을 작성하면 전용 티켓 채널이 만들어집니다. 담당자가 배정되면 채널에서 상담이 진행됩니다.",
+		Color:       colorBlue,
+		Fields: []*discordgo.MessageEmbedField{
+			{Name: "민원 창구", Value: strings.Join(topics, "\n"), Inline: false},
+			{Name: "명령어", Value: "`/내티켓` 내가 접수한 민원의 처리 상태를 확인합니다.\n`/도움말` 이 안내를 다시 봅니다.\n`/닫기` 티켓 채널에서 민원을 종료합니다.", Inline: false},
+		},
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{embed}}})
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	verificationChallengePrefix = "verify_challenge:"
+	verificationChallengeTTL    = 5 * time.Minute
+	verificationPassTTL         = time.Hour
+)
+
+var challengeEmojis = []struct{ Emoji, Name string }{
+	{"🍎", "사과"},
+	{"🚗", "자동차"},
+	{"🐶", "강아지"},
+	{"🌙", "달"},
+	{"⚽", "축구공"},
+}
+
+type intakeVerification struct {
+	Enabled           bool   `bson:"enabled"`
+	MinAccountAgeDays int    `bson:"min_account_age_days"`
+	MinMemberDays     int    `bson:"min_member_days,omitempty"`
+	RequiredRoleID    string `bson:"required_role_id,omitempty"`
+	ButtonChallenge   bool   `bson:"button_challenge"`
+	BypassRoleID      string `bson:"bypass_role_id,omitempty"`
+}
+
+type pendingChallenge struct {
+	Answer  string
+	Expires time.Time
+}
+
+var (
+	verificationMu    sync.Mutex
+	pendingChallenges = make(map[string]pendingChallenge)
+	verifiedUntil     = make(map[string]time.Time)
+)
+
+func checkIntakeRequirements(s *discordgo.Session, i *discordgo.InteractionCreate) bool {
+	v := getConfig().Verification
+	if !v.Enabled || (v.BypassRoleID != "" && memberHasRole(i.Member, v.BypassRoleID)) {
+		return true
+	}
+	if v.RequiredRoleID != "" && !memberHasRole(i.Member, v.RequiredRoleID) {
+		respondError(s, i, errVerificationRoleMissing, nil, v.RequiredRoleID)
+		return false
+	}
+	if v.MinAccountAgeDays > 0 {
+		created, err := discordgo.SnowflakeTimestamp(i.Member.User.ID)
+		if err == nil && time.Since(created) < time.Duration(v.MinAccountAgeDays)*24*time.Hour {
+			respondError(s, i, errAccountTooNew, nil, v.MinAccountAgeDays)
+			return false
+		}
+	}
+	if v.MinMemberDays > 0 && !i.Member.JoinedAt.IsZero() {
+		eligible := i.Member.JoinedAt.Add(time.Duration(v.MinMemberDays) * 24 * time.Hour)
+		if time.Now().Before(eligible) {
+			respondError(s, i, errMemberTooNew, nil, v.MinMemberDays, eligible.Unix())
+			return false
+		}
+	}
+	return true
+}
+
+func challengePassed(userID string) bool {
+	if !getConfig().Verification.ButtonChallenge {
+		return true
+	}
+	verificationMu.Lock()
+	defer verificationMu.Unlock()
+	return time.Now().Before(verifiedUntil[userID])
+}
+
+func needsChallenge(i *discordgo.InteractionCreate) bool {
+	v := getConfig().Verification
+	if !v.Enabled || !v.ButtonChallenge {
+		return false
+	}
+	if v.BypassRoleID != "" && memberHasRole(i.Member, v.BypassRoleID) {
+		return false
+	}
+	return !challengePassed(i.Member.User.ID)
+}
+
+func sendVerificationChallenge(s *discordgo.Session, i *discordgo.InteractionCreate, topic string) {
+	order := rand.Perm(len(challengeEmojis))
+	answer := challengeEmojis[order[0]]
+	verificationMu.Lock()
+	pendingChallenges[i.Member.User.ID] = pendingChallenge{Answer: answer.Emoji, Expires: time.Now().Add(verificationChallengeTTL)}
+	verificationMu.Unlock()
+	var buttons []discordgo.MessageComponent
+	for _, n := range rand.Perm(len(challengeEmojis)) {
+		e := challengeEmojis[n]
+		buttons = append(buttons, discordgo.Button{Emoji: &discordgo.ComponentEmoji{Name: e.Emoji}, Style: discordgo.SecondaryButton, CustomID: verificationChallengePrefix + topic + ":" + e.Emoji})
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Flags: discordgo.MessageFlagsEphemeral, Embeds: []*discordgo.MessageEmbed{{Title: "본인 확인", Description: fmt.Sprintf("민원 접수 전 간단한 확인이 필요합니다.\n아래 버튼 중 **%s** 그림을 눌러주세요.", answer.Name), Color: colorBlue}}, Components: []discordgo.MessageComponent{discordgo.ActionsRow{Components: buttons}}}})
+}
+
+func handleVerificationChallenge(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	parts := strings.SplitN(strings.TrimPrefix(i.MessageComponentData().CustomID, verificationChallengePrefix), ":", 2)
+	if len(parts) != 2 {
+		return
+	}
+	topic, choice := parts[0], parts[1]
+	userID := i.Member.User.ID
+	verificationMu.Lock()
+	challenge, ok := pendingChallenges[userID]
+	delete(pendingChallenges, userID)
+	passed := ok && time.Now().Before(challenge.Expires) && challenge.Answer == choice
+	if passed {
+		verifiedUntil[userID] = time.Now().Add(verificationPassTTL)
+	}
+	verificationMu.Unlock()
+	if !passed {
+		respondError(s, i, errVerificationFailed, nil)
+		return
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseModal, Data: intakeModal(topic)})
+}
+
+func verificationSummary(v intakeVerification) string {
+	if !v.Enabled {
+		return "사용 안 함"
+	}
+	var parts []string
+	if v.MinAccountAgeDays > 0 {
+		parts = append(parts, fmt.Sprintf("계정 생성 %d일 이상", v.MinAccountAgeDays))
+	}
+	if v.MinMemberDays > 0 {
+		parts = append(parts, fmt.Sprintf("서버 참여 %d일 이상", v.MinMemberDays))
+	}
+	if v.RequiredRoleID != "" {
+		parts = append(parts, fmt.Sprintf("<@&%s> 역할 필요", v.RequiredRoleID))
+	}
+	if v.ButtonChallenge {
+		parts = append(parts, "버튼 확인")
+	}
+	if v.BypassRoleID != "" {
+		parts = append(parts, fmt.Sprintf("<@&%s> 면제", v.BypassRoleID))
+	}
+	if len(parts) == 0 {
+		return "사용 (조건 없음)"
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type voiceEvent struct {
+	At       time.Time `bson:"at"`
+	UserID   string    `bson:"user_id"`
+	UserName string    `bson:"user_name"`
+	Joined   bool      `bson:"joined"`
+}
+
+func handleVoiceSession(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	t := requireTicket(s, i)
+	if t == nil {
+		return
+	}
+	if t.Status != ticketStatusOpen {
+		respondError(s, i, errTicketNotOpen, nil)
+		return
+	}
+	if t.VoiceChannelID != "" {
+		if _, err := s.Channel(t.VoiceChannelID); err == nil {
+			s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Embeds: []*discordgo.MessageEmbed{{Title: "음성 상담", Description: fmt.Sprintf("이미 연결된 음성 채널이 있습니다: <#%s>", t.VoiceChannelID), Color: colorBlue}}}})
+			return
+		}
+	}
+	cfg := getConfig()
+	supportRoleID, ok := cfg.CategorySupportRoles[t.Category]
+	if !ok {
+		supportRoleID = cfg.DefaultSupportRoleID
+	}
+	overwrites := []*discordgo.PermissionOverwrite{
+		{ID: i.GuildID, Type: discordgo.PermissionOverwriteTypeRole, Deny: discordgo.PermissionViewChannel},
+		{ID: t.OwnerID, Type: discordgo.PermissionOverwriteTypeMember, Allow: discordgo.PermissionViewChannel | discordgo.PermissionVoiceConnect | discordgo.PermissionVoiceSpeak},
+		{ID: supportRoleID, Type: discordgo.PermissionOverwriteTypeRole, Allow: discordgo.PermissionViewChannel | discordgo.PermissionVoiceConnect | discordgo.PermissionVoiceSpeak},
+	}
+	for _, id := range t.Participants {
+		overwrites = append(overwrites, &discordgo.PermissionOverwrite{ID: id, Type: discordgo.PermissionOverwriteTypeMember, Allow: discordgo.PermissionViewChannel | discordgo.PermissionVoiceConnect | discordgo.PermissionVoiceSpeak})
+	}
+	vc, err := s.GuildChannelCreateComplex(i.GuildID, discordgo.GuildChannelCreateData{
+		Name:                 t.Name() + "-음성",
+		Type:                 discordgo.ChannelTypeGuildVoice,
+		ParentID:             cfg.OpenCategoryID,
+		PermissionOverwrites: overwrites,
+	})
+	if err != nil {
+		respondError(s, i, errChannelCreateFailed, err)
+		return
+	}
+	if err := updateTicket(t.ChannelID, bson.M{"$set": bson.M{"voice_channel_id": vc.ID}}); err != nil {
+		s.ChannelDelete(vc.ID)
+		respondError(s, i, errTicketSaveFailed, err)
+		return
+	}
+	description := fmt.Sprintf("<#%s> 음성 채널을 만들었습니다. 티켓이 닫히면 함께 삭제됩니다.", vc.ID)
+	if cfg.RecordVoiceSessions {
+		description += "\n입장 및 퇴장 시각이 기록되어 대화록에 포함됩니다."
+	}
+	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{Type: discordgo.InteractionResponseChannelMessageWithSource, Data: &discordgo.InteractionResponseData{Content: fmt.Sprintf("<@%s>", t.OwnerID), Embeds: []*discordgo.MessageEmbed{{Title: "음성 상담", Description: description, Color: colorGreen}}}})
+}
+
+func voiceStateUpdate(s *discordgo.Session, v *discordgo.VoiceStateUpdate) {
+	if !getConfig().RecordVoiceSessions || v.Member == nil || v.Member.User.Bot {
+		return
+	}
+	before := ""
+	if v.BeforeUpdate != nil {
+		before = v.BeforeUpdate.ChannelID
+	}
+	if before == v.ChannelID {
+		return
+	}
+	now := time.Now()
+	if before != "" {
+		recordVoiceEvent(before, voiceEvent{At: now, UserID: v.UserID, UserName: memberDisplayName(v.Member), Joined: false})
+	}
+	if v.ChannelID != "" {
+		recordVoiceEvent(v.ChannelID, voiceEvent{At: now, UserID: v.UserID, UserName: memberDisplayName(v.Member), Joined: true})
+	}
+}
+
+func recordVoiceEvent(voiceChannelID string, e voiceEvent) {
+	if app().Tickets == nil {
+		return
+	}
+	_, err := app().Tickets.UpdateOne(context.TODO(), bson.M{"voice_channel_id": voiceChannelID}, bson.M{"$push": bson.M{"voice_events": e}})
+	if err != nil {
+		log.Printf("Could not record voice event in %s: %v", voiceChannelID, err)
+	}
+}
+
+func closeVoiceSession(s *discordgo.Session, t *ticket) {
+	if t.VoiceChannelID == "" {
+		return
+	}
+	if _, err := s.ChannelDelete(t.VoiceChannelID); err != nil {
+		log.Printf("Could not delete voice channel of '%s': %v", t.Name(), err)
+	}
+}
+
+func voiceSessionHTML(t *ticket) string {
+	if len(t.VoiceEvents) == 0 {
+		return ""
+	}
+	joinedAt := make(map[string]time.Time)
+	totals := make(map[string]time.Duration)
+	names := make(map[string]string)
+	var lines []string
+	for _, e := range t.VoiceEvents {
+		names[e.UserID] = e.UserName
+		action := "퇴장"
+		if e.Joined {
+			action = "입장"
+			joinedAt[e.UserID] = e.At
+		} else if start, ok := joinedAt[e.UserID]; ok {
+			totals[e.UserID] += e.At.Sub(start)
+			delete(joinedAt, e.UserID)
+		}
+		lines = append(lines, fmt.Sprintf("%s · %s 님 %s", e.At.In(kstLocation).Format("2006-01-02 15:04:05"), e.UserName, action))
+	}
+	last := t.VoiceEvents[len(t.VoiceEvents)-1].At
+	for id, start := range joinedAt {
+		totals[id] += last.Sub(start)
+	}
+	users := make([]string, 0, len(totals))
+	for id := range totals {
+		users = append(users, id)
+	}
+	sort.Slice(users, func(a, b int) bool { return totals[users[a]] > totals[users[b]] })
+	var summary []string
+	for _, id := range users {
+		summary = append(summary, fmt.Sprintf("%s: %s", names[id], formatWait(totals[id])))
+	}
+	var sb strings.Builder
+	sb.WriteString(`<div class="embed"><div class="embed-content"><div class="embed-title">음성 상담 기록</div>`)
+	sb.WriteString(fmt.Sprintf(`<div class="embed-description">%s ~ %s</div>`, t.VoiceEvents[0].At.In(kstLocation).Format("2006-01-02 15:04"), last.In(kstLocation).Format("15:04")))
+	sb.WriteString(`<div class="embed-field-name">참여 시간</div><div class="embed-description">` + html.EscapeString(strings.Join(summary, "\n")) + `</div>`)
+	sb.WriteString(`<div class="embed-field-name">입장/퇴장</div><div class="embed-description">` + html.EscapeString(strings.Join(lines, "\n")) + `</div>`)
+	sb.WriteString(`</div></div>`)
+	return sb.String()
+}